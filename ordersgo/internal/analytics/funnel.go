@@ -0,0 +1,113 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+)
+
+// stages pasos del embudo que se miden
+var stages = [][2]status.MilestoneType{
+	{status.Placed, status.Validated},
+	{status.Validated, status.FullyPaid},
+	{status.Placed, status.FullyPaid},
+	{status.Placed, status.Canceled},
+	{status.FullyPaid, status.Refunded},
+}
+
+func newFunnel(from time.Time, to time.Time, orders []*status.OrderStatus) *Funnel {
+	funnel := &Funnel{
+		From:   from.UTC(),
+		To:     to.UTC(),
+		Stages: []*Stage{},
+	}
+
+	for _, order := range orders {
+		funnel.Placed++
+		if order.Reached(status.Validated) {
+			funnel.Validated++
+		}
+		if order.Reached(status.Invalid) {
+			funnel.Invalid++
+		}
+		if order.Reached(status.FullyPaid) {
+			funnel.Paid++
+		}
+		if order.Reached(status.Canceled) {
+			funnel.Canceled++
+		}
+		if order.Reached(status.Refunded) {
+			funnel.Refunded++
+		}
+	}
+
+	for _, stage := range stages {
+		funnel.Stages = append(funnel.Stages, newStage(stage[0], stage[1], orders))
+	}
+
+	return funnel
+}
+
+func newStage(from status.MilestoneType, to status.MilestoneType, orders []*status.OrderStatus) *Stage {
+	stage := &Stage{
+		From: from,
+		To:   to,
+	}
+
+	reached := 0
+	durations := []float64{}
+	for _, order := range orders {
+		start := reachedAt(order, from)
+		if start == nil {
+			continue
+		}
+		reached++
+
+		end := reachedAt(order, to)
+		if end == nil {
+			continue
+		}
+		durations = append(durations, end.Sub(*start).Seconds())
+	}
+
+	stage.Orders = len(durations)
+	if reached > 0 {
+		stage.Conversion = float32(stage.Orders) / float32(reached)
+	}
+	if len(durations) == 0 {
+		return stage
+	}
+
+	sort.Float64s(durations)
+	var total float64
+	for _, d := range durations {
+		total += d
+	}
+	stage.AverageSeconds = total / float64(len(durations))
+	stage.MedianSeconds = percentile(durations, 0.5)
+	stage.P90Seconds = percentile(durations, 0.9)
+	return stage
+}
+
+// reachedAt fecha del primer hito del tipo
+func reachedAt(order *status.OrderStatus, milestone status.MilestoneType) *time.Time {
+	for _, m := range order.Milestones {
+		if m.Type == milestone {
+			return &m.Time
+		}
+	}
+	return nil
+}
+
+// percentile por rango más cercano, sorted ordenado de menor a mayor
+func percentile(sorted []float64, p float64) float64 {
+	index := int(float64(len(sorted))*p+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
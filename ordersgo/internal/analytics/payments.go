@@ -0,0 +1,95 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// unknownErrorCode agrupa los rechazos que llegaron sin código
+const unknownErrorCode = "unknown"
+
+// newPaymentFailures cada pago capturado o rechazado es un intento, las demás transiciones no cuentan.
+// Un mismo pago recibido dos veces con el mismo estado se cuenta una vez.
+func newPaymentFailures(from time.Time, to time.Time, payments []*events.Event) *PaymentFailures {
+	result := &PaymentFailures{
+		From:        from.UTC(),
+		To:          to.UTC(),
+		ByErrorCode: []*ErrorCodeFailures{},
+		ByMethod:    []*MethodFailures{},
+	}
+
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].Created.Before(payments[j].Created)
+	})
+
+	seen := map[string]bool{}
+	codes := map[string]*ErrorCodeFailures{}
+	methods := map[string]*MethodFailures{}
+	for _, e := range payments {
+		payment := e.Payment
+		status := payment.Status.Normalize()
+		if status != events.PaymentCaptured && status != events.PaymentRejected {
+			continue
+		}
+		key := payment.PaymentId + "/" + string(status)
+		if payment.PaymentId != "" && seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		method, ok := methods[payment.Method]
+		if !ok {
+			method = &MethodFailures{Method: payment.Method}
+			methods[payment.Method] = method
+			result.ByMethod = append(result.ByMethod, method)
+		}
+		result.Attempts++
+		method.Attempts++
+
+		if status != events.PaymentRejected {
+			continue
+		}
+		result.Rejected++
+		method.Rejected++
+
+		errorCode := payment.ErrorCode
+		if errorCode == "" {
+			errorCode = unknownErrorCode
+		}
+		code, ok := codes[errorCode]
+		if !ok {
+			code = &ErrorCodeFailures{ErrorCode: errorCode}
+			codes[errorCode] = code
+			result.ByErrorCode = append(result.ByErrorCode, code)
+		}
+		code.Count++
+		code.Amount += payment.Amount
+		if payment.ErrorMessage != "" {
+			code.LastReason = payment.ErrorMessage
+		}
+	}
+
+	if result.Attempts > 0 {
+		result.RejectionRate = float32(result.Rejected) / float32(result.Attempts)
+	}
+	for _, method := range result.ByMethod {
+		method.RejectionRate = float32(method.Rejected) / float32(method.Attempts)
+	}
+
+	sort.Slice(result.ByErrorCode, func(i, j int) bool {
+		if result.ByErrorCode[i].Count != result.ByErrorCode[j].Count {
+			return result.ByErrorCode[i].Count > result.ByErrorCode[j].Count
+		}
+		return result.ByErrorCode[i].ErrorCode < result.ByErrorCode[j].ErrorCode
+	})
+	sort.Slice(result.ByMethod, func(i, j int) bool {
+		if result.ByMethod[i].Rejected != result.ByMethod[j].Rejected {
+			return result.ByMethod[i].Rejected > result.ByMethod[j].Rejected
+		}
+		return result.ByMethod[i].Method < result.ByMethod[j].Method
+	})
+
+	return result
+}
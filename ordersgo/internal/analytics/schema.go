@@ -0,0 +1,58 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+)
+
+// Funnel embudo de las ordenes colocadas entre From (incluido) y To (excluido)
+type Funnel struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Placed    int       `json:"placed"`
+	Validated int       `json:"validated"`
+	Invalid   int       `json:"invalid"`
+	Paid      int       `json:"paid"`
+	Canceled  int       `json:"canceled"`
+	Refunded  int       `json:"refunded"`
+	Stages    []*Stage  `json:"stages"`
+}
+
+// Stage paso entre dos hitos, Orders son las ordenes que alcanzaron ambos.
+// Conversion es Orders sobre las ordenes que alcanzaron From, las duraciones en segundos.
+type Stage struct {
+	From           status.MilestoneType `json:"from"`
+	To             status.MilestoneType `json:"to"`
+	Orders         int                  `json:"orders"`
+	Conversion     float32              `json:"conversion"`
+	AverageSeconds float64              `json:"averageSeconds"`
+	MedianSeconds  float64              `json:"medianSeconds"`
+	P90Seconds     float64              `json:"p90Seconds"`
+}
+
+// PaymentFailures pagos aprobados y rechazados entre From (incluido) y To (excluido)
+type PaymentFailures struct {
+	From          time.Time            `json:"from"`
+	To            time.Time            `json:"to"`
+	Attempts      int                  `json:"attempts"`
+	Rejected      int                  `json:"rejected"`
+	RejectionRate float32              `json:"rejectionRate"`
+	ByErrorCode   []*ErrorCodeFailures `json:"byErrorCode"`
+	ByMethod      []*MethodFailures    `json:"byMethod"`
+}
+
+// ErrorCodeFailures rechazos con un mismo código, LastReason es el último mensaje recibido
+type ErrorCodeFailures struct {
+	ErrorCode  string  `json:"errorCode"`
+	Count      int     `json:"count"`
+	Amount     float32 `json:"amount"`
+	LastReason string  `json:"lastReason"`
+}
+
+type MethodFailures struct {
+	Method        string  `json:"method"`
+	Attempts      int     `json:"attempts"`
+	Rejected      int     `json:"rejected"`
+	RejectionRate float32 `json:"rejectionRate"`
+}
@@ -0,0 +1,64 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+)
+
+// AnalyticsService reportes calculados al consultar a partir de status_projection y del event store
+type AnalyticsService interface {
+	Funnel(from time.Time, to time.Time) (*Funnel, error)
+	PaymentFailures(from time.Time, to time.Time) (*PaymentFailures, error)
+}
+
+func NewAnalyticsService(log log.LogRusEntry, status status.StatusService, events events.EventService) AnalyticsService {
+	return &analyticsService{
+		log:    log,
+		status: status,
+		events: events,
+	}
+}
+
+type analyticsService struct {
+	log    log.LogRusEntry
+	status status.StatusService
+	events events.EventService
+}
+
+// Funnel las ordenes se seleccionan por fecha de colocación, los hitos pueden ser posteriores a To
+func (s *analyticsService) Funnel(from time.Time, to time.Time) (*Funnel, error) {
+	if err := validateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	orders, err := s.status.FindByCreated(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFunnel(from, to, orders), nil
+}
+
+func (s *analyticsService) PaymentFailures(from time.Time, to time.Time) (*PaymentFailures, error) {
+	if err := validateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	payments, err := s.events.FindPayments(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPaymentFailures(from, to, payments), nil
+}
+
+func validateRange(from time.Time, to time.Time) error {
+	if !from.Before(to) {
+		return errs.NewValidation().Add("from", "must be before to")
+	}
+	return nil
+}
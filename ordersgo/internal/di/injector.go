@@ -1,33 +1,89 @@
 package di
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/nmarsollier/commongo/db"
 	"github.com/nmarsollier/commongo/httpx"
 	"github.com/nmarsollier/commongo/log"
-	"github.com/nmarsollier/commongo/rbt"
 	"github.com/nmarsollier/commongo/security"
+	"github.com/nmarsollier/ordersgo/internal/analytics"
 	"github.com/nmarsollier/ordersgo/internal/env"
 	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/idempotency"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/messages"
+	"github.com/nmarsollier/ordersgo/internal/metrics"
+	"github.com/nmarsollier/ordersgo/internal/mongodb"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
 	"github.com/nmarsollier/ordersgo/internal/projections"
+	"github.com/nmarsollier/ordersgo/internal/projections/article"
+	"github.com/nmarsollier/ordersgo/internal/projections/checkpoint"
+	"github.com/nmarsollier/ordersgo/internal/projections/customer"
 	"github.com/nmarsollier/ordersgo/internal/projections/order"
+	"github.com/nmarsollier/ordersgo/internal/projections/sales"
 	"github.com/nmarsollier/ordersgo/internal/projections/status"
+	"github.com/nmarsollier/ordersgo/internal/projections/version"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
 	"github.com/nmarsollier/ordersgo/internal/rabbit/rbschema"
+	"github.com/nmarsollier/ordersgo/internal/reconciliation"
 	"github.com/nmarsollier/ordersgo/internal/services"
+	"github.com/nmarsollier/ordersgo/internal/telemetry"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
 )
 
 // Singletons
 var database *mongo.Database
+var postgres *pgxpool.Pool
+var postgresMutex sync.Mutex
+var databaseMutex sync.Mutex
+var closeDatabaseOnce sync.Once
 var httpClient httpx.HTTPClient
 var eventsCollection db.Collection
 var ordersCollection db.Collection
 var statusCollection db.Collection
+var customerCollection db.Collection
+var salesCollection db.Collection
+var articlesCollection db.Collection
+var reportsCollection db.Collection
+var messagesCollection db.Collection
+var idempotencyCollection db.Collection
+var checkpointsCollection db.Collection
+var versionsCollection db.Collection
+var currentMetrics *metrics.Metrics
+var dispatcher *projections.Dispatcher
+var dispatcherMutex sync.Mutex
+
+// Tablas del backend en memoria, compartidas por todos los injectors
+var memoryEvents = memdb.NewTable[events.Event]()
+var memoryOrders = memdb.NewTable[order.Order]()
+var memoryStatus = memdb.NewTable[status.OrderStatus]()
+var memoryCustomers = memdb.NewTable[customer.OrderStats]()
+var memorySales = memdb.NewTable[sales.OrderSales]()
+var memoryArticles = memdb.NewTable[article.OrderArticles]()
+var memoryReports = memdb.NewTable[reconciliation.Report]()
+var memoryMessages = memdb.NewTable[messages.ProcessedMessage]()
+var memoryIdempotency = memdb.NewTable[idempotency.Record]()
+var memoryCheckpoints = memdb.NewTable[checkpoint.Checkpoint]()
+var memoryVersions = memdb.NewTable[version.Version]()
+var metricsMutex sync.Mutex
 
 type Injector interface {
+	Context() context.Context
 	Logger() log.LogRusEntry
+	Metrics() *metrics.Metrics
 	Database() *mongo.Database
+	Postgres() *pgxpool.Pool
 	HttpClient() httpx.HTTPClient
 	SecurityRepository() security.SecurityRepository
 	SecurityService() security.SecurityService
@@ -40,31 +96,95 @@ type Injector interface {
 	StatusCollection() db.Collection
 	StatusRepository() status.StatusRepository
 	StatusService() status.StatusService
+	CustomerCollection() db.Collection
+	CustomerRepository() customer.CustomerRepository
+	CustomerService() customer.CustomerService
+	SalesCollection() db.Collection
+	SalesRepository() sales.SalesRepository
+	SalesService() sales.SalesService
+	ArticlesCollection() db.Collection
+	ArticleRepository() article.ArticleRepository
+	ArticleService() article.ArticleService
+	AnalyticsService() analytics.AnalyticsService
+	PaymentsClient() reconciliation.PaymentsClient
+	ReportsCollection() db.Collection
+	ReportRepository() reconciliation.ReportRepository
+	ReconciliationService() reconciliation.ReconciliationService
+	MessagesCollection() db.Collection
+	MessagesRepository() messages.MessagesRepository
+	MessagesService() messages.MessagesService
+	IdempotencyCollection() db.Collection
+	IdempotencyRepository() idempotency.IdempotencyRepository
+	IdempotencyService() idempotency.IdempotencyService
 	ProjectionsService() projections.ProjectionsService
+	ProjectionRegistry() *projections.Registry
+	VersionsCollection() db.Collection
+	VersionRepository() version.VersionRepository
+	ProjectionDispatcher() *projections.Dispatcher
+	EventFeed() events.EventFeed
+	CheckpointsCollection() db.Collection
+	CheckpointRepository() checkpoint.CheckpointRepository
+	ProjectionRunner() *projections.Runner
 	Service() services.Service
+	Transaction() services.Transaction
 	ArticleValidationPublisher() rbschema.ArticleValidationPublisher
 	PlacedOrderPublisher() rbschema.PlacedDataPublisher
+	RefundRequestPublisher() rbschema.RefundRequestPublisher
 }
 
 type Deps struct {
+	CurrCtx         context.Context
 	CurrLog         log.LogRusEntry
+	CurrMetrics     *metrics.Metrics
 	CurrHttpClient  httpx.HTTPClient
 	CurrDatabase    *mongo.Database
+	CurrPostgres    *pgxpool.Pool
 	CurrSecRepo     security.SecurityRepository
 	CurrSecSvc      security.SecurityService
 	CurrEvtColl     db.Collection
 	CurrOrdColl     db.Collection
 	CurrStaColl     db.Collection
+	CurrMsgColl     db.Collection
 	CurrEvtRepo     events.EventsRepository
 	CurrOrdRepo     order.OrderRepository
 	CurrOrdSvc      order.OrderService
 	CurrEvtSvc      events.EventService
 	CurrStsRepo     status.StatusRepository
 	CurrStsSvc      status.StatusService
+	CurrCusColl     db.Collection
+	CurrCusRepo     customer.CustomerRepository
+	CurrCusSvc      customer.CustomerService
+	CurrSalColl     db.Collection
+	CurrSalRepo     sales.SalesRepository
+	CurrSalSvc      sales.SalesService
+	CurrArtColl     db.Collection
+	CurrArtRepo     article.ArticleRepository
+	CurrArtSvc      article.ArticleService
+	CurrAnaSvc      analytics.AnalyticsService
+	CurrPayClient   reconciliation.PaymentsClient
+	CurrRepColl     db.Collection
+	CurrRepRepo     reconciliation.ReportRepository
+	CurrRecSvc      reconciliation.ReconciliationService
+	CurrMsgRepo     messages.MessagesRepository
+	CurrMsgSvc      messages.MessagesService
+	CurrIdmColl     db.Collection
+	CurrIdmRepo     idempotency.IdempotencyRepository
+	CurrIdmSvc      idempotency.IdempotencyService
 	CurrPrjSvc      projections.ProjectionsService
+	CurrPrjReg      *projections.Registry
+	CurrVerColl     db.Collection
+	CurrVerRepo     version.VersionRepository
+	CurrDispatcher  *projections.Dispatcher
+	CurrEvtFeed     events.EventFeed
+	CurrChkColl     db.Collection
+	CurrChkRepo     checkpoint.CheckpointRepository
+	CurrRunner      *projections.Runner
 	CurrSvc         services.Service
+	CurrTx          services.Transaction
+	CurrPgTx        pgx.Tx
 	CurrAVPublisher rbschema.ArticleValidationPublisher
 	CurrPLPublisher rbschema.PlacedDataPublisher
+	CurrRRPublisher rbschema.RefundRequestPublisher
 }
 
 func NewInjector(log log.LogRusEntry) Injector {
@@ -73,28 +193,149 @@ func NewInjector(log log.LogRusEntry) Injector {
 	}
 }
 
+// NewInjectorWithContext asocia las dependencias a la traza de ctx
+func NewInjectorWithContext(ctx context.Context, log log.LogRusEntry) Injector {
+	return &Deps{
+		CurrCtx: ctx,
+		CurrLog: log,
+	}
+}
+
+// Context contexto de la operación en curso, se usa para propagar la traza
+func (i *Deps) Context() context.Context {
+	if i.CurrCtx != nil {
+		return i.CurrCtx
+	}
+	return context.Background()
+}
+
 func (i *Deps) Logger() log.LogRusEntry {
 	return i.CurrLog
 }
 
+// Metrics los colectores son únicos por proceso, los tests pueden inyectar los propios
+func (i *Deps) Metrics() *metrics.Metrics {
+	if i.CurrMetrics != nil {
+		return i.CurrMetrics
+	}
+
+	metricsMutex.Lock()
+	defer metricsMutex.Unlock()
+
+	if currentMetrics != nil {
+		return currentMetrics
+	}
+
+	currentMetrics = metrics.New()
+	currentMetrics.RegisterOrdersByStatus(func() (map[string]int64, error) {
+		deps := NewInjector(i.Logger())
+
+		// Sin mongo no se puede crear la colección, no debe tirar el proceso en un scrape
+		if env.Get().StorageBackend == env.MongoStorage {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			if err := deps.Database().Client().Ping(ctx, nil); err != nil {
+				return nil, err
+			}
+		}
+
+		counts, err := deps.OrderService().CountByStatus()
+		if err != nil {
+			return nil, err
+		}
+
+		result := map[string]int64{}
+		for status, count := range counts {
+			result[string(status)] = count
+		}
+		return result, nil
+	})
+
+	return currentMetrics
+}
+
 func (i *Deps) Database() *mongo.Database {
 	if i.CurrDatabase != nil {
 		return i.CurrDatabase
 	}
 
+	databaseMutex.Lock()
+	defer databaseMutex.Unlock()
+
 	if database != nil {
 		return database
 	}
 
-	database, err := db.NewDatabase(env.Get().MongoURL, "orders")
+	newDatabase, err := db.NewDatabase(env.Get().MongoURL, "orders")
 	if err != nil {
 		i.CurrLog.Fatal(err)
 		return nil
 	}
+	database = newDatabase
+
+	closeDatabaseOnce.Do(func() {
+		lifecycle.OnShutdown(lifecycle.Connections, "mongo", CloseDatabase)
+	})
 
 	return database
 }
 
+// CloseDatabase desconecta el cliente de mongo
+func CloseDatabase(ctx context.Context) error {
+	databaseMutex.Lock()
+	defer databaseMutex.Unlock()
+
+	if database == nil {
+		return nil
+	}
+
+	err := database.Client().Disconnect(ctx)
+	database = nil
+	return err
+}
+
+// Postgres pool compartido, al conectar aplica las migraciones pendientes
+func (i *Deps) Postgres() *pgxpool.Pool {
+	if i.CurrPostgres != nil {
+		return i.CurrPostgres
+	}
+
+	postgresMutex.Lock()
+	defer postgresMutex.Unlock()
+
+	if postgres != nil {
+		return postgres
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := pgdb.Connect(ctx, i.Logger(), env.Get().PostgresURL)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+	postgres = pool
+
+	lifecycle.OnShutdown(lifecycle.Connections, "postgres", ClosePostgres)
+
+	return postgres
+}
+
+// ClosePostgres cierra las conexiones del pool
+func ClosePostgres(ctx context.Context) error {
+	postgresMutex.Lock()
+	defer postgresMutex.Unlock()
+
+	if postgres == nil {
+		return nil
+	}
+
+	postgres.Close()
+	postgres = nil
+	return nil
+}
+
 func (i *Deps) HttpClient() httpx.HTTPClient {
 	if i.CurrHttpClient != nil {
 		return i.CurrHttpClient
@@ -130,22 +371,80 @@ func (i *Deps) EventsCollection() db.Collection {
 	}
 
 	if eventsCollection != nil {
-		return eventsCollection
+		return i.traced("events", eventsCollection)
 	}
 
-	cartCollection, err := db.NewCollection(i.CurrLog, i.Database(), "events", IsDbTimeoutError, "orderId")
+	cartCollection, err := mongodb.NewCollection(i.CurrLog, i.Database(), "events", IsDbTimeoutError, "orderId", "created")
 	if err != nil {
 		i.CurrLog.Fatal(err)
 		return nil
 	}
-	return cartCollection
+
+	// Un solo place_order por carrito, cierra la carrera entre el find y el insert
+	err = i.createIndexes("events", mongo.IndexModel{
+		Keys: bson.D{{Key: "placeEvent.cartId", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"type": events.Place}),
+	})
+	if err != nil {
+		// Si hay duplicados previos no se puede crear, se sigue sin el indice
+		i.CurrLog.Error(err)
+	}
+
+	// Un solo evento inicial por pago, cierra la carrera de los pagos manuales con la misma Idempotency-Key
+	err = i.createIndexes("events", mongo.IndexModel{
+		Keys: bson.D{{Key: "orderId", Value: 1}, {Key: "payment.paymentId", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{
+				"type":              events.Payment,
+				"payment.status":    "",
+				"payment.paymentId": bson.M{"$gt": ""},
+			}),
+	})
+	if err != nil {
+		i.CurrLog.Error(err)
+	}
+
+	// Una sola validación por artículo de la orden
+	err = i.createIndexes("events", mongo.IndexModel{
+		Keys: bson.D{{Key: "orderId", Value: 1}, {Key: "validation.articleId", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"type": events.Validation}),
+	})
+	if err != nil {
+		i.CurrLog.Error(err)
+	}
+
+	// Un solo reembolso por número en cada pago, serializa los reembolsos concurrentes
+	err = i.createIndexes("events", mongo.IndexModel{
+		Keys: bson.D{{Key: "orderId", Value: 1}, {Key: "refund.paymentId", Value: 1}, {Key: "refund.sequence", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"type": events.Refund, "refund.sequence": bson.M{"$gt": 0}}),
+	})
+	if err != nil {
+		i.CurrLog.Error(err)
+	}
+
+	eventsCollection = cartCollection
+	return i.traced("events", eventsCollection)
 }
 
 func (i *Deps) EventsRepository() events.EventsRepository {
 	if i.CurrEvtRepo != nil {
 		return i.CurrEvtRepo
 	}
-	i.CurrEvtRepo = events.NewEventsRepository(i.Logger(), i.EventsCollection())
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrEvtRepo = events.NewMemoryEventsRepository(i.Logger(), memoryEvents)
+	case env.PostgresStorage:
+		i.CurrEvtRepo = events.NewPostgresEventsRepository(i.Logger(), i.postgresDB())
+	default:
+		i.CurrEvtRepo = events.NewEventsRepository(i.Logger(), i.EventsCollection())
+	}
 	return i.CurrEvtRepo
 }
 
@@ -153,7 +452,7 @@ func (i *Deps) EventService() events.EventService {
 	if i.CurrEvtSvc != nil {
 		return i.CurrEvtSvc
 	}
-	i.CurrEvtSvc = events.NewEventService(i.Logger(), i.EventsRepository())
+	i.CurrEvtSvc = events.NewEventService(i.Logger(), i.Metrics(), i.EventsRepository())
 	return i.CurrEvtSvc
 }
 
@@ -163,15 +462,16 @@ func (i *Deps) OrdersCollection() db.Collection {
 	}
 
 	if ordersCollection != nil {
-		return ordersCollection
+		return i.traced("order_projection", ordersCollection)
 	}
 
-	cartCollection, err := db.NewCollection(i.CurrLog, i.Database(), "order_projection", IsDbTimeoutError, "orderId")
+	cartCollection, err := mongodb.NewCollection(i.CurrLog, i.Database(), "order_projection", IsDbTimeoutError, "orderId")
 	if err != nil {
 		i.CurrLog.Fatal(err)
 		return nil
 	}
-	return cartCollection
+	ordersCollection = cartCollection
+	return i.traced("order_projection", ordersCollection)
 }
 
 func (i *Deps) StatusCollection() db.Collection {
@@ -180,22 +480,266 @@ func (i *Deps) StatusCollection() db.Collection {
 	}
 
 	if statusCollection != nil {
-		return statusCollection
+		return i.traced("status_projection", statusCollection)
+	}
+
+	cartCollection, err := mongodb.NewCollection(i.CurrLog, i.Database(), "status_projection", IsDbTimeoutError, "orderId", "created")
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+	statusCollection = cartCollection
+	return i.traced("status_projection", statusCollection)
+}
+
+func (i *Deps) CustomerCollection() db.Collection {
+	if i.CurrCusColl != nil {
+		return i.CurrCusColl
+	}
+
+	if customerCollection != nil {
+		return i.traced("customer_projection", customerCollection)
+	}
+
+	collection, err := mongodb.NewCollection(i.CurrLog, i.Database(), "customer_projection", IsDbTimeoutError, "userId")
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	err = i.createIndexes("customer_projection",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "orderId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	customerCollection = collection
+	return i.traced("customer_projection", customerCollection)
+}
+
+func (i *Deps) SalesCollection() db.Collection {
+	if i.CurrSalColl != nil {
+		return i.CurrSalColl
+	}
+
+	if salesCollection != nil {
+		return i.traced("sales_projection", salesCollection)
+	}
+
+	collection, err := mongodb.NewCollection(i.CurrLog, i.Database(), "sales_projection", IsDbTimeoutError)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	err = i.createIndexes("sales_projection",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "orderId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "first", Value: 1}, {Key: "last", Value: 1}},
+		},
+	)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	salesCollection = collection
+	return i.traced("sales_projection", salesCollection)
+}
+
+func (i *Deps) ArticlesCollection() db.Collection {
+	if i.CurrArtColl != nil {
+		return i.CurrArtColl
+	}
+
+	if articlesCollection != nil {
+		return i.traced("article_projection", articlesCollection)
+	}
+
+	collection, err := mongodb.NewCollection(i.CurrLog, i.Database(), "article_projection", IsDbTimeoutError, "articles.articleId")
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	err = i.createIndexes("article_projection",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "orderId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	articlesCollection = collection
+	return i.traced("article_projection", articlesCollection)
+}
+
+func (i *Deps) ReportsCollection() db.Collection {
+	if i.CurrRepColl != nil {
+		return i.CurrRepColl
+	}
+
+	if reportsCollection != nil {
+		return i.traced("reconciliation_reports", reportsCollection)
+	}
+
+	collection, err := mongodb.NewCollection(i.CurrLog, i.Database(), "reconciliation_reports", IsDbTimeoutError, "created")
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	reportsCollection = collection
+	return i.traced("reconciliation_reports", reportsCollection)
+}
+
+func (i *Deps) MessagesCollection() db.Collection {
+	if i.CurrMsgColl != nil {
+		return i.CurrMsgColl
+	}
+
+	if messagesCollection != nil {
+		return i.traced("processed_messages", messagesCollection)
+	}
+
+	collection, err := mongodb.NewCollection(i.CurrLog, i.Database(), "processed_messages", IsDbTimeoutError)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	err = i.createIndexes("processed_messages",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	if err := i.ttlIndex("processed_messages", "created", time.Duration(env.Get().MessagesTTLHours)*time.Hour); err != nil {
+		// Sin el ttl los mensajes no se depuran, pero la idempotencia sigue funcionando
+		i.CurrLog.Error(err)
+	}
+
+	messagesCollection = collection
+	return i.traced("processed_messages", messagesCollection)
+}
+
+func (i *Deps) MessagesRepository() messages.MessagesRepository {
+	if i.CurrMsgRepo != nil {
+		return i.CurrMsgRepo
+	}
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrMsgRepo = messages.NewMemoryMessagesRepository(i.Logger(), memoryMessages)
+	case env.PostgresStorage:
+		i.CurrMsgRepo = messages.NewPostgresMessagesRepository(
+			i.Logger(),
+			i.postgresDB(),
+			time.Duration(env.Get().MessagesTTLHours)*time.Hour,
+		)
+	default:
+		i.CurrMsgRepo = messages.NewMessagesRepository(i.Logger(), i.MessagesCollection(), i.Database().Collection("processed_messages"))
+	}
+	return i.CurrMsgRepo
+}
+
+func (i *Deps) MessagesService() messages.MessagesService {
+	if i.CurrMsgSvc != nil {
+		return i.CurrMsgSvc
+	}
+	i.CurrMsgSvc = messages.NewMessagesService(i.Logger(), i.MessagesRepository())
+	return i.CurrMsgSvc
+}
+
+func (i *Deps) IdempotencyCollection() db.Collection {
+	if i.CurrIdmColl != nil {
+		return i.CurrIdmColl
 	}
 
-	cartCollection, err := db.NewCollection(i.CurrLog, i.Database(), "status_projection", IsDbTimeoutError, "orderId")
+	if idempotencyCollection != nil {
+		return i.traced("idempotency_keys", idempotencyCollection)
+	}
+
+	collection, err := mongodb.NewCollection(i.CurrLog, i.Database(), "idempotency_keys", IsDbTimeoutError)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	err = i.createIndexes("idempotency_keys",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
 	if err != nil {
 		i.CurrLog.Fatal(err)
 		return nil
 	}
-	return cartCollection
+
+	if err := i.ttlIndex("idempotency_keys", "created", time.Duration(env.Get().IdempotencyTTL)*time.Hour); err != nil {
+		// Sin el ttl las claves no se depuran, pero la idempotencia sigue funcionando
+		i.CurrLog.Error(err)
+	}
+
+	idempotencyCollection = collection
+	return i.traced("idempotency_keys", idempotencyCollection)
+}
+
+func (i *Deps) IdempotencyRepository() idempotency.IdempotencyRepository {
+	if i.CurrIdmRepo != nil {
+		return i.CurrIdmRepo
+	}
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrIdmRepo = idempotency.NewMemoryIdempotencyRepository(i.Logger(), memoryIdempotency)
+	case env.PostgresStorage:
+		i.CurrIdmRepo = idempotency.NewPostgresIdempotencyRepository(
+			i.Logger(),
+			i.postgresDB(),
+			time.Duration(env.Get().IdempotencyTTL)*time.Hour,
+		)
+	default:
+		i.CurrIdmRepo = idempotency.NewIdempotencyRepository(i.Logger(), i.IdempotencyCollection(), i.Database().Collection("idempotency_keys"))
+	}
+	return i.CurrIdmRepo
+}
+
+func (i *Deps) IdempotencyService() idempotency.IdempotencyService {
+	if i.CurrIdmSvc != nil {
+		return i.CurrIdmSvc
+	}
+	i.CurrIdmSvc = idempotency.NewIdempotencyService(i.Logger(), i.IdempotencyRepository())
+	return i.CurrIdmSvc
 }
 
 func (i *Deps) OrdersRepository() order.OrderRepository {
 	if i.CurrOrdRepo != nil {
 		return i.CurrOrdRepo
 	}
-	i.CurrOrdRepo = order.NewOrderRepository(i.Logger(), i.OrdersCollection())
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrOrdRepo = order.NewMemoryOrderRepository(i.Logger(), memoryOrders)
+	case env.PostgresStorage:
+		i.CurrOrdRepo = order.NewPostgresOrderRepository(i.Logger(), i.postgresDB())
+	default:
+		i.CurrOrdRepo = order.NewOrderRepository(i.Logger(), i.OrdersCollection(), i.Database().Collection("order_projection"))
+	}
 	return i.CurrOrdRepo
 }
 
@@ -203,10 +747,77 @@ func (i *Deps) StatusRepository() status.StatusRepository {
 	if i.CurrStsRepo != nil {
 		return i.CurrStsRepo
 	}
-	i.CurrStsRepo = status.NewStatusRepository(i.Logger(), i.StatusCollection())
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrStsRepo = status.NewMemoryStatusRepository(i.Logger(), memoryStatus)
+	case env.PostgresStorage:
+		i.CurrStsRepo = status.NewPostgresStatusRepository(i.Logger(), i.postgresDB())
+	default:
+		i.CurrStsRepo = status.NewStatusRepository(i.Logger(), i.StatusCollection())
+	}
 	return i.CurrStsRepo
 }
 
+func (i *Deps) CustomerRepository() customer.CustomerRepository {
+	if i.CurrCusRepo != nil {
+		return i.CurrCusRepo
+	}
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrCusRepo = customer.NewMemoryCustomerRepository(i.Logger(), memoryCustomers)
+	case env.PostgresStorage:
+		i.CurrCusRepo = customer.NewPostgresCustomerRepository(i.Logger(), i.postgresDB())
+	default:
+		i.CurrCusRepo = customer.NewCustomerRepository(i.Logger(), i.CustomerCollection())
+	}
+	return i.CurrCusRepo
+}
+
+func (i *Deps) SalesRepository() sales.SalesRepository {
+	if i.CurrSalRepo != nil {
+		return i.CurrSalRepo
+	}
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrSalRepo = sales.NewMemorySalesRepository(i.Logger(), memorySales)
+	case env.PostgresStorage:
+		i.CurrSalRepo = sales.NewPostgresSalesRepository(i.Logger(), i.postgresDB())
+	default:
+		i.CurrSalRepo = sales.NewSalesRepository(i.Logger(), i.SalesCollection())
+	}
+	return i.CurrSalRepo
+}
+
+func (i *Deps) ArticleRepository() article.ArticleRepository {
+	if i.CurrArtRepo != nil {
+		return i.CurrArtRepo
+	}
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrArtRepo = article.NewMemoryArticleRepository(i.Logger(), memoryArticles)
+	case env.PostgresStorage:
+		i.CurrArtRepo = article.NewPostgresArticleRepository(i.Logger(), i.postgresDB())
+	default:
+		i.CurrArtRepo = article.NewArticleRepository(i.Logger(), i.ArticlesCollection(), i.Database().Collection("article_projection"))
+	}
+	return i.CurrArtRepo
+}
+
+func (i *Deps) ReportRepository() reconciliation.ReportRepository {
+	if i.CurrRepRepo != nil {
+		return i.CurrRepRepo
+	}
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrRepRepo = reconciliation.NewMemoryReportRepository(i.Logger(), memoryReports)
+	case env.PostgresStorage:
+		i.CurrRepRepo = reconciliation.NewPostgresReportRepository(i.Logger(), i.postgresDB())
+	default:
+		i.CurrRepRepo = reconciliation.NewReportRepository(i.Logger(), i.ReportsCollection(), i.Database().Collection("reconciliation_reports"))
+	}
+	return i.CurrRepRepo
+}
+
 func (i *Deps) OrderService() order.OrderService {
 	if i.CurrOrdSvc != nil {
 		return i.CurrOrdSvc
@@ -219,50 +830,355 @@ func (i *Deps) StatusService() status.StatusService {
 	if i.CurrStsSvc != nil {
 		return i.CurrStsSvc
 	}
-	i.CurrStsSvc = status.NewStatusService(i.Logger(), i.StatusRepository())
+	i.CurrStsSvc = status.NewStatusService(
+		i.Logger(),
+		i.StatusRepository(),
+		time.Duration(env.Get().OrderExpiration)*time.Hour,
+	)
 	return i.CurrStsSvc
 }
 
+func (i *Deps) CustomerService() customer.CustomerService {
+	if i.CurrCusSvc != nil {
+		return i.CurrCusSvc
+	}
+	i.CurrCusSvc = customer.NewCustomerService(i.Logger(), i.CustomerRepository())
+	return i.CurrCusSvc
+}
+
+func (i *Deps) SalesService() sales.SalesService {
+	if i.CurrSalSvc != nil {
+		return i.CurrSalSvc
+	}
+	i.CurrSalSvc = sales.NewSalesService(i.Logger(), i.SalesRepository())
+	return i.CurrSalSvc
+}
+
+func (i *Deps) ArticleService() article.ArticleService {
+	if i.CurrArtSvc != nil {
+		return i.CurrArtSvc
+	}
+	i.CurrArtSvc = article.NewArticleService(i.Logger(), i.ArticleRepository())
+	return i.CurrArtSvc
+}
+
+func (i *Deps) AnalyticsService() analytics.AnalyticsService {
+	if i.CurrAnaSvc != nil {
+		return i.CurrAnaSvc
+	}
+	i.CurrAnaSvc = analytics.NewAnalyticsService(i.Logger(), i.StatusService(), i.EventService())
+	return i.CurrAnaSvc
+}
+
+func (i *Deps) PaymentsClient() reconciliation.PaymentsClient {
+	if i.CurrPayClient != nil {
+		return i.CurrPayClient
+	}
+	i.CurrPayClient = reconciliation.NewPaymentsClient(i.Logger(), i.HttpClient(), env.Get().PaymentsServerURL)
+	return i.CurrPayClient
+}
+
+func (i *Deps) ReconciliationService() reconciliation.ReconciliationService {
+	if i.CurrRecSvc != nil {
+		return i.CurrRecSvc
+	}
+	i.CurrRecSvc = reconciliation.NewReconciliationService(
+		i.Logger(),
+		i.PaymentsClient(),
+		i.ReportRepository(),
+		i.StatusService(),
+		i.EventService(),
+	)
+	return i.CurrRecSvc
+}
+
 func (i *Deps) ProjectionsService() projections.ProjectionsService {
 	if i.CurrPrjSvc != nil {
 		return i.CurrPrjSvc
 	}
-	i.CurrPrjSvc = projections.NewProjectionsService(i.Logger(), i.EventService(), i.OrderService(), i.StatusService())
+	i.CurrPrjSvc = projections.NewProjectionsService(
+		i.Logger(),
+		i.Metrics(),
+		i.ProjectionDispatcher(),
+		i.EventService(),
+		i.ProjectionRegistry(),
+		i.VersionRepository(),
+	)
 	return i.CurrPrjSvc
 }
 
+// ProjectionRegistry read models que se actualizan con cada evento
+func (i *Deps) ProjectionRegistry() *projections.Registry {
+	if i.CurrPrjReg != nil {
+		return i.CurrPrjReg
+	}
+	i.CurrPrjReg = projections.NewRegistry(
+		order.NewOrderProjection(i.OrderService()),
+		status.NewStatusProjection(i.StatusService()),
+		customer.NewCustomerProjection(i.CustomerService()),
+		sales.NewSalesProjection(i.SalesService()),
+		article.NewArticleProjection(i.ArticleService()),
+	)
+	return i.CurrPrjReg
+}
+
+func (i *Deps) VersionsCollection() db.Collection {
+	if i.CurrVerColl != nil {
+		return i.CurrVerColl
+	}
+
+	if versionsCollection != nil {
+		return i.traced("projection_versions", versionsCollection)
+	}
+
+	collection, err := mongodb.NewCollection(i.CurrLog, i.Database(), "projection_versions", IsDbTimeoutError)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	err = i.createIndexes("projection_versions",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "projection", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	versionsCollection = collection
+	return i.traced("projection_versions", versionsCollection)
+}
+
+func (i *Deps) VersionRepository() version.VersionRepository {
+	if i.CurrVerRepo != nil {
+		return i.CurrVerRepo
+	}
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrVerRepo = version.NewMemoryVersionRepository(i.Logger(), memoryVersions)
+	case env.PostgresStorage:
+		i.CurrVerRepo = version.NewPostgresVersionRepository(i.Logger(), i.postgresDB())
+	default:
+		i.CurrVerRepo = version.NewVersionRepository(i.Logger(), i.VersionsCollection())
+	}
+	return i.CurrVerRepo
+}
+
+// ProjectionDispatcher único por proceso, serializa las actualizaciones de cada orden
+func (i *Deps) ProjectionDispatcher() *projections.Dispatcher {
+	if i.CurrDispatcher != nil {
+		return i.CurrDispatcher
+	}
+
+	dispatcherMutex.Lock()
+	defer dispatcherMutex.Unlock()
+
+	if dispatcher != nil {
+		return dispatcher
+	}
+
+	dispatcher = projections.NewDispatcher(
+		i.Logger(),
+		i.Metrics(),
+		env.Get().ProjectionWorkers,
+		env.Get().ProjectionQueue,
+		func(orderId string, projections []string) error {
+			logger := log.Get(env.Get().FluentURL, env.Get().ServerName).
+				WithField(log.LOG_FIELD_CONTROLLER, "Projections")
+			return NewInjector(logger).ProjectionsService().Update(orderId, projections...)
+		},
+	)
+	lifecycle.OnShutdown(lifecycle.Drain, "projections", dispatcher.Drain)
+
+	return dispatcher
+}
+
+// EventFeed mongo sigue la colección con un change stream, postgres y memoria consultan por posición
+func (i *Deps) EventFeed() events.EventFeed {
+	if i.CurrEvtFeed != nil {
+		return i.CurrEvtFeed
+	}
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrEvtFeed = events.NewMemoryEventFeed(i.Logger(), memoryEvents)
+	case env.PostgresStorage:
+		i.CurrEvtFeed = events.NewPostgresEventFeed(i.Logger(), i.Postgres())
+	default:
+		i.CurrEvtFeed = events.NewEventFeed(i.Logger(), i.Database().Collection("events"))
+	}
+	return i.CurrEvtFeed
+}
+
+func (i *Deps) CheckpointsCollection() db.Collection {
+	if i.CurrChkColl != nil {
+		return i.CurrChkColl
+	}
+
+	if checkpointsCollection != nil {
+		return i.traced("projection_checkpoints", checkpointsCollection)
+	}
+
+	collection, err := mongodb.NewCollection(i.CurrLog, i.Database(), "projection_checkpoints", IsDbTimeoutError)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	err = i.createIndexes("projection_checkpoints",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if err != nil {
+		i.CurrLog.Fatal(err)
+		return nil
+	}
+
+	checkpointsCollection = collection
+	return i.traced("projection_checkpoints", checkpointsCollection)
+}
+
+func (i *Deps) CheckpointRepository() checkpoint.CheckpointRepository {
+	if i.CurrChkRepo != nil {
+		return i.CurrChkRepo
+	}
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrChkRepo = checkpoint.NewMemoryCheckpointRepository(i.Logger(), memoryCheckpoints)
+	case env.PostgresStorage:
+		i.CurrChkRepo = checkpoint.NewPostgresCheckpointRepository(i.Logger(), i.postgresDB())
+	default:
+		i.CurrChkRepo = checkpoint.NewCheckpointRepository(i.Logger(), i.CheckpointsCollection())
+	}
+	return i.CurrChkRepo
+}
+
+// ProjectionRunner aplica los eventos a través del dispatcher, para no competir con las
+// actualizaciones que encolan los servicios
+func (i *Deps) ProjectionRunner() *projections.Runner {
+	if i.CurrRunner != nil {
+		return i.CurrRunner
+	}
+	i.CurrRunner = projections.NewRunner(
+		i.Logger().WithField(log.LOG_FIELD_CONTROLLER, "ProjectionRunner"),
+		i.Metrics(),
+		"projections",
+		i.EventFeed(),
+		i.CheckpointRepository(),
+		func(orderId string) error {
+			return i.ProjectionDispatcher().EnqueueWait(orderId)
+		},
+	)
+	return i.CurrRunner
+}
+
 func (i *Deps) Service() services.Service {
 	if i.CurrSvc != nil {
 		return i.CurrSvc
 	}
-	i.CurrSvc = services.NewService(i.Logger(), i.EventService(), i.ProjectionsService(), i.ArticleValidationPublisher(), i.PlacedOrderPublisher())
+	i.CurrSvc = services.NewService(
+		i.Logger(),
+		i.EventService(),
+		i.ProjectionsService(),
+		i.Transaction(),
+		i.ArticleValidationPublisher(),
+		i.PlacedOrderPublisher(),
+		i.RefundRequestPublisher(),
+	)
 	return i.CurrSvc
 }
 
+// Transaction las dependencias que recibe fn escriben dentro de una transacción del backend.
+// El backend en memoria no tiene transacciones, fn se ejecuta directamente.
+func (i *Deps) Transaction() services.Transaction {
+	if i.CurrTx != nil {
+		return i.CurrTx
+	}
+
+	switch env.Get().StorageBackend {
+	case env.MemoryStorage:
+		i.CurrTx = func(fn func(events.EventService, projections.ProjectionsService) error) error {
+			return fn(i.EventService(), i.ProjectionsService())
+		}
+	case env.PostgresStorage:
+		i.CurrTx = i.postgresTransaction
+	default:
+		i.CurrTx = i.mongoTransaction
+	}
+	return i.CurrTx
+}
+
+// mongoTransaction requiere que mongo corra como replica set
+func (i *Deps) mongoTransaction(fn func(events.EventService, projections.ProjectionsService) error) error {
+	session, err := i.Database().Client().StartSession()
+	if err != nil {
+		i.Logger().Error(err)
+		return err
+	}
+	defer session.EndSession(context.Background())
+
+	// WithTransaction reintenta fn ante errores transitorios, por eso cada intento usa dependencias nuevas
+	_, err = session.WithTransaction(i.Context(), func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		deps := &Deps{
+			CurrCtx:     sessionCtx,
+			CurrLog:     i.Logger(),
+			CurrMetrics: i.CurrMetrics,
+		}
+		return nil, fn(deps.EventService(), deps.ProjectionsService())
+	})
+	return err
+}
+
+func (i *Deps) postgresTransaction(fn func(events.EventService, projections.ProjectionsService) error) error {
+	return pgx.BeginFunc(i.Context(), i.Postgres(), func(tx pgx.Tx) error {
+		deps := &Deps{
+			CurrCtx:     i.Context(),
+			CurrLog:     i.Logger(),
+			CurrMetrics: i.CurrMetrics,
+			CurrPgTx:    tx,
+		}
+		return fn(deps.EventService(), deps.ProjectionsService())
+	})
+}
+
+// postgresDB las operaciones usan la transacción en curso si la hay
+func (i *Deps) postgresDB() pgdb.DB {
+	if i.CurrPgTx != nil {
+		return i.CurrPgTx
+	}
+	return i.Postgres()
+}
+
 func (i *Deps) ArticleValidationPublisher() rbschema.ArticleValidationPublisher {
 	if i.CurrAVPublisher != nil {
 		return i.CurrAVPublisher
 	}
 
-	i.CurrAVPublisher, _ = rbt.NewRabbitPublisher[*rbschema.ArticleValidationData](
-		rbt.RbtLogger(env.Get().FluentURL, env.Get().ServerName, i.Logger().CorrelationId()),
-		env.Get().RabbitURL,
+	i.CurrAVPublisher = broker.NewPublisher[*rbschema.ArticleValidationData](
+		i.Context(),
+		i.Logger(),
+		i.Metrics(),
 		"article_exist",
 		"direct",
 		"article_exist",
 	)
 
 	return i.CurrAVPublisher
-
 }
+
 func (i *Deps) PlacedOrderPublisher() rbschema.PlacedDataPublisher {
 	if i.CurrPLPublisher != nil {
 		return i.CurrPLPublisher
 	}
 
-	i.CurrPLPublisher, _ = rbt.NewRabbitPublisher[*rbschema.OrderPlacedData](
-		rbt.RbtLogger(env.Get().FluentURL, env.Get().ServerName, i.Logger().CorrelationId()),
-		env.Get().RabbitURL,
+	i.CurrPLPublisher = broker.NewPublisher[*rbschema.OrderPlacedData](
+		i.Context(),
+		i.Logger(),
+		i.Metrics(),
 		"order_placed",
 		"fanout",
 		"",
@@ -271,11 +1187,85 @@ func (i *Deps) PlacedOrderPublisher() rbschema.PlacedDataPublisher {
 	return i.CurrPLPublisher
 }
 
+// RefundRequestPublisher publica order.refund_requested en payments_exchange, como order.canceled
+func (i *Deps) RefundRequestPublisher() rbschema.RefundRequestPublisher {
+	if i.CurrRRPublisher != nil {
+		return i.CurrRRPublisher
+	}
+
+	i.CurrRRPublisher = broker.NewPublisher[*rbschema.RefundRequestData](
+		i.Context(),
+		i.Logger(),
+		i.Metrics(),
+		"payments_exchange",
+		"topic",
+		"order.refund_requested",
+	)
+
+	return i.CurrRRPublisher
+}
+
+// traced las colecciones son compartidas, cada injector las traza con su propio contexto
+func (i *Deps) traced(name string, collection db.Collection) db.Collection {
+	if collection == nil {
+		return nil
+	}
+	return telemetry.TraceCollection(i.Context(), name, collection)
+}
+
+// createIndexes crea los indices que db.NewCollection no soporta (unicos, parciales, ttl)
+func (i *Deps) createIndexes(collection string, indexes ...mongo.IndexModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := i.Database().Collection(collection).Indexes().CreateMany(ctx, indexes); err != nil {
+		IsDbTimeoutError(err)
+		return err
+	}
+	return nil
+}
+
+// ttlIndex crea el indice ttl sobre field. Si ya existe con otro ttl lo actualiza con collMod,
+// createIndexes falla con IndexOptionsConflict cuando cambian las opciones de un indice existente.
+func (i *Deps) ttlIndex(collection string, field string, ttl time.Duration) error {
+	err := i.createIndexes(collection, mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(ttl.Seconds())),
+	})
+
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) || !serverErr.HasErrorCode(indexOptionsConflict) {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return i.Database().RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection},
+		{Key: "index", Value: bson.D{
+			{Key: "keyPattern", Value: bson.D{{Key: field, Value: 1}}},
+			{Key: "expireAfterSeconds", Value: int32(ttl.Seconds())},
+		}},
+	}).Err()
+}
+
+// indexOptionsConflict código de mongo para un indice existente con otras opciones
+const indexOptionsConflict = 85
+
 func IsDbTimeoutError(err error) {
 	if err == topology.ErrServerSelectionTimeout {
 		database = nil
 		eventsCollection = nil
 		ordersCollection = nil
 		statusCollection = nil
+		customerCollection = nil
+		salesCollection = nil
+		articlesCollection = nil
+		reportsCollection = nil
+		messagesCollection = nil
+		idempotencyCollection = nil
+		checkpointsCollection = nil
+		versionsCollection = nil
 	}
 }
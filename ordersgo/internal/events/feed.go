@@ -0,0 +1,125 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nmarsollier/commongo/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Intervalo de consulta de los feeds que no reciben notificaciones
+const feedInterval = time.Second
+
+// EventFeed recorre los eventos en el orden en que se guardaron, a partir de una posición.
+type EventFeed interface {
+	// Head devuelve la posición actual, los eventos siguientes son los que se guarden después
+	Head(ctx context.Context) (string, error)
+	// Tail llama a apply con cada evento posterior a position y la posición que lo deja aplicado.
+	// Bloquea hasta que se cancele ctx, o apply o la lectura devuelvan error.
+	Tail(ctx context.Context, position string, apply func(event *Event, position string) error) error
+}
+
+// NewEventFeed sigue la colección events con un change stream, requiere que mongo corra como replica set.
+// La posición es el resume token del change stream.
+func NewEventFeed(log log.LogRusEntry, collection *mongo.Collection) EventFeed {
+	return &eventFeed{
+		log:        log,
+		collection: collection,
+	}
+}
+
+type eventFeed struct {
+	log        log.LogRusEntry
+	collection *mongo.Collection
+}
+
+var insertsPipeline = mongo.Pipeline{
+	{{Key: "$match", Value: bson.M{"operationType": "insert"}}},
+}
+
+type insertChange struct {
+	FullDocument *Event `bson:"fullDocument"`
+}
+
+func (f *eventFeed) Head(ctx context.Context) (string, error) {
+	stream, err := f.collection.Watch(ctx, insertsPipeline)
+	if err != nil {
+		f.log.Error(err)
+		return "", err
+	}
+	defer stream.Close(context.Background())
+
+	return resumePosition(stream.ResumeToken())
+}
+
+func (f *eventFeed) Tail(ctx context.Context, position string, apply func(*Event, string) error) error {
+	streamOptions := options.ChangeStream()
+	if position != "" {
+		streamOptions.SetResumeAfter(bson.M{"_data": position})
+	}
+
+	stream, err := f.collection.Watch(ctx, insertsPipeline, streamOptions)
+	if err != nil {
+		f.log.Error(err)
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		change := &insertChange{}
+		if err := stream.Decode(change); err != nil {
+			f.log.Error(err)
+			return err
+		}
+
+		next, err := resumePosition(stream.ResumeToken())
+		if err != nil {
+			return err
+		}
+
+		if err := apply(change.FullDocument, next); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := stream.Err(); err != nil {
+		f.log.Error(err)
+		return err
+	}
+	return errors.New("events change stream closed")
+}
+
+// resumePosition el resume token es un documento {_data: string}, se guarda solo el string
+func resumePosition(token bson.Raw) (string, error) {
+	if token == nil {
+		return "", errors.New("change stream without resume token")
+	}
+
+	value, err := token.LookupErr("_data")
+	if err != nil {
+		return "", err
+	}
+
+	position, ok := value.StringValueOK()
+	if !ok {
+		return "", errors.New("unexpected resume token format")
+	}
+	return position, nil
+}
+
+// waitFeed espera el próximo intervalo de consulta, devuelve false si fue cancelado
+func waitFeed(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(feedInterval):
+		return true
+	}
+}
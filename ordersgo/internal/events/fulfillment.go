@@ -0,0 +1,132 @@
+package events
+
+import (
+	"time"
+
+	"github.com/nmarsollier/commongo/errs"
+)
+
+// FulfillmentStatus estado del envío de la orden, cada evento de fulfillment es una transición
+type FulfillmentStatus string
+
+const (
+	FulfillmentPacking        FulfillmentStatus = "packing"
+	FulfillmentShipped        FulfillmentStatus = "shipped"
+	FulfillmentDelivered      FulfillmentStatus = "delivered"
+	FulfillmentDeliveryFailed FulfillmentStatus = "delivery_failed"
+)
+
+// fulfillmentTransitions estados a los que se puede pasar desde cada estado, "" es una orden sin envío.
+// Una entrega fallida se puede volver a despachar.
+var fulfillmentTransitions = map[FulfillmentStatus][]FulfillmentStatus{
+	"":                        {FulfillmentPacking, FulfillmentShipped},
+	FulfillmentPacking:        {FulfillmentShipped},
+	FulfillmentShipped:        {FulfillmentDelivered, FulfillmentDeliveryFailed},
+	FulfillmentDeliveryFailed: {FulfillmentShipped, FulfillmentDelivered},
+}
+
+// IsValid indica si es un estado conocido
+func (s FulfillmentStatus) IsValid() bool {
+	switch s {
+	case FulfillmentPacking, FulfillmentShipped, FulfillmentDelivered, FulfillmentDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// CanTransition indica si un envío en este estado puede pasar a next
+func (s FulfillmentStatus) CanTransition(next FulfillmentStatus) bool {
+	for _, allowed := range fulfillmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// FulfillmentEvent transición del envío. Carrier y TrackingNumber son obligatorios al despachar.
+type FulfillmentEvent struct {
+	Status         FulfillmentStatus `bson:"status" json:"status" binding:"required"`
+	Carrier        string            `bson:"carrier,omitempty" json:"carrier,omitempty" binding:"max=100"`
+	TrackingNumber string            `bson:"trackingNumber,omitempty" json:"trackingNumber,omitempty" binding:"max=100"`
+	Reason         string            `bson:"reason,omitempty" json:"reason,omitempty" binding:"max=500"`
+}
+
+// Validate valida el evento sin considerar el estado actual del envío
+func (e *FulfillmentEvent) Validate() error {
+	if !e.Status.IsValid() {
+		return errs.NewValidation().Add("status", "invalid fulfillment status")
+	}
+	if err := validateBinding(e); err != nil {
+		return err
+	}
+	if e.Status == FulfillmentShipped && (e.Carrier == "" || e.TrackingNumber == "") {
+		return errs.NewValidation().Add("trackingNumber", "carrier and tracking number are required to ship")
+	}
+	return nil
+}
+
+// FulfillmentState estado del envío que resulta de aplicar sus transiciones en orden
+type FulfillmentState struct {
+	Status         FulfillmentStatus        `bson:"status" json:"status"`
+	Carrier        string                   `bson:"carrier,omitempty" json:"carrier,omitempty"`
+	TrackingNumber string                   `bson:"trackingNumber,omitempty" json:"trackingNumber,omitempty"`
+	Transitions    []*FulfillmentTransition `bson:"transitions" json:"transitions"`
+}
+
+type FulfillmentTransition struct {
+	Status         FulfillmentStatus `bson:"status" json:"status"`
+	Carrier        string            `bson:"carrier,omitempty" json:"carrier,omitempty"`
+	TrackingNumber string            `bson:"trackingNumber,omitempty" json:"trackingNumber,omitempty"`
+	Reason         string            `bson:"reason,omitempty" json:"reason,omitempty"`
+	Time           time.Time         `bson:"time" json:"time"`
+}
+
+// Apply aplica la transición del evento, devuelve false si no es válida desde el estado actual.
+// Al despachar se reemplazan el transporte y el número de seguimiento.
+func (f *FulfillmentState) Apply(e *Event) bool {
+	fulfillment := e.Fulfillment
+	if !f.Status.CanTransition(fulfillment.Status) {
+		return false
+	}
+
+	f.Status = fulfillment.Status
+	if fulfillment.Status == FulfillmentShipped {
+		f.Carrier = fulfillment.Carrier
+		f.TrackingNumber = fulfillment.TrackingNumber
+	}
+
+	f.Transitions = append(f.Transitions, &FulfillmentTransition{
+		Status:         fulfillment.Status,
+		Carrier:        fulfillment.Carrier,
+		TrackingNumber: fulfillment.TrackingNumber,
+		Reason:         fulfillment.Reason,
+		Time:           e.Created,
+	})
+	return true
+}
+
+// FoldFulfillment estado del envío de la orden, nil si todavía no tiene eventos de fulfillment
+func FoldFulfillment(ev []*Event) *FulfillmentState {
+	var result *FulfillmentState
+	for _, e := range ev {
+		if e.Type != Fulfillment {
+			continue
+		}
+		if result == nil {
+			result = &FulfillmentState{}
+		}
+		result.Apply(e)
+	}
+	return result
+}
+
+func newFulfillmentEvent(orderId string, fulfillment *FulfillmentEvent) *Event {
+	return &Event{
+		OrderId:     orderId,
+		Type:        Fulfillment,
+		Fulfillment: fulfillment,
+		Created:     time.Now(),
+		Updated:     time.Now(),
+	}
+}
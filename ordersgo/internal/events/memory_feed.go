@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+)
+
+// NewMemoryEventFeed consulta la tabla de eventos en memoria.
+// La posición es la cantidad de eventos leídos.
+func NewMemoryEventFeed(log log.LogRusEntry, table *memdb.Table[Event]) EventFeed {
+	return &memoryEventFeed{
+		log:   log,
+		table: table,
+	}
+}
+
+type memoryEventFeed struct {
+	log   log.LogRusEntry
+	table *memdb.Table[Event]
+}
+
+func (f *memoryEventFeed) Head(ctx context.Context) (string, error) {
+	return strconv.Itoa(f.table.Len()), nil
+}
+
+func (f *memoryEventFeed) Tail(ctx context.Context, position string, apply func(*Event, string) error) error {
+	read, err := strconv.Atoi(position)
+	if err != nil {
+		f.log.Error(err)
+		return err
+	}
+
+	for {
+		events, err := f.table.Since(read)
+		if err != nil {
+			f.log.Error(err)
+			return err
+		}
+
+		for _, event := range events {
+			if err := apply(event, strconv.Itoa(read+1)); err != nil {
+				return err
+			}
+			read++
+		}
+
+		if !waitFeed(ctx) {
+			return nil
+		}
+	}
+}
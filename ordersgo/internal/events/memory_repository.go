@@ -0,0 +1,105 @@
+package events
+
+import (
+	"time"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+)
+
+// NewMemoryEventsRepository event store en memoria, la tabla se comparte entre requests
+func NewMemoryEventsRepository(log log.LogRusEntry, table *memdb.Table[Event]) EventsRepository {
+	return &memoryEventsRepository{
+		log:   log,
+		table: table,
+	}
+}
+
+type memoryEventsRepository struct {
+	log   log.LogRusEntry
+	table *memdb.Table[Event]
+}
+
+func (r *memoryEventsRepository) Insert(event *Event) (*Event, error) {
+	if err := event.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	// Mismo criterio que los indices unicos de mongo, un solo place_order por carrito,
+	// un solo evento inicial por pago, una validación por artículo y un reembolso por número
+	err := r.table.Insert(event, func(current *Event) bool {
+		return samePlace(current, event) || sameFirstPayment(current, event) ||
+			sameValidation(current, event) || sameRefund(current, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (r *memoryEventsRepository) FindPlaceByCartId(cartId string) (*Event, error) {
+	return r.table.FindOne(func(event *Event) bool {
+		return event.Type == Place && event.PlaceEvent != nil && event.PlaceEvent.CartId == cartId
+	})
+}
+
+func (r *memoryEventsRepository) FindByOrderId(orderId string) ([]*Event, error) {
+	return r.table.Find(func(event *Event) bool {
+		return event.OrderId == orderId
+	})
+}
+
+func (r *memoryEventsRepository) FindPaymentByPaymentId(paymentId string) (*Event, error) {
+	return r.table.FindOne(func(event *Event) bool {
+		return event.Type == Payment && event.Payment != nil && event.Payment.PaymentId == paymentId
+	})
+}
+
+func (r *memoryEventsRepository) FindOrderIds() ([]string, error) {
+	places, err := r.table.Find(func(event *Event) bool {
+		return event.Type == Place
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	orderIds := make([]string, len(places))
+	for index, place := range places {
+		orderIds[index] = place.OrderId
+	}
+	return orderIds, nil
+}
+
+func (r *memoryEventsRepository) FindPayments(from time.Time, to time.Time) ([]*Event, error) {
+	return r.table.Find(func(event *Event) bool {
+		return event.Type == Payment && !event.Created.Before(from) && event.Created.Before(to)
+	})
+}
+
+func samePlace(current *Event, event *Event) bool {
+	return event.Type == Place && current.Type == Place &&
+		event.PlaceEvent != nil && current.PlaceEvent != nil &&
+		current.PlaceEvent.CartId == event.PlaceEvent.CartId
+}
+
+func sameFirstPayment(current *Event, event *Event) bool {
+	return event.Type == Payment && current.Type == Payment &&
+		event.Payment != nil && current.Payment != nil &&
+		event.Payment.PaymentId != "" && event.Payment.Status == "" && current.Payment.Status == "" &&
+		current.OrderId == event.OrderId && current.Payment.PaymentId == event.Payment.PaymentId
+}
+
+func sameValidation(current *Event, event *Event) bool {
+	return event.Type == Validation && current.Type == Validation &&
+		event.Validation != nil && current.Validation != nil &&
+		current.OrderId == event.OrderId && current.Validation.ArticleId == event.Validation.ArticleId
+}
+
+func sameRefund(current *Event, event *Event) bool {
+	return event.Type == Refund && current.Type == Refund &&
+		event.Refund != nil && current.Refund != nil && event.Refund.Sequence > 0 &&
+		current.OrderId == event.OrderId && current.Refund.PaymentId == event.Refund.PaymentId &&
+		current.Refund.Sequence == event.Refund.Sequence
+}
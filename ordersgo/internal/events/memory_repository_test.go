@@ -0,0 +1,14 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestMemoryEvents(t *testing.T) {
+	repotest.EventsRepository(t, events.NewMemoryEventsRepository(log.Get("", "test"), memdb.NewTable[events.Event]()))
+}
@@ -0,0 +1,218 @@
+package events
+
+import (
+	"time"
+)
+
+// PaymentStatus estado del ciclo de vida de un pago, cada evento de pago es una transición
+type PaymentStatus string
+
+const (
+	PaymentPending           PaymentStatus = "pending"
+	PaymentAuthorized        PaymentStatus = "authorized"
+	PaymentCaptured          PaymentStatus = "captured"
+	PaymentRejected          PaymentStatus = "rejected"
+	PaymentRefunded          PaymentStatus = "refunded"
+	PaymentPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentChargeback        PaymentStatus = "chargeback"
+
+	// PaymentApproved estado de los eventos anteriores al ciclo de vida, equivale a captured
+	PaymentApproved PaymentStatus = "approved"
+)
+
+// paymentTransitions estados a los que se puede pasar desde cada estado, "" es un pago sin eventos.
+// Los pagos sin estado son pagos definidos a mano, no mueven dinero.
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	"":                       {"", PaymentPending, PaymentAuthorized, PaymentCaptured, PaymentRejected},
+	PaymentPending:           {PaymentAuthorized, PaymentCaptured, PaymentRejected},
+	PaymentAuthorized:        {PaymentCaptured, PaymentRejected},
+	PaymentCaptured:          {PaymentPartiallyRefunded, PaymentRefunded, PaymentChargeback},
+	PaymentPartiallyRefunded: {PaymentPartiallyRefunded, PaymentRefunded, PaymentChargeback},
+}
+
+// Normalize convierte los estados anteriores al ciclo de vida
+func (s PaymentStatus) Normalize() PaymentStatus {
+	if s == PaymentApproved {
+		return PaymentCaptured
+	}
+	return s
+}
+
+// IsValid indica si es un estado conocido
+func (s PaymentStatus) IsValid() bool {
+	switch s.Normalize() {
+	case "", PaymentPending, PaymentAuthorized, PaymentCaptured, PaymentRejected,
+		PaymentRefunded, PaymentPartiallyRefunded, PaymentChargeback:
+		return true
+	}
+	return false
+}
+
+// CanTransition indica si un pago en este estado puede pasar a next
+func (s PaymentStatus) CanTransition(next PaymentStatus) bool {
+	for _, allowed := range paymentTransitions[s.Normalize()] {
+		if allowed == next.Normalize() {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentState estado de un pago que resulta de aplicar sus transiciones en orden
+type PaymentState struct {
+	PaymentId     string
+	Method        string
+	TransactionId string
+	Status        PaymentStatus
+	// Amount monto autorizado o capturado
+	Amount       float32
+	Captured     float32
+	Refunded     float32
+	ChargedBack  float32
+	ErrorMessage string
+	ErrorCode    string
+	Transitions  []*PaymentTransition
+}
+
+// PaymentTransition transición aplicada, en los reembolsos y contracargos Amount es lo que se devolvió
+type PaymentTransition struct {
+	Status PaymentStatus
+	Amount float32
+	Reason string
+	Time   time.Time
+}
+
+// Remaining capturado que todavía no se reembolsó ni contracargó
+func (p *PaymentState) Remaining() float32 {
+	return p.Captured - p.Refunded - p.ChargedBack
+}
+
+// CanRefund indica si se puede reembolsar amount del pago
+func (p *PaymentState) CanRefund(amount float32) bool {
+	return p.Status.CanTransition(PaymentPartiallyRefunded) && amount > 0 && amount <= p.Remaining()
+}
+
+// Apply aplica la transición del evento de pago o reembolso, devuelve false si no es válida desde
+// el estado actual. Un reembolso parcial que cubre lo capturado deja el pago reembolsado. Los reembolsos
+// totales y los contracargos devuelven todo lo que quedaba capturado.
+func (p *PaymentState) Apply(e *Event) bool {
+	if e.Type == Refund {
+		return p.applyRefund(e)
+	}
+
+	payment := e.Payment
+	next := payment.Status.Normalize()
+	if !p.Status.CanTransition(next) {
+		return false
+	}
+
+	amount := payment.Amount
+	switch next {
+	case "", PaymentPending, PaymentAuthorized:
+		p.Amount = payment.Amount
+	case PaymentCaptured:
+		p.Amount = payment.Amount
+		p.Captured = payment.Amount
+	case PaymentRejected:
+		if p.Amount == 0 {
+			p.Amount = payment.Amount
+		}
+	case PaymentPartiallyRefunded:
+		amount = min(payment.Amount, p.Remaining())
+		p.Refunded += amount
+		if p.Remaining() <= 0 {
+			next = PaymentRefunded
+		}
+	case PaymentRefunded:
+		amount = p.Remaining()
+		p.Refunded += amount
+	case PaymentChargeback:
+		amount = p.Remaining()
+		p.ChargedBack += amount
+	}
+
+	p.Status = next
+	if payment.Method != "" {
+		p.Method = payment.Method
+	}
+	if payment.TransactionId != "" {
+		p.TransactionId = payment.TransactionId
+	}
+	if payment.ErrorCode != "" || payment.ErrorMessage != "" {
+		p.ErrorMessage = payment.ErrorMessage
+		p.ErrorCode = payment.ErrorCode
+	}
+	p.Transitions = append(p.Transitions, &PaymentTransition{
+		Status: next,
+		Amount: amount,
+		Reason: payment.ErrorMessage,
+		Time:   e.Created,
+	})
+	return true
+}
+
+// applyRefund los reembolsos que superan lo que queda se limitan a lo que queda
+func (p *PaymentState) applyRefund(e *Event) bool {
+	refund := e.Refund
+	if !p.Status.CanTransition(PaymentPartiallyRefunded) || refund.Amount <= 0 {
+		return false
+	}
+
+	amount := min(refund.Amount, p.Remaining())
+	p.Refunded += amount
+	p.Status = PaymentPartiallyRefunded
+	if p.Remaining() <= 0 {
+		p.Status = PaymentRefunded
+	}
+
+	p.Transitions = append(p.Transitions, &PaymentTransition{
+		Status: p.Status,
+		Amount: amount,
+		Reason: refund.Reason,
+		Time:   e.Created,
+	})
+	return true
+}
+
+// PaymentId pago al que se refiere el evento de pago o reembolso, vacío en los demás eventos
+func (e *Event) PaymentId() string {
+	switch {
+	case e.Type == Payment && e.Payment != nil:
+		return e.Payment.PaymentId
+	case e.Type == Refund && e.Refund != nil:
+		return e.Refund.PaymentId
+	}
+	return ""
+}
+
+// FoldPayments pagos de la orden en el orden en que llegó su primer evento.
+// Los eventos sin paymentId son pagos independientes, las transiciones inválidas y los reembolsos
+// de pagos desconocidos se ignoran.
+func FoldPayments(ev []*Event) []*PaymentState {
+	result := []*PaymentState{}
+	byId := map[string]*PaymentState{}
+	for _, e := range ev {
+		if !IsPaymentEvent(e) {
+			continue
+		}
+
+		id := e.PaymentId()
+		payment, ok := byId[id]
+		if !ok || id == "" {
+			payment = &PaymentState{PaymentId: id}
+			if !payment.Apply(e) {
+				continue
+			}
+			byId[id] = payment
+			result = append(result, payment)
+			continue
+		}
+		payment.Apply(e)
+	}
+	return result
+}
+
+// IsPaymentEvent indica si el evento es de pago o de reembolso
+func IsPaymentEvent(e *Event) bool {
+	return (e.Type == Payment && e.Payment != nil) || (e.Type == Refund && e.Refund != nil)
+}
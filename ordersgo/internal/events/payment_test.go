@@ -0,0 +1,429 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+func paymentEvent(paymentId string, status events.PaymentStatus, amount float32) *events.Event {
+	return &events.Event{
+		OrderId: "order",
+		Type:    events.Payment,
+		Payment: &events.PaymentEvent{
+			OrderId:   "order",
+			Method:    string(events.Cash),
+			Amount:    amount,
+			PaymentId: paymentId,
+			Status:    status,
+		},
+	}
+}
+
+func refundEvent(paymentId string, amount float32) *events.Event {
+	return &events.Event{
+		OrderId: "order",
+		Type:    events.Refund,
+		Refund: &events.RefundEvent{
+			OrderId:   "order",
+			PaymentId: paymentId,
+			Amount:    amount,
+		},
+	}
+}
+
+func TestPaymentTransitions(t *testing.T) {
+	statuses := []events.PaymentStatus{
+		"",
+		events.PaymentPending,
+		events.PaymentAuthorized,
+		events.PaymentCaptured,
+		events.PaymentRejected,
+		events.PaymentPartiallyRefunded,
+		events.PaymentRefunded,
+		events.PaymentChargeback,
+	}
+
+	allowed := map[events.PaymentStatus][]events.PaymentStatus{
+		"":                              {"", events.PaymentPending, events.PaymentAuthorized, events.PaymentCaptured, events.PaymentRejected},
+		events.PaymentPending:           {events.PaymentAuthorized, events.PaymentCaptured, events.PaymentRejected},
+		events.PaymentAuthorized:        {events.PaymentCaptured, events.PaymentRejected},
+		events.PaymentCaptured:          {events.PaymentPartiallyRefunded, events.PaymentRefunded, events.PaymentChargeback},
+		events.PaymentPartiallyRefunded: {events.PaymentPartiallyRefunded, events.PaymentRefunded, events.PaymentChargeback},
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := false
+			for _, status := range allowed[from] {
+				want = want || status == to
+			}
+
+			if got := from.CanTransition(to); got != want {
+				t.Errorf("%q -> %q = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestPaymentInvalidTransitions(t *testing.T) {
+	tests := []struct {
+		from events.PaymentStatus
+		to   events.PaymentStatus
+	}{
+		{events.PaymentCaptured, events.PaymentAuthorized},
+		{events.PaymentCaptured, events.PaymentPending},
+		{events.PaymentCaptured, events.PaymentCaptured},
+		{events.PaymentRejected, events.PaymentCaptured},
+		{events.PaymentRejected, events.PaymentAuthorized},
+		{events.PaymentAuthorized, events.PaymentPending},
+		{events.PaymentRefunded, events.PaymentCaptured},
+		{events.PaymentRefunded, events.PaymentPartiallyRefunded},
+		{events.PaymentChargeback, events.PaymentRefunded},
+		{events.PaymentPending, events.PaymentRefunded},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			if tt.from.CanTransition(tt.to) {
+				t.Fatal("transition allowed")
+			}
+
+			state := &events.PaymentState{PaymentId: "payment", Status: tt.from}
+			if state.Apply(paymentEvent("payment", tt.to, 100)) {
+				t.Fatal("transition applied")
+			}
+			if state.Status != tt.from {
+				t.Errorf("status = %q, want %q", state.Status, tt.from)
+			}
+		})
+	}
+}
+
+func TestPaymentApprovedIsCaptured(t *testing.T) {
+	if got := events.PaymentApproved.Normalize(); got != events.PaymentCaptured {
+		t.Errorf("normalize = %q", got)
+	}
+	if got := events.PaymentPending.Normalize(); got != events.PaymentPending {
+		t.Errorf("normalize pending = %q", got)
+	}
+	if !events.PaymentApproved.IsValid() {
+		t.Error("approved is not valid")
+	}
+	if events.PaymentStatus("unknown").IsValid() {
+		t.Error("unknown is valid")
+	}
+
+	tests := []struct {
+		name string
+		from events.PaymentStatus
+		to   events.PaymentStatus
+		want bool
+	}{
+		{"new approved", "", events.PaymentApproved, true},
+		{"pending to approved", events.PaymentPending, events.PaymentApproved, true},
+		{"approved to refunded", events.PaymentApproved, events.PaymentRefunded, true},
+		{"approved to authorized", events.PaymentApproved, events.PaymentAuthorized, false},
+		{"captured to approved", events.PaymentCaptured, events.PaymentApproved, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.from.CanTransition(tt.to); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	state := &events.PaymentState{PaymentId: "payment"}
+	if !state.Apply(paymentEvent("payment", events.PaymentApproved, 80)) {
+		t.Fatal("approved not applied")
+	}
+	if state.Status != events.PaymentCaptured || state.Captured != 80 {
+		t.Errorf("status = %q, captured = %v", state.Status, state.Captured)
+	}
+}
+
+func TestPaymentStateApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		events      []*events.Event
+		status      events.PaymentStatus
+		amount      float32
+		captured    float32
+		refunded    float32
+		chargedBack float32
+		transitions int
+	}{
+		{
+			name:        "manual",
+			events:      []*events.Event{paymentEvent("p", "", 50)},
+			status:      "",
+			amount:      50,
+			transitions: 1,
+		},
+		{
+			name: "authorized and captured",
+			events: []*events.Event{
+				paymentEvent("p", events.PaymentPending, 100),
+				paymentEvent("p", events.PaymentAuthorized, 100),
+				paymentEvent("p", events.PaymentCaptured, 90),
+			},
+			status:      events.PaymentCaptured,
+			amount:      90,
+			captured:    90,
+			transitions: 3,
+		},
+		{
+			name: "rejected keeps the authorized amount",
+			events: []*events.Event{
+				paymentEvent("p", events.PaymentAuthorized, 100),
+				paymentEvent("p", events.PaymentRejected, 0),
+			},
+			status:      events.PaymentRejected,
+			amount:      100,
+			transitions: 2,
+		},
+		{
+			name: "partial refund status covering the capture",
+			events: []*events.Event{
+				paymentEvent("p", events.PaymentCaptured, 100),
+				paymentEvent("p", events.PaymentPartiallyRefunded, 40),
+				paymentEvent("p", events.PaymentPartiallyRefunded, 80),
+			},
+			status:      events.PaymentRefunded,
+			amount:      100,
+			captured:    100,
+			refunded:    100,
+			transitions: 3,
+		},
+		{
+			name: "chargeback returns what is left",
+			events: []*events.Event{
+				paymentEvent("p", events.PaymentCaptured, 100),
+				refundEvent("p", 30),
+				paymentEvent("p", events.PaymentChargeback, 0),
+			},
+			status:      events.PaymentChargeback,
+			amount:      100,
+			captured:    100,
+			refunded:    30,
+			chargedBack: 70,
+			transitions: 3,
+		},
+		{
+			name: "invalid transitions are ignored",
+			events: []*events.Event{
+				paymentEvent("p", events.PaymentCaptured, 100),
+				paymentEvent("p", events.PaymentAuthorized, 20),
+				paymentEvent("p", events.PaymentRejected, 0),
+			},
+			status:      events.PaymentCaptured,
+			amount:      100,
+			captured:    100,
+			transitions: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &events.PaymentState{PaymentId: "p"}
+			for _, e := range tt.events {
+				state.Apply(e)
+			}
+
+			if state.Status != tt.status {
+				t.Errorf("status = %q, want %q", state.Status, tt.status)
+			}
+			if state.Amount != tt.amount {
+				t.Errorf("amount = %v, want %v", state.Amount, tt.amount)
+			}
+			if state.Captured != tt.captured {
+				t.Errorf("captured = %v, want %v", state.Captured, tt.captured)
+			}
+			if state.Refunded != tt.refunded {
+				t.Errorf("refunded = %v, want %v", state.Refunded, tt.refunded)
+			}
+			if state.ChargedBack != tt.chargedBack {
+				t.Errorf("chargedBack = %v, want %v", state.ChargedBack, tt.chargedBack)
+			}
+			if len(state.Transitions) != tt.transitions {
+				t.Errorf("transitions = %d, want %d", len(state.Transitions), tt.transitions)
+			}
+		})
+	}
+}
+
+func TestFoldPayments(t *testing.T) {
+	placed := &events.Event{OrderId: "order", Type: events.Place, PlaceEvent: &events.PlaceEvent{CartId: "cart"}}
+
+	payments := events.FoldPayments([]*events.Event{
+		placed,
+		paymentEvent("a", events.PaymentAuthorized, 60),
+		paymentEvent("", "", 10),
+		paymentEvent("b", events.PaymentRefunded, 20),
+		refundEvent("c", 5),
+		paymentEvent("b", events.PaymentCaptured, 40),
+		paymentEvent("a", events.PaymentCaptured, 60),
+		paymentEvent("", "", 15),
+		refundEvent("a", 25),
+		paymentEvent("a", events.PaymentAuthorized, 60),
+	})
+
+	tests := []struct {
+		paymentId string
+		status    events.PaymentStatus
+		amount    float32
+		remaining float32
+	}{
+		{"a", events.PaymentPartiallyRefunded, 60, 35},
+		{"", "", 10, 0},
+		{"b", events.PaymentCaptured, 40, 40},
+		{"", "", 15, 0},
+	}
+
+	if len(payments) != len(tests) {
+		t.Fatalf("payments = %d, want %d", len(payments), len(tests))
+	}
+	for i, tt := range tests {
+		payment := payments[i]
+		if payment.PaymentId != tt.paymentId || payment.Status != tt.status {
+			t.Errorf("payment %d = %q %q, want %q %q", i, payment.PaymentId, payment.Status, tt.paymentId, tt.status)
+		}
+		if payment.Amount != tt.amount || payment.Remaining() != tt.remaining {
+			t.Errorf("payment %d amount = %v remaining = %v, want %v %v", i, payment.Amount, payment.Remaining(), tt.amount, tt.remaining)
+		}
+	}
+}
+
+func TestPaymentRefunds(t *testing.T) {
+	captured := func() *events.PaymentState {
+		state := &events.PaymentState{PaymentId: "p"}
+		state.Apply(paymentEvent("p", events.PaymentCaptured, 100))
+		return state
+	}
+
+	tests := []struct {
+		name      string
+		state     func() *events.PaymentState
+		refunds   []float32
+		applied   []bool
+		status    events.PaymentStatus
+		refunded  float32
+		remaining float32
+	}{
+		{
+			name:      "partial",
+			state:     captured,
+			refunds:   []float32{30},
+			applied:   []bool{true},
+			status:    events.PaymentPartiallyRefunded,
+			refunded:  30,
+			remaining: 70,
+		},
+		{
+			name:      "partials up to the capture",
+			state:     captured,
+			refunds:   []float32{30, 70},
+			applied:   []bool{true, true},
+			status:    events.PaymentRefunded,
+			refunded:  100,
+			remaining: 0,
+		},
+		{
+			name:      "capped to the remaining",
+			state:     captured,
+			refunds:   []float32{60, 60},
+			applied:   []bool{true, true},
+			status:    events.PaymentRefunded,
+			refunded:  100,
+			remaining: 0,
+		},
+		{
+			name:      "nothing left after a full refund",
+			state:     captured,
+			refunds:   []float32{100, 10},
+			applied:   []bool{true, false},
+			status:    events.PaymentRefunded,
+			refunded:  100,
+			remaining: 0,
+		},
+		{
+			name:      "zero amount",
+			state:     captured,
+			refunds:   []float32{0},
+			applied:   []bool{false},
+			status:    events.PaymentCaptured,
+			remaining: 100,
+		},
+		{
+			name: "not captured",
+			state: func() *events.PaymentState {
+				state := &events.PaymentState{PaymentId: "p"}
+				state.Apply(paymentEvent("p", events.PaymentAuthorized, 100))
+				return state
+			},
+			refunds: []float32{10},
+			applied: []bool{false},
+			status:  events.PaymentAuthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := tt.state()
+			for i, amount := range tt.refunds {
+				if got := state.Apply(refundEvent("p", amount)); got != tt.applied[i] {
+					t.Errorf("refund %d applied = %v, want %v", i, got, tt.applied[i])
+				}
+			}
+
+			if state.Status != tt.status {
+				t.Errorf("status = %q, want %q", state.Status, tt.status)
+			}
+			if state.Refunded != tt.refunded {
+				t.Errorf("refunded = %v, want %v", state.Refunded, tt.refunded)
+			}
+			if got := state.Remaining(); got != tt.remaining {
+				t.Errorf("remaining = %v, want %v", got, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestPaymentRemaining(t *testing.T) {
+	state := &events.PaymentState{Captured: 100, Refunded: 25, ChargedBack: 15}
+	if got := state.Remaining(); got != 60 {
+		t.Errorf("remaining = %v, want 60", got)
+	}
+}
+
+func TestPaymentCanRefund(t *testing.T) {
+	tests := []struct {
+		name   string
+		status events.PaymentStatus
+		amount float32
+		want   bool
+	}{
+		{"captured", events.PaymentCaptured, 50, true},
+		{"captured remaining", events.PaymentCaptured, 60, true},
+		{"over the remaining", events.PaymentCaptured, 60.5, false},
+		{"zero", events.PaymentCaptured, 0, false},
+		{"negative", events.PaymentCaptured, -10, false},
+		{"partially refunded", events.PaymentPartiallyRefunded, 10, true},
+		{"legacy approved", events.PaymentApproved, 10, true},
+		{"refunded", events.PaymentRefunded, 10, false},
+		{"chargeback", events.PaymentChargeback, 10, false},
+		{"authorized", events.PaymentAuthorized, 10, false},
+		{"pending", events.PaymentPending, 10, false},
+		{"manual", "", 10, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &events.PaymentState{Status: tt.status, Captured: 100, Refunded: 40}
+			if got := state.CanRefund(tt.amount); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package events
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+)
+
+// Eventos que se leen por consulta
+const feedBatch = 100
+
+// NewPostgresEventFeed consulta la tabla events por seq.
+// La posición es el seq del último evento leído.
+func NewPostgresEventFeed(log log.LogRusEntry, db pgdb.DB) EventFeed {
+	return &postgresEventFeed{
+		log: log,
+		db:  db,
+	}
+}
+
+type postgresEventFeed struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+}
+
+type sequencedEvent struct {
+	seq   int64
+	event *Event
+}
+
+func (f *postgresEventFeed) Head(ctx context.Context) (string, error) {
+	var seq int64
+	if err := f.db.QueryRow(ctx, "SELECT COALESCE(MAX(seq), 0) FROM events").Scan(&seq); err != nil {
+		f.log.Error(err)
+		return "", err
+	}
+	return strconv.FormatInt(seq, 10), nil
+}
+
+// Tail el seq se asigna al insertar pero es visible al confirmar, una transacción abierta puede
+// confirmar un seq menor al último leído. Ante un hueco se espera mientras siga abierta alguna
+// transacción que pudo tomarlo, los huecos que quedan son de inserts que fallaron.
+func (f *postgresEventFeed) Tail(ctx context.Context, position string, apply func(*Event, string) error) error {
+	read, err := strconv.ParseInt(position, 10, 64)
+	if err != nil {
+		f.log.Error(err)
+		return err
+	}
+
+	var gapSince *time.Time
+	for {
+		batch, err := f.next(ctx, read)
+		if err != nil {
+			return err
+		}
+
+		for _, current := range batch {
+			if current.seq != read+1 {
+				if gapSince == nil {
+					if gapSince, err = f.now(ctx); err != nil {
+						return err
+					}
+				}
+				open, err := f.writersSince(ctx, *gapSince)
+				if err != nil {
+					return err
+				}
+				if open {
+					break
+				}
+			}
+
+			gapSince = nil
+			if err := apply(current.event, strconv.FormatInt(current.seq, 10)); err != nil {
+				return err
+			}
+			read = current.seq
+		}
+
+		if len(batch) == feedBatch && gapSince == nil {
+			continue
+		}
+		if !waitFeed(ctx) {
+			return nil
+		}
+	}
+}
+
+func (f *postgresEventFeed) next(ctx context.Context, read int64) ([]*sequencedEvent, error) {
+	rows, err := f.db.Query(ctx, `
+		SELECT seq, id, order_id, type, payload, created, updated FROM events
+		WHERE seq > $1 ORDER BY seq LIMIT $2`, read, feedBatch,
+	)
+	if err != nil {
+		f.log.Error(err)
+		return nil, err
+	}
+
+	batch, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*sequencedEvent, error) {
+		current := &sequencedEvent{}
+		event, err := scanEvent(&seqRow{CollectableRow: row, seq: &current.seq})
+		current.event = event
+		return current, err
+	})
+	if err != nil {
+		f.log.Error(err)
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+func (f *postgresEventFeed) now(ctx context.Context) (*time.Time, error) {
+	var now time.Time
+	if err := f.db.QueryRow(ctx, "SELECT clock_timestamp()").Scan(&now); err != nil {
+		f.log.Error(err)
+		return nil, err
+	}
+	return &now, nil
+}
+
+// writersSince indica si sigue abierta alguna transacción con escrituras iniciada antes de since
+func (f *postgresEventFeed) writersSince(ctx context.Context, since time.Time) (bool, error) {
+	var open bool
+	err := f.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_stat_activity
+			WHERE datname = current_database() AND pid <> pg_backend_pid()
+				AND backend_xid IS NOT NULL AND xact_start <= $1
+		)`, since,
+	).Scan(&open)
+	if err != nil {
+		f.log.Error(err)
+		return false, err
+	}
+	return open, nil
+}
+
+// seqRow antepone el seq a las columnas que lee scanEvent
+type seqRow struct {
+	pgx.CollectableRow
+	seq *int64
+}
+
+func (r *seqRow) Scan(dest ...any) error {
+	return r.CollectableRow.Scan(append([]any{r.seq}, dest...)...)
+}
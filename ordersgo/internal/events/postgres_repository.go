@@ -0,0 +1,217 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewPostgresEventsRepository event store append only sobre la tabla events
+func NewPostgresEventsRepository(log log.LogRusEntry, db pgdb.DB) EventsRepository {
+	return &postgresEventsRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+type postgresEventsRepository struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+}
+
+// eventPayload contenido del evento según su tipo
+type eventPayload struct {
+	PlaceEvent  *PlaceEvent       `json:"placeEvent,omitempty"`
+	Validation  *ValidationEvent  `json:"validation,omitempty"`
+	Payment     *PaymentEvent     `json:"payment,omitempty"`
+	CancelEvent *CancelEvent      `json:"cancelEvent,omitempty"`
+	Refund      *RefundEvent      `json:"refund,omitempty"`
+	AddressSet  *AddressSetEvent  `json:"addressSet,omitempty"`
+	Fulfillment *FulfillmentEvent `json:"fulfillment,omitempty"`
+	Return      *ReturnEvent      `json:"return,omitempty"`
+}
+
+const selectEvent = `SELECT id, order_id, type, payload, created, updated FROM events `
+
+func (r *postgresEventsRepository) Insert(event *Event) (*Event, error) {
+	if err := event.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	payload, err := json.Marshal(eventPayload{
+		PlaceEvent:  event.PlaceEvent,
+		Validation:  event.Validation,
+		Payment:     event.Payment,
+		CancelEvent: event.CancelEvent,
+		Refund:      event.Refund,
+		AddressSet:  event.AddressSet,
+		Fulfillment: event.Fulfillment,
+		Return:      event.Return,
+	})
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	id := event.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	var cartId, paymentId *string
+	if event.PlaceEvent != nil {
+		cartId = &event.PlaceEvent.CartId
+	}
+	if event.Payment != nil && event.Payment.PaymentId != "" {
+		paymentId = &event.Payment.PaymentId
+	}
+	if event.Refund != nil {
+		paymentId = &event.Refund.PaymentId
+	}
+
+	// El lock por orden serializa el cálculo de la versión hasta el commit. Dentro de una
+	// transacción corre en un savepoint, un error no deja abortada la transacción externa.
+	err = pgx.BeginFunc(context.Background(), r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(context.Background(), `SELECT pg_advisory_xact_lock(hashtext($1))`, event.OrderId); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(context.Background(), `
+			INSERT INTO events (id, order_id, version, type, cart_id, payment_id, payload, created, updated)
+			SELECT $1::text, $2::text, COALESCE(MAX(version), 0) + 1, $3::text, $4::text, $5::text,
+				$6::jsonb, $7::timestamptz, $8::timestamptz
+			FROM events WHERE order_id = $2`,
+			id.Hex(), event.OrderId, event.Type, cartId, paymentId, payload, event.Created, event.Updated,
+		)
+		return err
+	})
+
+	if pgdb.IsUniqueViolation(err, "events_place_cart") || pgdb.IsUniqueViolation(err, "events_payment_first") ||
+		pgdb.IsUniqueViolation(err, "events_validation_article") || pgdb.IsUniqueViolation(err, "events_refund_sequence") {
+		return nil, errs.AlreadyExist
+	}
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (r *postgresEventsRepository) FindPlaceByCartId(cartId string) (*Event, error) {
+	return r.findOne(selectEvent+"WHERE type = $1 AND cart_id = $2", Place, cartId)
+}
+
+// FindByOrderId devuelve los eventos de la orden en el orden en que se guardaron
+func (r *postgresEventsRepository) FindByOrderId(orderId string) ([]*Event, error) {
+	rows, err := r.db.Query(context.Background(), selectEvent+"WHERE order_id = $1 ORDER BY version", orderId)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	events, err := pgx.CollectRows(rows, scanEvent)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *postgresEventsRepository) FindPaymentByPaymentId(paymentId string) (*Event, error) {
+	return r.findOne(selectEvent+"WHERE type = $1 AND payment_id = $2 ORDER BY created LIMIT 1", Payment, paymentId)
+}
+
+// FindOrderIds devuelve los ids de todas las ordenes, toda orden empieza con un place_order
+func (r *postgresEventsRepository) FindOrderIds() ([]string, error) {
+	rows, err := r.db.Query(context.Background(), "SELECT order_id FROM events WHERE type = $1 ORDER BY seq", Place)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	orderIds, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return orderIds, nil
+}
+
+func (r *postgresEventsRepository) FindPayments(from time.Time, to time.Time) ([]*Event, error) {
+	rows, err := r.db.Query(context.Background(), selectEvent+"WHERE type = $1 AND created >= $2 AND created < $3", Payment, from, to)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	events, err := pgx.CollectRows(rows, scanEvent)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *postgresEventsRepository) findOne(query string, args ...any) (*Event, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	event, err := pgx.CollectOneRow(rows, scanEvent)
+	if err == pgx.ErrNoRows {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func scanEvent(row pgx.CollectableRow) (*Event, error) {
+	var id string
+	var payload []byte
+	var created, updated time.Time
+	event := &Event{}
+
+	if err := row.Scan(&id, &event.OrderId, &event.Type, &payload, &created, &updated); err != nil {
+		return nil, err
+	}
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := eventPayload{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	event.ID = objectId
+	event.PlaceEvent = data.PlaceEvent
+	event.Validation = data.Validation
+	event.Payment = data.Payment
+	event.CancelEvent = data.CancelEvent
+	event.Refund = data.Refund
+	event.AddressSet = data.AddressSet
+	event.Fulfillment = data.Fulfillment
+	event.Return = data.Return
+	event.Created = created.UTC()
+	event.Updated = updated.UTC()
+	return event, nil
+}
@@ -0,0 +1,13 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestPostgresEvents(t *testing.T) {
+	repotest.EventsRepository(t, events.NewPostgresEventsRepository(log.Get("", "test"), repotest.Postgres(t)))
+}
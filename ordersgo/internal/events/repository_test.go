@@ -0,0 +1,16 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+// TestMongoEvents corre contra MONGO_URL con las colecciones e índices del injector
+func TestMongoEvents(t *testing.T) {
+	deps := &di.Deps{CurrLog: log.Get("", "test"), CurrDatabase: repotest.Mongo(t)}
+	repotest.EventsRepository(t, events.NewEventsRepository(deps.Logger(), deps.EventsCollection()))
+}
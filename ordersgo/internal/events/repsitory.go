@@ -2,10 +2,13 @@ package events
 
 import (
 	"context"
+	"time"
 
 	"github.com/nmarsollier/commongo/db"
+	"github.com/nmarsollier/commongo/errs"
 	"github.com/nmarsollier/commongo/log"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type EventsRepository interface {
@@ -13,6 +16,9 @@ type EventsRepository interface {
 	FindPlaceByCartId(cartId string) (*Event, error)
 	FindByOrderId(orderId string) ([]*Event, error)
 	FindPaymentByPaymentId(paymentId string) (*Event, error)
+	FindOrderIds() ([]string, error)
+	// FindPayments eventos de pago creados entre from (incluido) y to (excluido)
+	FindPayments(from time.Time, to time.Time) ([]*Event, error)
 }
 
 func NewEventsRepository(log log.LogRusEntry, collection db.Collection) EventsRepository {
@@ -34,6 +40,9 @@ func (r *eventsRepository) Insert(event *Event) (*Event, error) {
 	}
 
 	if _, err := r.collection.InsertOne(context.Background(), event); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errs.AlreadyExist
+		}
 		r.log.Error(err)
 		return nil, err
 	}
@@ -53,9 +62,10 @@ func (r *eventsRepository) FindPlaceByCartId(cartId string) (*Event, error) {
 		},
 	}
 	if err := r.collection.FindOne(context.Background(), filter, event); err != nil {
-		if err.Error() != "mongo: no documents in result" {
-			r.log.Error(err)
+		if err == mongo.ErrNoDocuments {
+			return nil, errs.NotFound
 		}
+		r.log.Error(err)
 		return nil, err
 	}
 
@@ -91,17 +101,65 @@ func (r *eventsRepository) FindPaymentByPaymentId(paymentId string) (*Event, err
 	filter := bson.D{
 		{Key: "$and",
 			Value: bson.A{
-				bson.M{"paymentEvent.paymentId": paymentId},
+				bson.M{"payment.paymentId": paymentId},
 				bson.M{"type": Payment},
 			},
 		},
 	}
 	if err := r.collection.FindOne(context.Background(), filter, event); err != nil {
-		if err.Error() != "mongo: no documents in result" {
-			r.log.Error(err)
+		if err == mongo.ErrNoDocuments {
+			return nil, errs.NotFound
 		}
+		r.log.Error(err)
 		return nil, err
 	}
 
 	return event, nil
 }
+
+// FindOrderIds devuelve los ids de todas las ordenes, toda orden empieza con un place_order
+func (r *eventsRepository) FindOrderIds() ([]string, error) {
+	cur, err := r.collection.Find(context.Background(), bson.M{"type": Place})
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	orderIds := []string{}
+	for cur.Next(context.Background()) {
+		event := &Event{}
+		if err := cur.Decode(event); err != nil {
+			r.log.Error(err)
+			return nil, err
+		}
+		orderIds = append(orderIds, event.OrderId)
+	}
+
+	return orderIds, nil
+}
+
+func (r *eventsRepository) FindPayments(from time.Time, to time.Time) ([]*Event, error) {
+	filter := bson.M{
+		"type":    Payment,
+		"created": bson.M{"$gte": from, "$lt": to},
+	}
+	cur, err := r.collection.Find(context.Background(), filter)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	events := []*Event{}
+	for cur.Next(context.Background()) {
+		event := &Event{}
+		if err := cur.Decode(event); err != nil {
+			r.log.Error(err)
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,185 @@
+package events
+
+import (
+	"time"
+
+	"github.com/nmarsollier/commongo/errs"
+)
+
+// ReturnStatus estado de una devolución, cada evento de devolución es una transición
+type ReturnStatus string
+
+const (
+	ReturnRequested ReturnStatus = "requested"
+	ReturnApproved  ReturnStatus = "approved"
+	ReturnRejected  ReturnStatus = "rejected"
+	ReturnReceived  ReturnStatus = "received"
+)
+
+// returnTransitions estados a los que se puede pasar desde cada estado, "" es una devolución que no existe
+var returnTransitions = map[ReturnStatus][]ReturnStatus{
+	"":              {ReturnRequested},
+	ReturnRequested: {ReturnApproved, ReturnRejected},
+	ReturnApproved:  {ReturnReceived},
+}
+
+// IsValid indica si es un estado conocido
+func (s ReturnStatus) IsValid() bool {
+	switch s {
+	case ReturnRequested, ReturnApproved, ReturnRejected, ReturnReceived:
+		return true
+	}
+	return false
+}
+
+// CanTransition indica si una devolución en este estado puede pasar a next
+func (s ReturnStatus) CanTransition(next ReturnStatus) bool {
+	for _, allowed := range returnTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ReturnEvent transición de la devolución. Al pedirla se informan el usuario, el motivo y las líneas,
+// al recibirla el valor de cada línea a reembolsar.
+type ReturnEvent struct {
+	ReturnId string        `bson:"returnId" json:"returnId" binding:"required,max=100"`
+	Status   ReturnStatus  `bson:"status" json:"status" binding:"required"`
+	UserId   string        `bson:"userId,omitempty" json:"userId,omitempty" binding:"max=100"`
+	Reason   string        `bson:"reason,omitempty" json:"reason,omitempty" binding:"max=500"`
+	Lines    []*ReturnLine `bson:"lines,omitempty" json:"lines,omitempty" binding:"dive"`
+	Amount   float32       `bson:"amount,omitempty" json:"amount,omitempty"`
+}
+
+// ReturnLine artículo y cantidad que se devuelven, Amount es el valor de la línea
+type ReturnLine struct {
+	ArticleId string  `bson:"articleId" json:"articleId" binding:"required,min=1,max=100"`
+	Quantity  int     `bson:"quantity" json:"quantity" binding:"required,min=1"`
+	Amount    float32 `bson:"amount,omitempty" json:"amount,omitempty"`
+}
+
+// Validate valida el evento sin considerar el estado actual de la devolución
+func (e *ReturnEvent) Validate() error {
+	if !e.Status.IsValid() {
+		return errs.NewValidation().Add("status", "invalid return status")
+	}
+	if err := validateBinding(e); err != nil {
+		return err
+	}
+	if e.Status == ReturnRequested {
+		if e.Reason == "" {
+			return errs.NewValidation().Add("reason", "required")
+		}
+		if len(e.Lines) == 0 {
+			return errs.NewValidation().Add("lines", "required")
+		}
+	}
+	return nil
+}
+
+// ReturnState estado de la devolución que resulta de aplicar sus transiciones en orden.
+// Todas las líneas avanzan juntas, cada una informa su estado.
+type ReturnState struct {
+	ReturnId    string              `bson:"returnId" json:"returnId"`
+	UserId      string              `bson:"userId" json:"userId"`
+	Status      ReturnStatus        `bson:"status" json:"status"`
+	Reason      string              `bson:"reason" json:"reason"`
+	Lines       []*ReturnLineState  `bson:"lines" json:"lines"`
+	Amount      float32             `bson:"amount" json:"amount"`
+	Transitions []*ReturnTransition `bson:"transitions" json:"transitions"`
+}
+
+type ReturnLineState struct {
+	ArticleId string       `bson:"articleId" json:"articleId"`
+	Quantity  int          `bson:"quantity" json:"quantity"`
+	Amount    float32      `bson:"amount" json:"amount"`
+	Status    ReturnStatus `bson:"status" json:"status"`
+}
+
+type ReturnTransition struct {
+	Status ReturnStatus `bson:"status" json:"status"`
+	Reason string       `bson:"reason,omitempty" json:"reason,omitempty"`
+	Amount float32      `bson:"amount,omitempty" json:"amount,omitempty"`
+	Time   time.Time    `bson:"time" json:"time"`
+}
+
+// Apply aplica la transición del evento, devuelve false si no es válida desde el estado actual
+func (r *ReturnState) Apply(e *Event) bool {
+	data := e.Return
+	if !r.Status.CanTransition(data.Status) {
+		return false
+	}
+
+	r.Status = data.Status
+	switch data.Status {
+	case ReturnRequested:
+		r.ReturnId = data.ReturnId
+		r.UserId = data.UserId
+		r.Reason = data.Reason
+		r.Lines = make([]*ReturnLineState, len(data.Lines))
+		for i, line := range data.Lines {
+			r.Lines[i] = &ReturnLineState{
+				ArticleId: line.ArticleId,
+				Quantity:  line.Quantity,
+			}
+		}
+	case ReturnReceived:
+		r.Amount = data.Amount
+		for _, line := range r.Lines {
+			for _, received := range data.Lines {
+				if received.ArticleId == line.ArticleId {
+					line.Amount = received.Amount
+				}
+			}
+		}
+	}
+	for _, line := range r.Lines {
+		line.Status = data.Status
+	}
+
+	r.Transitions = append(r.Transitions, &ReturnTransition{
+		Status: data.Status,
+		Reason: data.Reason,
+		Amount: data.Amount,
+		Time:   e.Created,
+	})
+	return true
+}
+
+// FoldReturns devoluciones de la orden en el orden en que se pidieron
+func FoldReturns(ev []*Event) []*ReturnState {
+	result := []*ReturnState{}
+	for _, e := range ev {
+		if e.Type != Return {
+			continue
+		}
+
+		var current *ReturnState
+		for _, r := range result {
+			if r.ReturnId == e.Return.ReturnId {
+				current = r
+			}
+		}
+		if current == nil {
+			current = &ReturnState{}
+			if current.Apply(e) {
+				result = append(result, current)
+			}
+			continue
+		}
+		current.Apply(e)
+	}
+	return result
+}
+
+func newReturnEvent(orderId string, data *ReturnEvent) *Event {
+	return &Event{
+		OrderId: orderId,
+		Type:    Return,
+		Return:  data,
+		Created: time.Now(),
+		Updated: time.Now(),
+	}
+}
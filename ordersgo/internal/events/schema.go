@@ -16,13 +16,26 @@ const (
 	Debit  PaymentMethod = "DEBIT"
 )
 
+// IsValid indica si es un medio de pago conocido
+func (m PaymentMethod) IsValid() bool {
+	switch m {
+	case Cash, Credit, Debit:
+		return true
+	}
+	return false
+}
+
 type EventType string
 
 const (
-	Place      EventType = "place_order"
-	Validation EventType = "aticle_validation"
-	Payment    EventType = "payment"
-	Cancel     EventType = "order_canceled"
+	Place       EventType = "place_order"
+	Validation  EventType = "aticle_validation"
+	Payment     EventType = "payment"
+	Cancel      EventType = "order_canceled"
+	Refund      EventType = "payment_refund"
+	AddressSet  EventType = "address_set"
+	Fulfillment EventType = "fulfillment"
+	Return      EventType = "order_return"
 )
 
 // Estuctura basica de del evento
@@ -34,6 +47,10 @@ type Event struct {
 	Validation  *ValidationEvent   `bson:"validation"`
 	Payment     *PaymentEvent      `bson:"payment"`
 	CancelEvent *CancelEvent       `bson:"cancelEvent"`
+	Refund      *RefundEvent       `bson:"refund"`
+	AddressSet  *AddressSetEvent   `bson:"addressSet"`
+	Fulfillment *FulfillmentEvent  `bson:"fulfillment"`
+	Return      *ReturnEvent       `bson:"return"`
 	Created     time.Time          `bson:"created"`
 	Updated     time.Time          `bson:"updated"`
 }
@@ -47,6 +64,7 @@ type PlaceEvent struct {
 	CartId   string    `bson:"cartId"`
 	UserId   string    `bson:"userId" `
 	Articles []Article `bson:"articles" `
+	Delivery *Delivery `bson:"delivery,omitempty"`
 }
 
 type Article struct {
@@ -60,7 +78,7 @@ type PaymentEvent struct {
 	Amount        float32 `bson:"amount" binding:"required"`
 	PaymentId     string `bson:"paymentId"`
 	TransactionId string `bson:"transactionId"`
-	Status        string `bson:"status"` // approved, rejected, refunded
+	Status        PaymentStatus `bson:"status"`
 	ErrorMessage  string `bson:"errorMessage,omitempty"`
 	ErrorCode     string `bson:"errorCode,omitempty"`
 }
@@ -73,6 +91,64 @@ type ValidationEvent struct {
 	Price       float32 `bson:"price" json:"price"`
 }
 
+// RefundEvent reembolso de un pago capturado, un pago puede tener varios reembolsos parciales.
+// RefundId es opcional, los reembolsos con el mismo id se guardan una vez.
+type RefundEvent struct {
+	OrderId   string  `bson:"orderId" json:"orderId"`
+	PaymentId string  `bson:"paymentId" json:"paymentId"`
+	RefundId  string  `bson:"refundId,omitempty" json:"refundId,omitempty"`
+	Amount    float32 `bson:"amount" json:"amount"`
+	Reason    string  `bson:"reason,omitempty" json:"reason,omitempty"`
+	// Sequence número del reembolso en el pago, lo asigna SaveRefund. El índice único por pago y número
+	// hace que de dos reembolsos concurrentes se guarde uno solo.
+	Sequence int `bson:"sequence,omitempty" json:"sequence,omitempty"`
+}
+
+// Delivery datos de entrega de la orden, todos los campos son opcionales
+type Delivery struct {
+	ShippingAddress *Address `bson:"shippingAddress,omitempty" json:"shippingAddress,omitempty"`
+	BillingAddress  *Address `bson:"billingAddress,omitempty" json:"billingAddress,omitempty"`
+	ContactPhone    string   `bson:"contactPhone,omitempty" json:"contactPhone,omitempty" binding:"omitempty,e164" example:"+5491144445555"`
+	Instructions    string   `bson:"instructions,omitempty" json:"instructions,omitempty" binding:"max=500"`
+}
+
+type Address struct {
+	Line1      string `bson:"line1" json:"line1" binding:"required,min=1,max=200"`
+	Line2      string `bson:"line2,omitempty" json:"line2,omitempty" binding:"max=200"`
+	City       string `bson:"city" json:"city" binding:"required,min=1,max=100"`
+	State      string `bson:"state,omitempty" json:"state,omitempty" binding:"max=100"`
+	PostalCode string `bson:"postalCode" json:"postalCode" binding:"required,min=1,max=20"`
+	Country    string `bson:"country" json:"country" binding:"required,iso3166_1_alpha2" example:"AR"`
+}
+
+// IsEmpty indica si no tiene ningún dato
+func (d *Delivery) IsEmpty() bool {
+	return d.ShippingAddress == nil && d.BillingAddress == nil && d.ContactPhone == "" && d.Instructions == ""
+}
+
+// Merge reemplaza los datos informados en changes y conserva el resto
+func (d Delivery) Merge(changes *Delivery) *Delivery {
+	if changes.ShippingAddress != nil {
+		d.ShippingAddress = changes.ShippingAddress
+	}
+	if changes.BillingAddress != nil {
+		d.BillingAddress = changes.BillingAddress
+	}
+	if changes.ContactPhone != "" {
+		d.ContactPhone = changes.ContactPhone
+	}
+	if changes.Instructions != "" {
+		d.Instructions = changes.Instructions
+	}
+	return &d
+}
+
+// AddressSetEvent cambios en los datos de entrega posteriores a la colocación
+type AddressSetEvent struct {
+	UserId   string   `bson:"userId" json:"userId"`
+	Delivery Delivery `bson:"delivery" json:"delivery"`
+}
+
 type CancelEvent struct {
 	UserId string `bson:"userId"`
 	Reason string `bson:"reason"`
@@ -104,6 +180,26 @@ func newPaymentEvent(
 	}
 }
 
+func newRefundEvent(refundEvent *RefundEvent) *Event {
+	return &Event{
+		OrderId: refundEvent.OrderId,
+		Type:    Refund,
+		Refund:  refundEvent,
+		Created: time.Now(),
+		Updated: time.Now(),
+	}
+}
+
+func newAddressSetEvent(orderId string, addressSet *AddressSetEvent) *Event {
+	return &Event{
+		OrderId:    orderId,
+		Type:       AddressSet,
+		AddressSet: addressSet,
+		Created:    time.Now(),
+		Updated:    time.Now(),
+	}
+}
+
 // ValidationEvent Nueva instancia de validation event
 func newValidationEvent(
 	validationEvent *ValidationEvent,
@@ -1,35 +1,62 @@
 package events
 
 import (
+	"reflect"
+	"strings"
+	"time"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/nmarsollier/commongo/errs"
 	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/metrics"
 )
 
 type EventService interface {
 	SavePlaceOrder(data *PlacedOrderData) (*Event, error)
 	SavePayment(data *PaymentEvent) (*Event, error)
+	SaveRefund(data *RefundEvent) (*Event, error)
+	SaveAddressSet(orderId string, userId string, data *Delivery) (*Event, error)
+	SaveFulfillment(orderId string, data *FulfillmentEvent) (*Event, error)
+	SaveReturn(orderId string, data *ReturnEvent) (*Event, error)
 	SaveArticleExist(data *ValidationEvent) (*Event, error)
 	NewCancelEvent(orderId, userId, reason string) *Event
 	Save(event *Event) (*Event, error)
 	FindByOrderId(orderId string) ([]*Event, error)
+	FindOrderIds() ([]string, error)
+	FindPayments(from time.Time, to time.Time) ([]*Event, error)
 }
 
-func NewEventService(log log.LogRusEntry, repository EventsRepository) EventService {
+func NewEventService(log log.LogRusEntry, metrics *metrics.Metrics, repository EventsRepository) EventService {
 	return &eventService{
 		log:        log,
+		metrics:    metrics,
 		repository: repository,
 	}
 }
 
 type eventService struct {
 	log        log.LogRusEntry
+	metrics    *metrics.Metrics
 	repository EventsRepository
 }
 
-// SaveArticleExist saves the event for article exist
+// insert guarda el evento registrando la latencia del event store
+func (s *eventService) insert(event *Event) (*Event, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.EventStoreInsert.WithLabelValues(string(event.Type)).Observe(time.Since(start).Seconds())
+	}()
+
+	return s.repository.Insert(event)
+}
+
+// SaveArticleExist saves the event for article exist, si el artículo ya se validó devuelve el evento guardado
 func (s *eventService) SaveArticleExist(data *ValidationEvent) (*Event, error) {
-	event, err := s.repository.Insert(newValidationEvent(data))
+	if existing, err := s.findValidation(data.ReferenceId, data.ArticleId); existing != nil || err != nil {
+		return existing, err
+	}
+
+	event, err := s.insert(newValidationEvent(data))
 
 	if err != nil {
 		return nil, err
@@ -40,20 +67,21 @@ func (s *eventService) SaveArticleExist(data *ValidationEvent) (*Event, error) {
 
 // SavePlaceOrder saves the event for place order
 func (s *eventService) SavePlaceOrder(data *PlacedOrderData) (*Event, error) {
+	if err := validateBinding(data); err != nil {
+		s.log.Error("Invalid NewPlaceData Data", err)
+		return nil, err
+	}
+
 	if e, _ := s.repository.FindPlaceByCartId(data.CartId); e != nil {
 		s.log.Error("Place already exist")
 		return nil, errs.AlreadyExist
 	}
 
-	if err := validator.New().Struct(data); err != nil {
-		s.log.Error("Invalid NewPlaceData Data", err)
-		return nil, err
-	}
-
 	event := s.placeOrderToEvent(data)
-	event, err := s.repository.Insert(event)
+	event, err := s.insert(event)
 
 	if err != nil {
+		// El indice unico por cartId detecta los place concurrentes
 		s.log.Error(err)
 		return nil, err
 	}
@@ -64,7 +92,8 @@ func (s *eventService) SavePlaceOrder(data *PlacedOrderData) (*Event, error) {
 type PlacedOrderData struct {
 	CartId   string                  `json:"cartId" binding:"required,min=1,max=100"`
 	UserId   string                  `json:"userId" binding:"required,min=1,max=100"`
-	Articles []PlacePrderArticleData `json:"articles" binding:"required,gt=0"`
+	Articles []PlacePrderArticleData `json:"articles" binding:"required,gt=0,dive"`
+	Delivery *Delivery               `json:"delivery,omitempty"`
 }
 
 type PlacePrderArticleData struct {
@@ -72,6 +101,29 @@ type PlacePrderArticleData struct {
 	Quantity int    `json:"quantity" binding:"required,min=1"`
 }
 
+// validateBinding valida con los tags binding que usa gin, así rabbit y GraphQL reciben los mismos
+// errores que REST
+func validateBinding(data interface{}) error {
+	validate := validator.New()
+	validate.SetTagName("binding")
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.Split(field.Tag.Get("json"), ",")[0]
+	})
+
+	err := validate.Struct(data)
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	result := errs.NewValidation()
+	for _, e := range validationErrors {
+		path := strings.SplitN(e.Namespace(), ".", 2)
+		result.Add(path[len(path)-1], e.Tag())
+	}
+	return result
+}
+
 func (s *eventService) placeOrderToEvent(event *PlacedOrderData) *Event {
 	articles := make([]Article, len(event.Articles))
 	for index, item := range event.Articles {
@@ -85,18 +137,127 @@ func (s *eventService) placeOrderToEvent(event *PlacedOrderData) *Event {
 		CartId:   event.CartId,
 		UserId:   event.UserId,
 		Articles: articles,
+		Delivery: event.Delivery,
 	})
 }
 
-// SavePayment saves a payment event
+// SaveAddressSet saves the changes to the delivery details, only the informed fields are replaced
+func (s *eventService) SaveAddressSet(orderId string, userId string, data *Delivery) (*Event, error) {
+	if data.IsEmpty() {
+		return nil, errs.NewValidation().Add("delivery", "required")
+	}
+
+	if err := validateBinding(data); err != nil {
+		return nil, err
+	}
+
+	return s.insert(newAddressSetEvent(orderId, &AddressSetEvent{
+		UserId:   userId,
+		Delivery: *data,
+	}))
+}
+
+// SaveFulfillment saves a fulfillment transition, the transition must be valid from the current status
+func (s *eventService) SaveFulfillment(orderId string, data *FulfillmentEvent) (*Event, error) {
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+
+	orderEvents, err := s.repository.FindByOrderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	state := FoldFulfillment(orderEvents)
+	if state == nil {
+		state = &FulfillmentState{}
+	}
+
+	var last *Event
+	for _, e := range orderEvents {
+		if e.Type == Fulfillment {
+			last = e
+		}
+	}
+
+	// Idempotencia, un envío que ya está en el estado con los mismos datos no se vuelve a guardar
+	duplicate, err := checkTransition(s.log, "fulfillment "+orderId, state.Status, data.Status, last, func(e *Event) bool {
+		return e.Fulfillment.Carrier == data.Carrier &&
+			e.Fulfillment.TrackingNumber == data.TrackingNumber &&
+			e.Fulfillment.Reason == data.Reason
+	})
+	if duplicate != nil || err != nil {
+		return duplicate, err
+	}
+
+	return s.insert(newFulfillmentEvent(orderId, data))
+}
+
+// SaveReturn saves a return transition, the transition must be valid from the current return status
+func (s *eventService) SaveReturn(orderId string, data *ReturnEvent) (*Event, error) {
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+
+	orderEvents, err := s.repository.FindByOrderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &ReturnState{}
+	for _, r := range FoldReturns(orderEvents) {
+		if r.ReturnId == data.ReturnId {
+			state = r
+		}
+	}
+
+	var last *Event
+	for _, e := range orderEvents {
+		if e.Type == Return && e.Return.ReturnId == data.ReturnId {
+			last = e
+		}
+	}
+
+	// Idempotencia, una devolución que ya está en el estado no se vuelve a guardar
+	duplicate, err := checkTransition(s.log, "return "+data.ReturnId, state.Status, data.Status, last, nil)
+	if duplicate != nil || err != nil {
+		return duplicate, err
+	}
+
+	return s.insert(newReturnEvent(orderId, data))
+}
+
+// SavePayment saves a payment transition, the transition must be valid from the current payment status
 func (s *eventService) SavePayment(data *PaymentEvent) (*Event, error) {
-	// Verificar si el pago ya existe (idempotencia)
-	if existingEvent, _ := s.repository.FindPaymentByPaymentId(data.PaymentId); existingEvent != nil {
-		s.log.Info("Payment event already exists, skipping duplicate: ", data.PaymentId)
-		return existingEvent, nil
+	if !data.Status.IsValid() {
+		return nil, errs.NewValidation().Add("status", "invalid payment status")
+	}
+	data.Status = data.Status.Normalize()
+	if data.Status == PaymentRefunded || data.Status == PaymentPartiallyRefunded {
+		return nil, errs.NewValidation().Add("status", "refunds are saved with SaveRefund")
+	}
+
+	if data.PaymentId != "" {
+		existing, err := s.findPayment(data.OrderId, data.PaymentId)
+		if err != nil {
+			return nil, err
+		}
+
+		state := &PaymentState{PaymentId: data.PaymentId}
+		var last *Event
+		for _, e := range existing {
+			state.Apply(e)
+			last = e
+		}
+
+		// Idempotencia, un pago que ya está en el estado no se vuelve a guardar
+		duplicate, err := checkTransition(s.log, "payment "+data.PaymentId, state.Status, data.Status, last, nil)
+		if duplicate != nil || err != nil {
+			return duplicate, err
+		}
 	}
 
-	event, err := s.repository.Insert(newPaymentEvent(data))
+	event, err := s.insert(newPaymentEvent(data))
 
 	if err != nil {
 		return nil, err
@@ -105,6 +266,75 @@ func (s *eventService) SavePayment(data *PaymentEvent) (*Event, error) {
 	return event, nil
 }
 
+// SaveRefund saves a refund of a captured payment, the amount can't exceed what remains of the payment
+func (s *eventService) SaveRefund(data *RefundEvent) (*Event, error) {
+	if data.PaymentId == "" {
+		return nil, errs.NewValidation().Add("paymentId", "required")
+	}
+
+	existing, err := s.findPayment(data.OrderId, data.PaymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &PaymentState{PaymentId: data.PaymentId}
+	refunds := 0
+	for _, e := range existing {
+		if e.Type == Refund && data.RefundId != "" && e.Refund.RefundId == data.RefundId {
+			s.log.Info("Refund already exists, skipping duplicate: ", data.RefundId)
+			return e, nil
+		}
+		if e.Type == Refund {
+			refunds++
+		}
+		state.Apply(e)
+	}
+
+	if len(existing) == 0 {
+		return nil, errs.NotFound
+	}
+	if !state.CanRefund(data.Amount) {
+		s.log.Error("Invalid refund ", data.PaymentId, ": ", data.Amount, " of ", state.Remaining(), " ", state.Status)
+		return nil, errs.NewValidation().Add("amount", "must be greater than 0 and up to the remaining amount of a captured payment")
+	}
+
+	// Si otro reembolso del pago se guardó después de la lectura ya ocupó este número, el insert
+	// devuelve errs.AlreadyExist
+	data.Sequence = refunds + 1
+	return s.insert(newRefundEvent(data))
+}
+
+// findPayment eventos de pago y reembolso del pago en el orden en que se guardaron
+func (s *eventService) findPayment(orderId string, paymentId string) ([]*Event, error) {
+	orderEvents, err := s.repository.FindByOrderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Event{}
+	for _, e := range orderEvents {
+		if IsPaymentEvent(e) && e.PaymentId() == paymentId {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+// findValidation la validación del artículo en la orden, nil si todavía no se validó
+func (s *eventService) findValidation(orderId string, articleId string) (*Event, error) {
+	orderEvents, err := s.repository.FindByOrderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range orderEvents {
+		if e.Type == Validation && e.Validation != nil && e.Validation.ArticleId == articleId {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
 // NewCancelEvent creates a new cancel event
 func (s *eventService) NewCancelEvent(orderId, userId, reason string) *Event {
 	return NewCancelEvent(orderId, userId, reason)
@@ -112,7 +342,7 @@ func (s *eventService) NewCancelEvent(orderId, userId, reason string) *Event {
 
 // Save saves an event directly
 func (s *eventService) Save(event *Event) (*Event, error) {
-	savedEvent, err := s.repository.Insert(event)
+	savedEvent, err := s.insert(event)
 	if err != nil {
 		return nil, err
 	}
@@ -123,3 +353,13 @@ func (s *eventService) Save(event *Event) (*Event, error) {
 func (s *eventService) FindByOrderId(orderId string) ([]*Event, error) {
 	return s.repository.FindByOrderId(orderId)
 }
+
+// FindOrderIds returns the ids of all the orders in the event store
+func (s *eventService) FindOrderIds() ([]string, error) {
+	return s.repository.FindOrderIds()
+}
+
+// FindPayments returns the payment events created in the range
+func (s *eventService) FindPayments(from time.Time, to time.Time) ([]*Event, error) {
+	return s.repository.FindPayments(from, to)
+}
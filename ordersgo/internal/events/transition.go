@@ -0,0 +1,46 @@
+package events
+
+import (
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+)
+
+// lifecycleStatus estado de un ciclo de vida con transiciones, pagos, envíos y devoluciones
+type lifecycleStatus[S any] interface {
+	~string
+	CanTransition(next S) bool
+}
+
+// checkTransition decide si se guarda el paso de current a next.
+// last es el último evento de la misma entidad, si ya la dejó en next es una repetición: se devuelve
+// ese evento cuando same indica que trae los mismos datos (same nil compara solo el estado), si no
+// es un error de validación. Devuelve nil, nil cuando la transición se puede guardar.
+func checkTransition[S lifecycleStatus[S]](
+	log log.LogRusEntry,
+	subject string,
+	current S,
+	next S,
+	last *Event,
+	same func(*Event) bool,
+) (*Event, error) {
+	if last != nil && current == next {
+		if same == nil || same(last) {
+			log.Info("Transition already exists, skipping duplicate: ", subject)
+			return last, nil
+		}
+
+		log.Error("Repeated transition with different data ", subject, ": ", next)
+		return nil, errs.NewValidation().Add("status", "already "+string(next)+" with different data")
+	}
+
+	if !current.CanTransition(next) {
+		log.Error("Invalid transition ", subject, ": ", current, " -> ", next)
+		message := "invalid transition to " + string(next)
+		if current != "" {
+			message += " from " + string(current)
+		}
+		return nil, errs.NewValidation().Add("status", message)
+	}
+
+	return nil, nil
+}
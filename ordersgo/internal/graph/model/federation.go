@@ -153,6 +153,25 @@ func (ec *executionContext) resolveEntity(
 	}()
 
 	switch typeName {
+	case "Article":
+		resolverName, err := entityResolverNameForArticle(ctx, rep)
+		if err != nil {
+			return nil, fmt.Errorf(`finding resolver for Entity "Article": %w`, err)
+		}
+		switch resolverName {
+
+		case "findArticleByID":
+			id0, err := ec.unmarshalNString2string(ctx, rep["id"])
+			if err != nil {
+				return nil, fmt.Errorf(`unmarshalling param 0 for findArticleByID(): %w`, err)
+			}
+			entity, err := ec.resolvers.Entity().FindArticleByID(ctx, id0)
+			if err != nil {
+				return nil, fmt.Errorf(`resolving Entity "Article": %w`, err)
+			}
+
+			return entity, nil
+		}
 	case "Order":
 		resolverName, err := entityResolverNameForOrder(ctx, rep)
 		if err != nil {
@@ -198,6 +217,33 @@ func (ec *executionContext) resolveManyEntities(
 	}
 }
 
+func entityResolverNameForArticle(ctx context.Context, rep EntityRepresentation) (string, error) {
+	for {
+		var (
+			m   EntityRepresentation
+			val interface{}
+			ok  bool
+		)
+		_ = val
+		// if all of the KeyFields values for this resolver are null,
+		// we shouldn't use use it
+		allNull := true
+		m = rep
+		val, ok = m["id"]
+		if !ok {
+			break
+		}
+		if allNull {
+			allNull = val == nil
+		}
+		if allNull {
+			break
+		}
+		return "findArticleByID", nil
+	}
+	return "", fmt.Errorf("%w for Article", ErrTypeNotFound)
+}
+
 func entityResolverNameForOrder(ctx context.Context, rep EntityRepresentation) (string, error) {
 	for {
 		var (
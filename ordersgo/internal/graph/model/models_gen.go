@@ -6,14 +6,46 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 )
 
+type Address struct {
+	Line1      string  `json:"line1"`
+	Line2      *string `json:"line2,omitempty"`
+	City       string  `json:"city"`
+	State      *string `json:"state,omitempty"`
+	PostalCode string  `json:"postalCode"`
+	Country    string  `json:"country"`
+}
+
+type AddressInput struct {
+	Line1      string  `json:"line1"`
+	Line2      *string `json:"line2,omitempty"`
+	City       string  `json:"city"`
+	State      *string `json:"state,omitempty"`
+	PostalCode string  `json:"postalCode"`
+	Country    string  `json:"country"`
+}
+
 type Article struct {
-	ID string `json:"id"`
+	ID     string         `json:"id"`
+	Demand *ArticleDemand `json:"demand,omitempty"`
 }
 
 func (Article) IsEntity() {}
 
+type ArticleDemand struct {
+	ArticleID        string  `json:"articleId"`
+	Orders           int     `json:"orders"`
+	QuantityOrdered  int     `json:"quantityOrdered"`
+	QuantityPaid     int     `json:"quantityPaid"`
+	QuantityCanceled int     `json:"quantityCanceled"`
+	Validations      int     `json:"validations"`
+	Invalid          int     `json:"invalid"`
+	InvalidRate      float64 `json:"invalidRate"`
+	Revenue          float64 `json:"revenue"`
+}
+
 type ArticleInput struct {
 	ArticleID    string  `json:"articleId"`
 	Quantity     int     `json:"quantity"`
@@ -22,17 +54,58 @@ type ArticleInput struct {
 	IsValidated  bool    `json:"isValidated"`
 }
 
+type CustomerOrderStats struct {
+	UserID            string              `json:"userId"`
+	Orders            int                 `json:"orders"`
+	ByStatus          []*StatusCount      `json:"byStatus"`
+	TotalSpent        float64             `json:"totalSpent"`
+	TotalRefunded     float64             `json:"totalRefunded"`
+	AverageOrderValue float64             `json:"averageOrderValue"`
+	FirstOrder        *time.Time          `json:"firstOrder,omitempty"`
+	LastOrder         *time.Time          `json:"lastOrder,omitempty"`
+	FavouriteArticles []*FavouriteArticle `json:"favouriteArticles"`
+}
+
+type Delivery struct {
+	ShippingAddress *Address `json:"shippingAddress,omitempty"`
+	BillingAddress  *Address `json:"billingAddress,omitempty"`
+	ContactPhone    *string  `json:"contactPhone,omitempty"`
+	Instructions    *string  `json:"instructions,omitempty"`
+}
+
+type DeliveryInput struct {
+	ShippingAddress *AddressInput `json:"shippingAddress,omitempty"`
+	BillingAddress  *AddressInput `json:"billingAddress,omitempty"`
+	ContactPhone    *string       `json:"contactPhone,omitempty"`
+	Instructions    *string       `json:"instructions,omitempty"`
+}
+
+type FavouriteArticle struct {
+	ArticleID string `json:"articleId"`
+	Quantity  int    `json:"quantity"`
+	Orders    int    `json:"orders"`
+}
+
+type Fulfillment struct {
+	Status         string  `json:"status"`
+	Carrier        *string `json:"carrier,omitempty"`
+	TrackingNumber *string `json:"trackingNumber,omitempty"`
+}
+
 type Mutation struct {
 }
 
 type Order struct {
-	ID       string          `json:"id"`
-	OrderID  string          `json:"orderId"`
-	Status   OrderStatus     `json:"status"`
-	UserID   string          `json:"userId"`
-	CartID   string          `json:"cartId"`
-	Articles []*OrderArticle `json:"articles,omitempty"`
-	Payments []*PaymentEvent `json:"payments,omitempty"`
+	ID          string          `json:"id"`
+	OrderID     string          `json:"orderId"`
+	Status      OrderStatus     `json:"status"`
+	UserID      string          `json:"userId"`
+	CartID      string          `json:"cartId"`
+	Articles    []*OrderArticle `json:"articles,omitempty"`
+	Payments    []*PaymentEvent `json:"payments,omitempty"`
+	Delivery    *Delivery       `json:"delivery,omitempty"`
+	Fulfillment *Fulfillment    `json:"fulfillment,omitempty"`
+	Returns     []*Return       `json:"returns,omitempty"`
 }
 
 func (Order) IsEntity() {}
@@ -46,6 +119,19 @@ type OrderArticle struct {
 	IsValidated  bool     `json:"isValidated"`
 }
 
+type OrderMilestone struct {
+	Type           MilestoneType `json:"type"`
+	Time           time.Time     `json:"time"`
+	ArticleID      *string       `json:"articleId,omitempty"`
+	Valid          *bool         `json:"valid,omitempty"`
+	PaymentID      *string       `json:"paymentId,omitempty"`
+	Amount         *float64      `json:"amount,omitempty"`
+	Reason         *string       `json:"reason,omitempty"`
+	Carrier        *string       `json:"carrier,omitempty"`
+	TrackingNumber *string       `json:"trackingNumber,omitempty"`
+	ReturnID       *string       `json:"returnId,omitempty"`
+}
+
 type OrderSummary struct {
 	ID           string      `json:"id"`
 	Status       OrderStatus `json:"status"`
@@ -55,9 +141,16 @@ type OrderSummary struct {
 	Articles     int         `json:"articles"`
 }
 
+type OrderTimeline struct {
+	OrderID    string            `json:"orderId"`
+	UserID     string            `json:"userId"`
+	Milestones []*OrderMilestone `json:"milestones"`
+}
+
 type PaymentEvent struct {
 	Method PaymentMethod `json:"method"`
 	Amount float64       `json:"amount"`
+	Status *string       `json:"status,omitempty"`
 }
 
 type PaymentEventInput struct {
@@ -65,9 +158,124 @@ type PaymentEventInput struct {
 	Amount float64       `json:"amount"`
 }
 
+type PlaceOrderArticleInput struct {
+	ID       string `json:"id"`
+	Quantity int    `json:"quantity"`
+}
+
+type PlaceOrderInput struct {
+	CartID   string                    `json:"cartId"`
+	UserID   string                    `json:"userId"`
+	Articles []*PlaceOrderArticleInput `json:"articles"`
+	Delivery *DeliveryInput            `json:"delivery,omitempty"`
+}
+
 type Query struct {
 }
 
+type Return struct {
+	ReturnID string        `json:"returnId"`
+	Status   string        `json:"status"`
+	Reason   string        `json:"reason"`
+	Amount   float64       `json:"amount"`
+	Lines    []*ReturnLine `json:"lines"`
+}
+
+type ReturnInput struct {
+	Reason string             `json:"reason"`
+	Lines  []*ReturnLineInput `json:"lines"`
+}
+
+type ReturnLine struct {
+	ArticleID string  `json:"articleId"`
+	Quantity  int     `json:"quantity"`
+	Amount    float64 `json:"amount"`
+	Status    string  `json:"status"`
+}
+
+type ReturnLineInput struct {
+	ArticleID string `json:"articleId"`
+	Quantity  int    `json:"quantity"`
+}
+
+type StatusCount struct {
+	Status string `json:"status"`
+	Count  int    `json:"count"`
+}
+
+type MilestoneType string
+
+const (
+	MilestoneTypePlaced           MilestoneType = "PLACED"
+	MilestoneTypeArticleValidated MilestoneType = "ARTICLE_VALIDATED"
+	MilestoneTypeValidated        MilestoneType = "VALIDATED"
+	MilestoneTypeInvalid          MilestoneType = "INVALID"
+	MilestoneTypeFirstPayment     MilestoneType = "FIRST_PAYMENT"
+	MilestoneTypeFullyPaid        MilestoneType = "FULLY_PAID"
+	MilestoneTypeRefunded         MilestoneType = "REFUNDED"
+	MilestoneTypeChargeback       MilestoneType = "CHARGEBACK"
+	MilestoneTypeCanceled         MilestoneType = "CANCELED"
+	MilestoneTypePacking          MilestoneType = "PACKING"
+	MilestoneTypeShipped          MilestoneType = "SHIPPED"
+	MilestoneTypeDelivered        MilestoneType = "DELIVERED"
+	MilestoneTypeDeliveryFailed   MilestoneType = "DELIVERY_FAILED"
+	MilestoneTypeReturnRequested  MilestoneType = "RETURN_REQUESTED"
+	MilestoneTypeReturnApproved   MilestoneType = "RETURN_APPROVED"
+	MilestoneTypeReturnRejected   MilestoneType = "RETURN_REJECTED"
+	MilestoneTypeReturnReceived   MilestoneType = "RETURN_RECEIVED"
+	MilestoneTypeExpired          MilestoneType = "EXPIRED"
+)
+
+var AllMilestoneType = []MilestoneType{
+	MilestoneTypePlaced,
+	MilestoneTypeArticleValidated,
+	MilestoneTypeValidated,
+	MilestoneTypeInvalid,
+	MilestoneTypeFirstPayment,
+	MilestoneTypeFullyPaid,
+	MilestoneTypeRefunded,
+	MilestoneTypeChargeback,
+	MilestoneTypeCanceled,
+	MilestoneTypePacking,
+	MilestoneTypeShipped,
+	MilestoneTypeDelivered,
+	MilestoneTypeDeliveryFailed,
+	MilestoneTypeReturnRequested,
+	MilestoneTypeReturnApproved,
+	MilestoneTypeReturnRejected,
+	MilestoneTypeReturnReceived,
+	MilestoneTypeExpired,
+}
+
+func (e MilestoneType) IsValid() bool {
+	switch e {
+	case MilestoneTypePlaced, MilestoneTypeArticleValidated, MilestoneTypeValidated, MilestoneTypeInvalid, MilestoneTypeFirstPayment, MilestoneTypeFullyPaid, MilestoneTypeRefunded, MilestoneTypeChargeback, MilestoneTypeCanceled, MilestoneTypePacking, MilestoneTypeShipped, MilestoneTypeDelivered, MilestoneTypeDeliveryFailed, MilestoneTypeReturnRequested, MilestoneTypeReturnApproved, MilestoneTypeReturnRejected, MilestoneTypeReturnReceived, MilestoneTypeExpired:
+		return true
+	}
+	return false
+}
+
+func (e MilestoneType) String() string {
+	return string(e)
+}
+
+func (e *MilestoneType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MilestoneType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MilestoneType", str)
+	}
+	return nil
+}
+
+func (e MilestoneType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type OrderStatus string
 
 const (
@@ -75,6 +283,10 @@ const (
 	OrderStatusInvalid        OrderStatus = "INVALID"
 	OrderStatusValidated      OrderStatus = "VALIDATED"
 	OrderStatusPaymentDefined OrderStatus = "PAYMENT_DEFINED"
+	OrderStatusPacking        OrderStatus = "PACKING"
+	OrderStatusShipped        OrderStatus = "SHIPPED"
+	OrderStatusDelivered      OrderStatus = "DELIVERED"
+	OrderStatusDeliveryFailed OrderStatus = "DELIVERY_FAILED"
 )
 
 var AllOrderStatus = []OrderStatus{
@@ -82,11 +294,15 @@ var AllOrderStatus = []OrderStatus{
 	OrderStatusInvalid,
 	OrderStatusValidated,
 	OrderStatusPaymentDefined,
+	OrderStatusPacking,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+	OrderStatusDeliveryFailed,
 }
 
 func (e OrderStatus) IsValid() bool {
 	switch e {
-	case OrderStatusPlaced, OrderStatusInvalid, OrderStatusValidated, OrderStatusPaymentDefined:
+	case OrderStatusPlaced, OrderStatusInvalid, OrderStatusValidated, OrderStatusPaymentDefined, OrderStatusPacking, OrderStatusShipped, OrderStatusDelivered, OrderStatusDeliveryFailed:
 		return true
 	}
 	return false
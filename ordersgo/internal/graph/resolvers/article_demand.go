@@ -0,0 +1,44 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/graph/model"
+	"github.com/nmarsollier/ordersgo/internal/graph/tools"
+)
+
+// FindArticleById el artículo es del catálogo, acá solo se resuelven los campos que agrega este servicio
+func FindArticleById(ctx context.Context, id string) (*model.Article, error) {
+	return &model.Article{
+		ID: id,
+	}, nil
+}
+
+// GetArticleDemand un artículo que nunca se pidió no tiene demanda
+func GetArticleDemand(ctx context.Context, obj *model.Article) (*model.ArticleDemand, error) {
+	if _, err := tools.ValidateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	env := tools.GqlDi(ctx)
+	demand, err := env.ArticleService().FindByArticleId(obj.ID)
+	if err == errs.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ArticleDemand{
+		ArticleID:        demand.ArticleId,
+		Orders:           demand.Orders,
+		QuantityOrdered:  demand.QuantityOrdered,
+		QuantityPaid:     demand.QuantityPaid,
+		QuantityCanceled: demand.QuantityCanceled,
+		Validations:      demand.Validations,
+		Invalid:          demand.Invalid,
+		InvalidRate:      float64(demand.InvalidRate),
+		Revenue:          float64(demand.Revenue),
+	}, nil
+}
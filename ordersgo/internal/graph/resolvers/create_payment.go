@@ -3,23 +3,31 @@ package resolvers
 import (
 	"context"
 
+	"github.com/nmarsollier/commongo/errs"
 	"github.com/nmarsollier/ordersgo/internal/events"
 	"github.com/nmarsollier/ordersgo/internal/graph/model"
 	"github.com/nmarsollier/ordersgo/internal/graph/tools"
+	"github.com/nmarsollier/ordersgo/internal/services"
 )
 
 func CreatePayment(ctx context.Context, orderID string, payment *model.PaymentEventInput) (bool, error) {
-	_, err := tools.ValidateLoggedIn(ctx)
+	user, err := tools.ValidateLoggedIn(ctx)
 	if err != nil {
 		return false, err
 	}
 
+	if payment == nil {
+		return false, errs.NewValidation().Add("payment", "required")
+	}
+
 	env := tools.GqlDi(ctx)
 
-	_, err = env.Service().ProcessSavePayment(&events.PaymentEvent{
-		OrderId: orderID,
-		Method:  string(payment.Method),
-		Amount:  float32(payment.Amount),
+	_, err = env.Service().ProcessManualPayment(&services.ManualPaymentData{
+		OrderId:        orderID,
+		UserId:         user.ID,
+		Method:         events.PaymentMethod(payment.Method),
+		Amount:         float32(payment.Amount),
+		IdempotencyKey: tools.IdempotencyKey(ctx),
 	})
 	if err != nil {
 		return false, err
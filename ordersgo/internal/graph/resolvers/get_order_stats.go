@@ -0,0 +1,69 @@
+package resolvers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/graph/model"
+	"github.com/nmarsollier/ordersgo/internal/graph/tools"
+	"github.com/nmarsollier/ordersgo/internal/projections/customer"
+)
+
+// GetOrderStats sin userId devuelve las del usuario logueado, otro usuario requiere admin
+func GetOrderStats(ctx context.Context, userId *string) (*model.CustomerOrderStats, error) {
+	user, err := tools.ValidateLoggedIn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := user.ID
+	if userId != nil && *userId != user.ID {
+		if !user.HasPermission("admin") {
+			return nil, errs.Unauthorized
+		}
+		target = *userId
+	}
+
+	env := tools.GqlDi(ctx)
+	stats, err := env.CustomerService().FindByUserId(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapCustomerStatsToModel(stats), nil
+}
+
+func mapCustomerStatsToModel(stats *customer.CustomerStats) *model.CustomerOrderStats {
+	byStatus := []*model.StatusCount{}
+	for status, count := range stats.ByStatus {
+		byStatus = append(byStatus, &model.StatusCount{
+			Status: string(status),
+			Count:  count,
+		})
+	}
+	sort.Slice(byStatus, func(i, j int) bool {
+		return byStatus[i].Status < byStatus[j].Status
+	})
+
+	favourites := make([]*model.FavouriteArticle, len(stats.FavouriteArticles))
+	for i, a := range stats.FavouriteArticles {
+		favourites[i] = &model.FavouriteArticle{
+			ArticleID: a.ArticleId,
+			Quantity:  a.Quantity,
+			Orders:    a.Orders,
+		}
+	}
+
+	return &model.CustomerOrderStats{
+		UserID:            stats.UserId,
+		Orders:            stats.Orders,
+		ByStatus:          byStatus,
+		TotalSpent:        float64(stats.TotalSpent),
+		TotalRefunded:     float64(stats.TotalRefunded),
+		AverageOrderValue: float64(stats.AverageOrderValue),
+		FirstOrder:        stats.FirstOrder,
+		LastOrder:         stats.LastOrder,
+		FavouriteArticles: favourites,
+	}
+}
@@ -0,0 +1,65 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/graph/model"
+	"github.com/nmarsollier/ordersgo/internal/graph/tools"
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+)
+
+func GetOrderStatus(ctx context.Context, id string) (*model.OrderTimeline, error) {
+	user, err := tools.ValidateLoggedIn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	env := tools.GqlDi(ctx)
+	timeline, err := env.StatusService().FindByOrderId(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Solo el dueño de la orden o un admin
+	if timeline.UserId != user.ID && !user.HasPermission("admin") {
+		return nil, errs.Unauthorized
+	}
+
+	return mapTimelineToModel(timeline), nil
+}
+
+func mapTimelineToModel(timeline *status.OrderStatus) *model.OrderTimeline {
+	milestones := make([]*model.OrderMilestone, len(timeline.Milestones))
+	for i, m := range timeline.Milestones {
+		milestones[i] = &model.OrderMilestone{
+			Type:           model.MilestoneType(strings.ToUpper(string(m.Type))),
+			Time:           m.Time,
+			ArticleID:      optional(m.ArticleId),
+			Valid:          m.Valid,
+			PaymentID:      optional(m.PaymentId),
+			Reason:         optional(m.Reason),
+			Carrier:        optional(m.Carrier),
+			TrackingNumber: optional(m.TrackingNumber),
+			ReturnID:       optional(m.ReturnId),
+		}
+		if m.Amount != 0 {
+			amount := float64(m.Amount)
+			milestones[i].Amount = &amount
+		}
+	}
+
+	return &model.OrderTimeline{
+		OrderID:    timeline.OrderId,
+		UserID:     timeline.UserId,
+		Milestones: milestones,
+	}
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
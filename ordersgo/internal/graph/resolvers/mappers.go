@@ -1,19 +1,23 @@
 package resolvers
 
 import (
+	"github.com/nmarsollier/ordersgo/internal/events"
 	"github.com/nmarsollier/ordersgo/internal/graph/model"
 	"github.com/nmarsollier/ordersgo/internal/projections/order"
 )
 
 func mapOrderToModel(order *order.Order) *model.Order {
 	return &model.Order{
-		ID:       order.ID.Hex(),
-		OrderID:  order.OrderId,
-		Status:   model.OrderStatus(order.Status),
-		UserID:   order.UserId,
-		CartID:   order.CartId,
-		Articles: mapArticlesToModel(order.Articles),
-		Payments: mapPaymentsToModel(order.Payments),
+		ID:          order.ID.Hex(),
+		OrderID:     order.OrderId,
+		Status:      model.OrderStatus(order.Status),
+		UserID:      order.UserId,
+		CartID:      order.CartId,
+		Articles:    mapArticlesToModel(order.Articles),
+		Payments:    mapPaymentsToModel(order.Payments),
+		Delivery:    mapDeliveryToModel(order.Delivery),
+		Fulfillment: mapFulfillmentToModel(order.Fulfillment),
+		Returns:     mapReturnsToModel(order.Returns),
 	}
 }
 
@@ -43,7 +47,107 @@ func mapPaymentsToModel(payments []*order.PaymentEvent) []*model.PaymentEvent {
 		result[i] = &model.PaymentEvent{
 			Method: model.PaymentMethod(p.Method),
 			Amount: float64(p.Amount),
+			Status: optional(string(p.Status)),
 		}
 	}
 	return result
 }
+
+func mapDeliveryToModel(delivery *events.Delivery) *model.Delivery {
+	if delivery == nil {
+		return nil
+	}
+
+	return &model.Delivery{
+		ShippingAddress: mapAddressToModel(delivery.ShippingAddress),
+		BillingAddress:  mapAddressToModel(delivery.BillingAddress),
+		ContactPhone:    optional(delivery.ContactPhone),
+		Instructions:    optional(delivery.Instructions),
+	}
+}
+
+func mapFulfillmentToModel(fulfillment *events.FulfillmentState) *model.Fulfillment {
+	if fulfillment == nil {
+		return nil
+	}
+
+	return &model.Fulfillment{
+		Status:         string(fulfillment.Status),
+		Carrier:        optional(fulfillment.Carrier),
+		TrackingNumber: optional(fulfillment.TrackingNumber),
+	}
+}
+
+func mapReturnsToModel(returns []*events.ReturnState) []*model.Return {
+	result := make([]*model.Return, len(returns))
+	for i, r := range returns {
+		lines := make([]*model.ReturnLine, len(r.Lines))
+		for j, line := range r.Lines {
+			lines[j] = &model.ReturnLine{
+				ArticleID: line.ArticleId,
+				Quantity:  line.Quantity,
+				Amount:    float64(line.Amount),
+				Status:    string(line.Status),
+			}
+		}
+
+		result[i] = &model.Return{
+			ReturnID: r.ReturnId,
+			Status:   string(r.Status),
+			Reason:   r.Reason,
+			Amount:   float64(r.Amount),
+			Lines:    lines,
+		}
+	}
+	return result
+}
+
+func mapAddressToModel(address *events.Address) *model.Address {
+	if address == nil {
+		return nil
+	}
+
+	return &model.Address{
+		Line1:      address.Line1,
+		Line2:      optional(address.Line2),
+		City:       address.City,
+		State:      optional(address.State),
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
+
+func mapDeliveryInput(delivery *model.DeliveryInput) *events.Delivery {
+	if delivery == nil {
+		return nil
+	}
+
+	return &events.Delivery{
+		ShippingAddress: mapAddressInput(delivery.ShippingAddress),
+		BillingAddress:  mapAddressInput(delivery.BillingAddress),
+		ContactPhone:    value(delivery.ContactPhone),
+		Instructions:    value(delivery.Instructions),
+	}
+}
+
+func mapAddressInput(address *model.AddressInput) *events.Address {
+	if address == nil {
+		return nil
+	}
+
+	return &events.Address{
+		Line1:      address.Line1,
+		Line2:      value(address.Line2),
+		City:       address.City,
+		State:      value(address.State),
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
+
+func value(optional *string) string {
+	if optional == nil {
+		return ""
+	}
+	return *optional
+}
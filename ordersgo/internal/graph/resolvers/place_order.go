@@ -0,0 +1,42 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/graph/model"
+	"github.com/nmarsollier/ordersgo/internal/graph/tools"
+)
+
+func PlaceOrder(ctx context.Context, order model.PlaceOrderInput) (string, error) {
+	user, err := tools.ValidateLoggedIn(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if order.UserID != user.ID && !user.HasPermission("admin") {
+		return "", errs.Unauthorized
+	}
+
+	articles := make([]events.PlacePrderArticleData, len(order.Articles))
+	for i, article := range order.Articles {
+		articles[i] = events.PlacePrderArticleData{
+			Id:       article.ID,
+			Quantity: article.Quantity,
+		}
+	}
+
+	env := tools.GqlDi(ctx)
+	event, err := env.Service().PocessPlaceOrder(&events.PlacedOrderData{
+		CartId:   order.CartID,
+		UserId:   order.UserID,
+		Articles: articles,
+		Delivery: mapDeliveryInput(order.Delivery),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return event.OrderId, nil
+}
@@ -0,0 +1,38 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/graph/model"
+	"github.com/nmarsollier/ordersgo/internal/graph/tools"
+	"github.com/nmarsollier/ordersgo/internal/services"
+)
+
+func RequestReturn(ctx context.Context, orderID string, returnArg model.ReturnInput) (string, error) {
+	user, err := tools.ValidateLoggedIn(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]*events.ReturnLine, len(returnArg.Lines))
+	for i, line := range returnArg.Lines {
+		lines[i] = &events.ReturnLine{
+			ArticleId: line.ArticleID,
+			Quantity:  line.Quantity,
+		}
+	}
+
+	env := tools.GqlDi(ctx)
+	event, err := env.Service().ProcessRequestReturn(&services.ReturnRequestData{
+		OrderId: orderID,
+		UserId:  user.ID,
+		Reason:  returnArg.Reason,
+		Lines:   lines,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return event.Return.ReturnId, nil
+}
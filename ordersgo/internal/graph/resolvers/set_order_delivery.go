@@ -0,0 +1,23 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/nmarsollier/ordersgo/internal/graph/model"
+	"github.com/nmarsollier/ordersgo/internal/graph/tools"
+)
+
+func SetOrderDelivery(ctx context.Context, orderID string, delivery model.DeliveryInput) (bool, error) {
+	user, err := tools.ValidateLoggedIn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	env := tools.GqlDi(ctx)
+	_, err = env.Service().ProcessSetDelivery(orderID, user.ID, mapDeliveryInput(&delivery))
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -11,6 +11,11 @@ import (
 	"github.com/nmarsollier/ordersgo/internal/graph/resolvers"
 )
 
+// FindArticleByID is the resolver for the findArticleByID field.
+func (r *entityResolver) FindArticleByID(ctx context.Context, id string) (*model.Article, error) {
+	return resolvers.FindArticleById(ctx, id)
+}
+
 // FindOrderByID is the resolver for the findOrderByID field.
 func (r *entityResolver) FindOrderByID(ctx context.Context, id string) (*model.Order, error) {
 	return resolvers.FindByOrderId(ctx, id)
@@ -11,11 +11,31 @@ import (
 	"github.com/nmarsollier/ordersgo/internal/graph/resolvers"
 )
 
+// Demand is the resolver for the demand field.
+func (r *articleResolver) Demand(ctx context.Context, obj *model.Article) (*model.ArticleDemand, error) {
+	return resolvers.GetArticleDemand(ctx, obj)
+}
+
 // CreatePayment is the resolver for the createPayment field.
 func (r *mutationResolver) CreatePayment(ctx context.Context, orderID string, payment *model.PaymentEventInput) (bool, error) {
 	return resolvers.CreatePayment(ctx, orderID, payment)
 }
 
+// PlaceOrder is the resolver for the placeOrder field.
+func (r *mutationResolver) PlaceOrder(ctx context.Context, order model.PlaceOrderInput) (string, error) {
+	return resolvers.PlaceOrder(ctx, order)
+}
+
+// SetOrderDelivery is the resolver for the setOrderDelivery field.
+func (r *mutationResolver) SetOrderDelivery(ctx context.Context, orderID string, delivery model.DeliveryInput) (bool, error) {
+	return resolvers.SetOrderDelivery(ctx, orderID, delivery)
+}
+
+// RequestReturn is the resolver for the requestReturn field.
+func (r *mutationResolver) RequestReturn(ctx context.Context, orderID string, returnArg model.ReturnInput) (string, error) {
+	return resolvers.RequestReturn(ctx, orderID, returnArg)
+}
+
 // GetOrder is the resolver for the getOrder field.
 func (r *queryResolver) GetOrder(ctx context.Context, id string) (*model.Order, error) {
 	return resolvers.GetOrder(ctx, id)
@@ -26,11 +46,25 @@ func (r *queryResolver) GetOrders(ctx context.Context) ([]*model.OrderSummary, e
 	return resolvers.GetOrders(ctx)
 }
 
+// GetOrderStatus is the resolver for the getOrderStatus field.
+func (r *queryResolver) GetOrderStatus(ctx context.Context, id string) (*model.OrderTimeline, error) {
+	return resolvers.GetOrderStatus(ctx, id)
+}
+
+// GetOrderStats is the resolver for the getOrderStats field.
+func (r *queryResolver) GetOrderStats(ctx context.Context, userID *string) (*model.CustomerOrderStats, error) {
+	return resolvers.GetOrderStats(ctx, userID)
+}
+
+// Article returns model.ArticleResolver implementation.
+func (r *Resolver) Article() model.ArticleResolver { return &articleResolver{r} }
+
 // Mutation returns model.MutationResolver implementation.
 func (r *Resolver) Mutation() model.MutationResolver { return &mutationResolver{r} }
 
 // Query returns model.QueryResolver implementation.
 func (r *Resolver) Query() model.QueryResolver { return &queryResolver{r} }
 
+type articleResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
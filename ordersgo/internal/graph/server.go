@@ -1,25 +1,63 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
 	"github.com/nmarsollier/ordersgo/internal/env"
 	"github.com/nmarsollier/ordersgo/internal/graph/model"
 	"github.com/nmarsollier/ordersgo/internal/graph/schema"
+	"github.com/nmarsollier/ordersgo/internal/graph/tools"
+	"github.com/nmarsollier/ordersgo/internal/health"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
 )
 
 func Start() {
 	logger := log.Get(env.Get().FluentURL, env.Get().ServerName)
 	port := env.Get().GqlPort
 	srv := handler.NewDefaultServer(model.NewExecutableSchema(model.Config{Resolvers: &schema.Resolver{}}))
+	srv.Use(tools.TracingExtension{})
+	srv.Use(tools.MetricsExtension{})
+	srv.Use(tools.IdempotencyExtension{})
 
-	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", srv)
+	mux := http.NewServeMux()
+	mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	mux.Handle("/query", srv)
+	mux.HandleFunc("/health/live", healthLive)
+	mux.HandleFunc("/health/ready", healthReady)
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+	lifecycle.OnShutdown(lifecycle.Servers, "graphql", server.Shutdown)
 
 	logger.Info("GraphQL playground on port : ", port)
-	logger.Error(http.ListenAndServe(fmt.Sprintf(":%d", env.Get().GqlPort), nil))
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Error(err)
+		lifecycle.Stop()
+	}
+}
+
+func healthLive(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, health.Live())
+}
+
+func healthReady(w http.ResponseWriter, r *http.Request) {
+	logger := log.Get(env.Get().FluentURL, env.Get().ServerName).
+		WithField(log.LOG_FIELD_CONTROLLER, "GraphQL").
+		WithField(log.LOG_FIELD_HTTP_PATH, r.URL.Path)
+
+	writeHealth(w, health.Ready(di.NewInjector(logger)))
+}
+
+func writeHealth(w http.ResponseWriter, report *health.Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(report.HttpStatus())
+	json.NewEncoder(w).Encode(report)
 }
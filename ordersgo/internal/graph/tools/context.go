@@ -14,7 +14,7 @@ func GqlDi(c context.Context) di.Injector {
 		return context_deps.(di.Injector)
 	}
 
-	deps := di.NewInjector(newLogger(c))
+	deps := di.NewInjectorWithContext(c, newLogger(c))
 	operationContext.Variables["di"] = deps
 	return deps
 }
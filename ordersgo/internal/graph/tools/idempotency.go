@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/nmarsollier/ordersgo/internal/idempotency"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// IdempotencyKey valor del header Idempotency-Key, vacío si no se envió
+func IdempotencyKey(ctx context.Context) string {
+	return graphql.GetOperationContext(ctx).Headers.Get(idempotency.Header)
+}
+
+// IdempotencyExtension repite la respuesta original de una mutation cuando el cliente reintenta
+// con el mismo Idempotency-Key. Sólo se guardan las respuestas sin errores.
+type IdempotencyExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = IdempotencyExtension{}
+
+func (IdempotencyExtension) ExtensionName() string {
+	return "Idempotency"
+}
+
+func (IdempotencyExtension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (IdempotencyExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	operationContext := graphql.GetOperationContext(ctx)
+	key := IdempotencyKey(ctx)
+	if key == "" || operationContext.Operation == nil || operationContext.Operation.Operation != ast.Mutation {
+		return next(ctx)
+	}
+	if err := idempotency.ValidateKey(key); err != nil {
+		return graphql.ErrorResponse(ctx, "%s", err.Error())
+	}
+
+	// GqlDi guarda el injector en las variables, no es parte del pedido
+	variables := map[string]interface{}{}
+	for name, value := range operationContext.Variables {
+		if name != "di" {
+			variables[name] = value
+		}
+	}
+	requestVariables, err := json.Marshal(variables)
+	if err != nil {
+		return graphql.ErrorResponse(ctx, "%s", err.Error())
+	}
+	hash := idempotency.RequestHash(operationContext.OperationName, operationContext.RawQuery, string(requestVariables))
+
+	// Sin usuario la mutation responde Unauthorized, no hay nada que guardar
+	user, err := ValidateLoggedIn(ctx)
+	if err != nil {
+		return next(ctx)
+	}
+	key = idempotency.ScopedKey(user.ID, key)
+
+	deps := GqlDi(ctx)
+	service := deps.IdempotencyService()
+	record, err := service.Begin(key, hash)
+	if err != nil {
+		return graphql.ErrorResponse(ctx, "%s", err.Error())
+	}
+	if record != nil {
+		response := &graphql.Response{}
+		if err := json.Unmarshal([]byte(record.Body), response); err != nil {
+			return graphql.ErrorResponse(ctx, "%s", err.Error())
+		}
+		return response
+	}
+
+	response := next(ctx)
+	if response == nil || len(response.Errors) > 0 {
+		service.Release(key)
+		return response
+	}
+
+	body, err := json.Marshal(response)
+	if err == nil {
+		err = service.Complete(key, 200, "application/json", body)
+	}
+	if err != nil {
+		deps.Logger().Error(err)
+		service.Release(key)
+	}
+
+	return response
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/ordersgo/internal/env"
+	"github.com/nmarsollier/ordersgo/internal/telemetry"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -19,9 +20,14 @@ func newLogger(ctx context.Context) log.LogRusEntry {
 		WithField(log.LOG_FIELD_HTTP_PATH, operationContext.OperationName)
 }
 
+// getCorrelationId mismo header que rest, si no viene se usa el trace id de la operación
 func getCorrelationId(ctx context.Context) string {
 	operationContext := graphql.GetOperationContext(ctx)
-	value := operationContext.Headers.Get("Authorization")
+	value := operationContext.Headers.Get(log.LOG_FIELD_CORRELATION_ID)
+
+	if len(value) == 0 {
+		value = telemetry.TraceId(ctx)
+	}
 
 	if len(value) == 0 {
 		value = uuid.NewV4().String()
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// MetricsExtension registra cantidad y latencia de operaciones GraphQL
+type MetricsExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = MetricsExtension{}
+
+func (MetricsExtension) ExtensionName() string {
+	return "Metrics"
+}
+
+func (MetricsExtension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (MetricsExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	response := next(ctx)
+
+	operation := operationName(ctx)
+	status := "ok"
+	if response == nil || len(response.Errors) > 0 {
+		status = "error"
+	}
+
+	m := GqlDi(ctx).Metrics()
+	m.GraphqlOperations.WithLabelValues(operation, status).Inc()
+	m.GraphqlDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+
+	return response
+}
+
+func operationName(ctx context.Context) string {
+	operationContext := graphql.GetOperationContext(ctx)
+	if operationContext.OperationName != "" {
+		return operationContext.OperationName
+	}
+	if operationContext.Operation != nil {
+		return string(operationContext.Operation.Operation)
+	}
+	return "unknown"
+}
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/nmarsollier/ordersgo/internal/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingExtension crea un span por operación GraphQL, continuando la traza del cliente
+type TracingExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = TracingExtension{}
+
+func (TracingExtension) ExtensionName() string {
+	return "Tracing"
+}
+
+func (TracingExtension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (TracingExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	operationContext := graphql.GetOperationContext(ctx)
+	if operationContext.Headers != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(http.Header(operationContext.Headers)))
+	}
+
+	operation := operationName(ctx)
+	ctx, span := telemetry.Tracer().Start(ctx, "graphql "+operation,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("graphql.operation.name", operation),
+		),
+	)
+	defer span.End()
+
+	if operationContext.Operation != nil {
+		span.SetAttributes(attribute.String("graphql.operation.type", string(operationContext.Operation.Operation)))
+	}
+
+	response := next(ctx)
+	if response != nil && len(response.Errors) > 0 {
+		span.SetStatus(codes.Error, response.Errors.Error())
+	}
+
+	return response
+}
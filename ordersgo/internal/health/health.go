@@ -0,0 +1,160 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/env"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
+	"github.com/nmarsollier/ordersgo/internal/projections"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type Status string
+
+const (
+	Up       Status = "up"
+	Degraded Status = "degraded"
+	Down     Status = "down"
+)
+
+// Las proyecciones con actualizaciones pendientes más viejas que esto se reportan degradadas
+const maxProjectionLag = 30 * time.Second
+
+const checkTimeout = 3 * time.Second
+
+// Report resultado de un chequeo de salud
+type Report struct {
+	Status Status           `json:"status"`
+	Checks map[string]Check `json:"checks,omitempty"`
+}
+
+// Check resultado de una dependencia
+type Check struct {
+	Status  Status      `json:"status"`
+	Error   string      `json:"error,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// HttpStatus código http a responder según el estado
+func (r *Report) HttpStatus() int {
+	if r.Status == Down {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// Live indica si el proceso está vivo, no chequea dependencias
+func Live() *Report {
+	if lifecycle.IsStopping() {
+		return &Report{Status: Down}
+	}
+	return &Report{Status: Up}
+}
+
+// Ready chequea las dependencias necesarias para atender requests
+func Ready(deps di.Injector) *Report {
+	report := &Report{
+		Status: Up,
+		Checks: map[string]Check{
+			"rabbit":      checkRabbit(),
+			"auth":        checkAuth(deps),
+			"projections": checkProjections(),
+		},
+	}
+
+	// El backend en memoria no tiene dependencias externas
+	switch env.Get().StorageBackend {
+	case env.MongoStorage:
+		report.Checks["mongo"] = checkMongo(deps)
+	case env.PostgresStorage:
+		report.Checks["postgres"] = checkPostgres(deps)
+	}
+
+	if lifecycle.IsStopping() {
+		report.Status = Down
+	}
+
+	for _, check := range report.Checks {
+		if check.Status == Down {
+			report.Status = Down
+		} else if check.Status == Degraded && report.Status == Up {
+			report.Status = Degraded
+		}
+	}
+
+	return report
+}
+
+func checkMongo(deps di.Injector) Check {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	if err := deps.Database().Client().Ping(ctx, readpref.Primary()); err != nil {
+		return Check{Status: Down, Error: err.Error()}
+	}
+	return Check{Status: Up}
+}
+
+func checkPostgres(deps di.Injector) Check {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	if err := deps.Postgres().Ping(ctx); err != nil {
+		return Check{Status: Down, Error: err.Error()}
+	}
+	return Check{Status: Up}
+}
+
+func checkRabbit() Check {
+	consumers := broker.Consumers()
+
+	result := Check{Status: Up, Details: consumers}
+	if len(consumers) == 0 {
+		result.Status = Down
+		result.Error = "consumers not started"
+	}
+
+	for _, consumer := range consumers {
+		if consumer.State != broker.Connected {
+			result.Status = Down
+		}
+	}
+	return result
+}
+
+// checkAuth valida que el servicio de auth responda, cualquier respuesta http sirve
+func checkAuth(deps di.Injector) Check {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, env.Get().SecurityServerURL, nil)
+	if err != nil {
+		return Check{Status: Down, Error: err.Error()}
+	}
+
+	resp, err := deps.HttpClient().Do(req)
+	if err != nil {
+		return Check{Status: Down, Error: err.Error()}
+	}
+	resp.Body.Close()
+
+	return Check{Status: Up}
+}
+
+func checkProjections() Check {
+	lag := projections.Lag()
+
+	result := Check{Status: Up, Details: lag}
+	if lag.OldestMillis > maxProjectionLag.Milliseconds() {
+		result.Status = Degraded
+	}
+	if lag.LastError != "" {
+		result.Status = Degraded
+		result.Error = lag.LastError
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package idempotency
+
+import (
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+)
+
+// NewMemoryIdempotencyRepository claves en memoria, duran lo que dura el proceso
+func NewMemoryIdempotencyRepository(log log.LogRusEntry, table *memdb.Table[Record]) IdempotencyRepository {
+	return &memoryIdempotencyRepository{
+		log:   log,
+		table: table,
+	}
+}
+
+type memoryIdempotencyRepository struct {
+	log   log.LogRusEntry
+	table *memdb.Table[Record]
+}
+
+func (r *memoryIdempotencyRepository) Insert(record *Record) (*Record, error) {
+	if err := record.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	err := r.table.Insert(record, func(current *Record) bool {
+		return current.Key == record.Key
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *memoryIdempotencyRepository) FindByKey(key string) (*Record, error) {
+	return r.table.FindOne(func(record *Record) bool {
+		return record.Key == key
+	})
+}
+
+func (r *memoryIdempotencyRepository) Update(record *Record) error {
+	current, err := r.FindByKey(record.Key)
+	if err != nil {
+		return err
+	}
+
+	current.Status = record.Status
+	current.ContentType = record.ContentType
+	current.Body = record.Body
+	return r.table.Upsert(current, func(stored *Record) bool {
+		return stored.Key == record.Key
+	})
+}
+
+func (r *memoryIdempotencyRepository) TakeOver(current *Record, record *Record) error {
+	if err := record.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return err
+	}
+
+	return r.table.Replace(record, func(stored *Record) bool {
+		return stored.Key == current.Key && stored.Created.Equal(current.Created) && !stored.IsCompleted()
+	})
+}
+
+func (r *memoryIdempotencyRepository) Delete(key string) error {
+	_, err := r.table.Delete(func(record *Record) bool {
+		return record.Key == key
+	})
+	return err
+}
@@ -0,0 +1,14 @@
+package idempotency_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/idempotency"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestMemoryIdempotency(t *testing.T) {
+	repotest.IdempotencyRepository(t, idempotency.NewMemoryIdempotencyRepository(log.Get("", "test"), memdb.NewTable[idempotency.Record]()))
+}
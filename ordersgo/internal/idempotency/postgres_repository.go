@@ -0,0 +1,130 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewPostgresIdempotencyRepository claves sobre la tabla idempotency_keys.
+// Postgres no tiene indices TTL, las claves más viejas que ttl se depuran al insertar.
+func NewPostgresIdempotencyRepository(log log.LogRusEntry, db pgdb.DB, ttl time.Duration) IdempotencyRepository {
+	return &postgresIdempotencyRepository{
+		log: log,
+		db:  db,
+		ttl: ttl,
+	}
+}
+
+type postgresIdempotencyRepository struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+	ttl time.Duration
+}
+
+func (r *postgresIdempotencyRepository) Insert(record *Record) (*Record, error) {
+	if err := record.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	id := record.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	tag, err := r.db.Exec(context.Background(), `
+		WITH expired AS (DELETE FROM idempotency_keys WHERE created < $8)
+		INSERT INTO idempotency_keys (key, id, request_hash, status, content_type, body, created)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (key) DO NOTHING`,
+		record.Key, id.Hex(), record.RequestHash, record.Status, record.ContentType, record.Body,
+		record.Created, time.Now().Add(-r.ttl),
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, errs.AlreadyExist
+	}
+
+	return record, nil
+}
+
+func (r *postgresIdempotencyRepository) FindByKey(key string) (*Record, error) {
+	var id string
+	record := &Record{}
+
+	err := r.db.QueryRow(context.Background(), `
+		SELECT id, key, request_hash, status, content_type, body, created
+		FROM idempotency_keys WHERE key = $1`, key,
+	).Scan(&id, &record.Key, &record.RequestHash, &record.Status, &record.ContentType, &record.Body, &record.Created)
+	if err == pgx.ErrNoRows {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	if record.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	record.Created = record.Created.UTC()
+	return record, nil
+}
+
+func (r *postgresIdempotencyRepository) Update(record *Record) error {
+	tag, err := r.db.Exec(context.Background(), `
+		UPDATE idempotency_keys SET status = $2, content_type = $3, body = $4 WHERE key = $1`,
+		record.Key, record.Status, record.ContentType, record.Body,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound
+	}
+
+	return nil
+}
+
+func (r *postgresIdempotencyRepository) TakeOver(current *Record, record *Record) error {
+	if err := record.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return err
+	}
+
+	tag, err := r.db.Exec(context.Background(), `
+		UPDATE idempotency_keys SET request_hash = $3, status = $4, content_type = $5, body = $6, created = $7
+		WHERE key = $1 AND created = $2 AND status = 0`,
+		current.Key, current.Created, record.RequestHash, record.Status, record.ContentType, record.Body, record.Created,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFound
+	}
+
+	return nil
+}
+
+func (r *postgresIdempotencyRepository) Delete(key string) error {
+	if _, err := r.db.Exec(context.Background(), "DELETE FROM idempotency_keys WHERE key = $1", key); err != nil {
+		r.log.Error(err)
+		return err
+	}
+
+	return nil
+}
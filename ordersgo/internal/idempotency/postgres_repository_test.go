@@ -0,0 +1,14 @@
+package idempotency_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/idempotency"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestPostgresIdempotency(t *testing.T) {
+	repotest.IdempotencyRepository(t, idempotency.NewPostgresIdempotencyRepository(log.Get("", "test"), repotest.Postgres(t), 24*time.Hour))
+}
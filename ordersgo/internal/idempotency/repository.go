@@ -0,0 +1,117 @@
+package idempotency
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/db"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type IdempotencyRepository interface {
+	Insert(record *Record) (*Record, error)
+	FindByKey(key string) (*Record, error)
+	Update(record *Record) error
+	// TakeOver reemplaza current por record si nadie lo tomó ni lo completó, si no devuelve errs.NotFound
+	TakeOver(current *Record, record *Record) error
+	Delete(key string) error
+}
+
+// NewIdempotencyRepository raw es la misma colección, se usa para las operaciones que db.Collection no soporta
+func NewIdempotencyRepository(log log.LogRusEntry, collection db.Collection, raw *mongo.Collection) IdempotencyRepository {
+	return &idempotencyRepository{
+		log:        log,
+		collection: collection,
+		raw:        raw,
+	}
+}
+
+type idempotencyRepository struct {
+	log        log.LogRusEntry
+	collection db.Collection
+	raw        *mongo.Collection
+}
+
+// Insert registra la clave, si ya existe devuelve errs.AlreadyExist
+func (r *idempotencyRepository) Insert(record *Record) (*Record, error) {
+	if err := record.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	if _, err := r.collection.InsertOne(context.Background(), record); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errs.AlreadyExist
+		}
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// FindByKey busca una clave registrada
+func (r *idempotencyRepository) FindByKey(key string) (*Record, error) {
+	record := &Record{}
+	filter := bson.M{"key": key}
+	if err := r.collection.FindOne(context.Background(), filter, record); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errs.NotFound
+		}
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Update guarda la respuesta, conserva la fecha de creación para el ttl
+func (r *idempotencyRepository) Update(record *Record) error {
+	filter := bson.M{"key": record.Key}
+	update := bson.M{"$set": bson.M{
+		"status":      record.Status,
+		"contentType": record.ContentType,
+		"body":        record.Body,
+	}}
+
+	modified, err := r.collection.UpdateOne(context.Background(), filter, update, nil)
+	if err != nil {
+		r.log.Error(err)
+		return err
+	}
+	if modified == 0 {
+		return errs.NotFound
+	}
+
+	return nil
+}
+
+func (r *idempotencyRepository) TakeOver(current *Record, record *Record) error {
+	if err := record.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return err
+	}
+
+	filter := bson.M{"key": current.Key, "created": current.Created, "status": 0}
+	modified, err := r.collection.ReplaceOne(context.Background(), filter, record)
+	if err != nil {
+		r.log.Error(err)
+		return err
+	}
+	if modified == 0 {
+		return errs.NotFound
+	}
+
+	return nil
+}
+
+func (r *idempotencyRepository) Delete(key string) error {
+	if _, err := r.raw.DeleteOne(context.Background(), bson.M{"key": key}); err != nil {
+		r.log.Error(err)
+		return err
+	}
+
+	return nil
+}
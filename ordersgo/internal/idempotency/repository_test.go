@@ -0,0 +1,16 @@
+package idempotency_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/idempotency"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+// TestMongoIdempotency corre contra MONGO_URL con las colecciones e índices del injector
+func TestMongoIdempotency(t *testing.T) {
+	deps := &di.Deps{CurrLog: log.Get("", "test"), CurrDatabase: repotest.Mongo(t)}
+	repotest.IdempotencyRepository(t, idempotency.NewIdempotencyRepository(deps.Logger(), deps.IdempotencyCollection(), deps.Database().Collection("idempotency_keys")))
+}
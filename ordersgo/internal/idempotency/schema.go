@@ -0,0 +1,71 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/nmarsollier/commongo/errs"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Header header con el que los clientes identifican los reintentos de una operación
+const Header = "Idempotency-Key"
+
+// maxKeyLength largo máximo de la clave enviada por el cliente
+const maxKeyLength = 255
+
+// Record respuesta guardada para una clave de idempotencia.
+// Status en 0 indica que la operación todavía se está procesando.
+type Record struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Key         string             `bson:"key" validate:"required,min=1,max=400"`
+	RequestHash string             `bson:"requestHash" validate:"required,len=64"`
+	Status      int                `bson:"status"`
+	ContentType string             `bson:"contentType"`
+	Body        string             `bson:"body"`
+	Created     time.Time          `bson:"created"`
+}
+
+// ValidateSchema valida la estructura para ser insertada en la db
+func (e *Record) ValidateSchema() error {
+	return validator.New().Struct(e)
+}
+
+// IsCompleted indica si ya se guardó la respuesta
+func (e *Record) IsCompleted() bool {
+	return e.Status != 0
+}
+
+// ValidateKey valida la clave enviada por el cliente
+func ValidateKey(key string) error {
+	if len(key) > maxKeyLength {
+		return errs.NewValidation().Add(Header, "must be at most 255 characters")
+	}
+	return nil
+}
+
+// ScopedKey las claves son por usuario, dos usuarios pueden usar la misma clave
+func ScopedKey(userId string, key string) string {
+	return userId + ":" + key
+}
+
+// RequestHash hash del contenido de la operación, para detectar la misma clave con otro pedido
+func RequestHash(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func newRecord(key string, requestHash string) *Record {
+	return &Record{
+		Key:         key,
+		RequestHash: requestHash,
+		Created:     time.Now(),
+	}
+}
@@ -0,0 +1,97 @@
+package idempotency
+
+import (
+	"time"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+)
+
+// lockTimeout tiempo tras el cual una operación sin respuesta se considera abandonada
+const lockTimeout = time.Minute
+
+// ErrKeyReused la clave ya se usó con un pedido distinto
+var ErrKeyReused = errs.NewRestError(422, "Idempotency-Key already used with a different request")
+
+// ErrInProgress hay otro pedido con la misma clave procesándose
+var ErrInProgress = errs.NewRestError(409, "A request with the same Idempotency-Key is in progress")
+
+type IdempotencyService interface {
+	Begin(key string, requestHash string) (*Record, error)
+	Complete(key string, status int, contentType string, body []byte) error
+	Release(key string) error
+}
+
+func NewIdempotencyService(log log.LogRusEntry, repository IdempotencyRepository) IdempotencyService {
+	return &idempotencyService{
+		log:        log,
+		repository: repository,
+	}
+}
+
+type idempotencyService struct {
+	log        log.LogRusEntry
+	repository IdempotencyRepository
+}
+
+// Begin reserva la clave. Si es nueva devuelve nil y la operación debe ejecutarse, si ya se completó
+// con el mismo pedido devuelve la respuesta guardada para repetirla.
+func (s *idempotencyService) Begin(key string, requestHash string) (*Record, error) {
+	_, err := s.repository.Insert(newRecord(key, requestHash))
+	if err == nil {
+		return nil, nil
+	}
+	if err != errs.AlreadyExist {
+		return nil, err
+	}
+
+	current, err := s.repository.FindByKey(key)
+	if err == errs.NotFound {
+		return nil, ErrInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if current.RequestHash != requestHash {
+		return nil, ErrKeyReused
+	}
+
+	if current.IsCompleted() {
+		s.log.Info("Replaying response for idempotency key: ", key)
+		return current, nil
+	}
+
+	if time.Since(current.Created) < lockTimeout {
+		return nil, ErrInProgress
+	}
+
+	// Solo se toma si sigue abandonada, si otro pedido la tomó o la completó después de leerla no se pisa
+	s.log.Info("Taking over abandoned idempotency key: ", key)
+	if err := s.repository.TakeOver(current, newRecord(key, requestHash)); err != nil {
+		if err == errs.NotFound {
+			return nil, ErrInProgress
+		}
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Complete guarda la respuesta para repetirla en los reintentos
+func (s *idempotencyService) Complete(key string, status int, contentType string, body []byte) error {
+	current, err := s.repository.FindByKey(key)
+	if err != nil {
+		return err
+	}
+
+	current.Status = status
+	current.ContentType = contentType
+	current.Body = string(body)
+	return s.repository.Update(current)
+}
+
+// Release libera la clave cuando la operación falló, para que pueda reintentarse
+func (s *idempotencyService) Release(key string) error {
+	return s.repository.Delete(key)
+}
@@ -0,0 +1,144 @@
+package lifecycle
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/nmarsollier/commongo/log"
+)
+
+// Stage etapas del apagado, se ejecutan en este orden
+type Stage int
+
+const (
+	// Servers dejan de aceptar requests HTTP y GraphQL
+	Servers Stage = iota
+	// Consumers se cancelan los consumers de rabbit, terminando el mensaje en curso
+	Consumers
+	// Drain se espera el trabajo en background (proyecciones, publicaciones)
+	Drain
+	// Connections se cierran las conexiones a Mongo y Rabbit
+	Connections
+)
+
+type hook struct {
+	stage Stage
+	name  string
+	fn    func(ctx context.Context) error
+}
+
+var (
+	mutex    sync.Mutex
+	hooks    []hook
+	tasks    sync.WaitGroup
+	stopping bool
+	stop     = make(chan struct{})
+	stopOnce sync.Once
+)
+
+// OnShutdown registra una función a ejecutar durante el apagado en la etapa indicada.
+// Dentro de una etapa las funciones se ejecutan en el orden en que se registraron.
+func OnShutdown(stage Stage, name string, fn func(ctx context.Context) error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	hooks = append(hooks, hook{stage: stage, name: name, fn: fn})
+}
+
+// Go ejecuta fn en background, el apagado espera a que termine antes de cerrar las conexiones
+func Go(fn func()) {
+	tasks.Add(1)
+	go func() {
+		defer tasks.Done()
+		fn()
+	}()
+}
+
+// Stop inicia el apagado sin esperar una señal, por ejemplo si un servidor no puede iniciar
+func Stop() {
+	stopOnce.Do(func() {
+		close(stop)
+	})
+}
+
+// IsStopping indica si el apagado ya comenzó
+func IsStopping() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return stopping
+}
+
+// Run bloquea hasta recibir SIGTERM/SIGINT (o Stop) y luego apaga la aplicación por etapas.
+// timeout es el tiempo total que se espera para completar el apagado.
+func Run(logger log.LogRusEntry, timeout time.Duration) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(signals)
+
+	select {
+	case sig := <-signals:
+		logger.Info("Signal received, shutting down: ", sig)
+	case <-stop:
+		logger.Info("Stop requested, shutting down")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	shutdown(ctx, logger)
+}
+
+func shutdown(ctx context.Context, logger log.LogRusEntry) {
+	mutex.Lock()
+	stopping = true
+	mutex.Unlock()
+
+	for _, stage := range []Stage{Servers, Consumers, Drain, Connections} {
+		if stage == Drain {
+			if err := waitTasks(ctx); err != nil {
+				logger.Error("Background tasks not drained: ", err)
+			}
+		}
+
+		for _, h := range stageHooks(stage) {
+			if err := h.fn(ctx); err != nil {
+				logger.Error("Shutdown ", h.name, ": ", err)
+				continue
+			}
+			logger.Info("Shutdown ", h.name, " completed")
+		}
+	}
+}
+
+func stageHooks(stage Stage) []hook {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	result := []hook{}
+	for _, h := range hooks {
+		if h.stage == stage {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+func waitTasks(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		tasks.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
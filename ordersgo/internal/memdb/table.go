@@ -0,0 +1,227 @@
+package memdb
+
+import (
+	"sync"
+
+	"github.com/nmarsollier/commongo/errs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Table colección en memoria con la misma semántica que usamos de mongo:
+// los documentos se guardan serializados en bson, por lo que se pierden las
+// referencias y la precisión de las fechas es la misma que en la db, se genera
+// el _id al insertar y las consultas respetan el orden de inserción.
+type Table[T any] struct {
+	mutex     sync.RWMutex
+	documents []bson.M
+}
+
+func NewTable[T any]() *Table[T] {
+	return &Table[T]{}
+}
+
+// Insert agrega el documento, si conflicts encuentra otro documento devuelve errs.AlreadyExist
+func (t *Table[T]) Insert(document *T, conflicts func(*T) bool) error {
+	row, err := toRow(document)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if conflicts != nil {
+		for _, current := range t.documents {
+			value, err := fromRow[T](current)
+			if err != nil {
+				return err
+			}
+			if conflicts(value) {
+				return errs.AlreadyExist
+			}
+		}
+	}
+
+	if _, ok := row["_id"]; !ok {
+		row["_id"] = primitive.NewObjectID()
+	}
+	t.documents = append(t.documents, row)
+	return nil
+}
+
+// Upsert reemplaza el primer documento que cumpla match, conservando su _id, o lo inserta
+func (t *Table[T]) Upsert(document *T, match func(*T) bool) error {
+	row, err := toRow(document)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	replaced, err := t.replace(row, match)
+	if err != nil || replaced {
+		return err
+	}
+
+	if _, ok := row["_id"]; !ok {
+		row["_id"] = primitive.NewObjectID()
+	}
+	t.documents = append(t.documents, row)
+	return nil
+}
+
+// Replace reemplaza el primer documento que cumpla match, conservando su _id, o errs.NotFound
+func (t *Table[T]) Replace(document *T, match func(*T) bool) error {
+	row, err := toRow(document)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	replaced, err := t.replace(row, match)
+	if err != nil {
+		return err
+	}
+	if !replaced {
+		return errs.NotFound
+	}
+	return nil
+}
+
+func (t *Table[T]) replace(row bson.M, match func(*T) bool) (bool, error) {
+	for index, current := range t.documents {
+		value, err := fromRow[T](current)
+		if err != nil {
+			return false, err
+		}
+		if match(value) {
+			row["_id"] = current["_id"]
+			t.documents[index] = row
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FindOne devuelve una copia del primer documento que cumpla match, o errs.NotFound
+func (t *Table[T]) FindOne(match func(*T) bool) (*T, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	for _, current := range t.documents {
+		value, err := fromRow[T](current)
+		if err != nil {
+			return nil, err
+		}
+		if match(value) {
+			return value, nil
+		}
+	}
+
+	return nil, errs.NotFound
+}
+
+// Find devuelve copias de los documentos que cumplan match, en orden de inserción
+func (t *Table[T]) Find(match func(*T) bool) ([]*T, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	result := []*T{}
+	for _, current := range t.documents {
+		value, err := fromRow[T](current)
+		if err != nil {
+			return nil, err
+		}
+		if match == nil || match(value) {
+			result = append(result, value)
+		}
+	}
+
+	return result, nil
+}
+
+// Delete elimina los documentos que cumplan match y devuelve cuántos se eliminaron.
+// Corre las posiciones de Since, no usar en tablas que se siguen por posición.
+func (t *Table[T]) Delete(match func(*T) bool) (int, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	kept := make([]bson.M, 0, len(t.documents))
+	for _, current := range t.documents {
+		value, err := fromRow[T](current)
+		if err != nil {
+			return 0, err
+		}
+		if !match(value) {
+			kept = append(kept, current)
+		}
+	}
+
+	deleted := len(t.documents) - len(kept)
+	t.documents = kept
+	return deleted, nil
+}
+
+// Clear elimina todos los documentos
+func (t *Table[T]) Clear() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.documents = nil
+}
+
+// Len cantidad de documentos en la tabla
+func (t *Table[T]) Len() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return len(t.documents)
+}
+
+// Since devuelve copias de los documentos a partir de la posición from, en orden de inserción.
+// Las posiciones son estables mientras solo se inserte.
+func (t *Table[T]) Since(from int) ([]*T, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	result := []*T{}
+	for index := max(from, 0); index < len(t.documents); index++ {
+		value, err := fromRow[T](t.documents[index])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+func toRow[T any](document *T) (bson.M, error) {
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	row := bson.M{}
+	if err := bson.Unmarshal(data, &row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func fromRow[T any](row bson.M) (*T, error) {
+	data, err := bson.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+
+	value := new(T)
+	if err := bson.Unmarshal(data, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
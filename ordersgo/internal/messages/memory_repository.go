@@ -0,0 +1,48 @@
+package messages
+
+import (
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+)
+
+// NewMemoryMessagesRepository mensajes procesados en memoria, duran lo que dura el proceso
+func NewMemoryMessagesRepository(log log.LogRusEntry, table *memdb.Table[ProcessedMessage]) MessagesRepository {
+	return &memoryMessagesRepository{
+		log:   log,
+		table: table,
+	}
+}
+
+type memoryMessagesRepository struct {
+	log   log.LogRusEntry
+	table *memdb.Table[ProcessedMessage]
+}
+
+func (r *memoryMessagesRepository) Insert(message *ProcessedMessage) (*ProcessedMessage, error) {
+	if err := message.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	err := r.table.Insert(message, func(current *ProcessedMessage) bool {
+		return current.Key == message.Key
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func (r *memoryMessagesRepository) FindByKey(key string) (*ProcessedMessage, error) {
+	return r.table.FindOne(func(message *ProcessedMessage) bool {
+		return message.Key == key
+	})
+}
+
+func (r *memoryMessagesRepository) Delete(key string) error {
+	_, err := r.table.Delete(func(message *ProcessedMessage) bool {
+		return message.Key == key
+	})
+	return err
+}
@@ -0,0 +1,14 @@
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/messages"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestMemoryMessages(t *testing.T) {
+	repotest.MessagesRepository(t, messages.NewMemoryMessagesRepository(log.Get("", "test"), memdb.NewTable[messages.ProcessedMessage]()))
+}
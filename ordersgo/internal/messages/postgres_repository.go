@@ -0,0 +1,89 @@
+package messages
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewPostgresMessagesRepository mensajes procesados sobre la tabla processed_messages.
+// Postgres no tiene indices TTL, los mensajes más viejos que ttl se depuran al insertar.
+func NewPostgresMessagesRepository(log log.LogRusEntry, db pgdb.DB, ttl time.Duration) MessagesRepository {
+	return &postgresMessagesRepository{
+		log: log,
+		db:  db,
+		ttl: ttl,
+	}
+}
+
+type postgresMessagesRepository struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+	ttl time.Duration
+}
+
+func (r *postgresMessagesRepository) Insert(message *ProcessedMessage) (*ProcessedMessage, error) {
+	if err := message.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	id := message.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	tag, err := r.db.Exec(context.Background(), `
+		WITH expired AS (DELETE FROM processed_messages WHERE created < $5)
+		INSERT INTO processed_messages (key, id, queue, created) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO NOTHING`,
+		message.Key, id.Hex(), message.Queue, message.Created, time.Now().Add(-r.ttl),
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, errs.AlreadyExist
+	}
+
+	return message, nil
+}
+
+func (r *postgresMessagesRepository) FindByKey(key string) (*ProcessedMessage, error) {
+	var id string
+	message := &ProcessedMessage{}
+
+	err := r.db.QueryRow(context.Background(),
+		"SELECT id, key, queue, created FROM processed_messages WHERE key = $1", key,
+	).Scan(&id, &message.Key, &message.Queue, &message.Created)
+	if err == pgx.ErrNoRows {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	if message.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	message.Created = message.Created.UTC()
+	return message, nil
+}
+
+func (r *postgresMessagesRepository) Delete(key string) error {
+	if _, err := r.db.Exec(context.Background(), "DELETE FROM processed_messages WHERE key = $1", key); err != nil {
+		r.log.Error(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package messages_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/messages"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestPostgresMessages(t *testing.T) {
+	repotest.MessagesRepository(t, messages.NewPostgresMessagesRepository(log.Get("", "test"), repotest.Postgres(t), 72*time.Hour))
+}
@@ -0,0 +1,74 @@
+package messages
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/db"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MessagesRepository interface {
+	Insert(message *ProcessedMessage) (*ProcessedMessage, error)
+	FindByKey(key string) (*ProcessedMessage, error)
+	Delete(key string) error
+}
+
+// NewMessagesRepository raw es la misma colección, se usa para las operaciones que db.Collection no soporta
+func NewMessagesRepository(log log.LogRusEntry, collection db.Collection, raw *mongo.Collection) MessagesRepository {
+	return &messagesRepository{
+		log:        log,
+		collection: collection,
+		raw:        raw,
+	}
+}
+
+type messagesRepository struct {
+	log        log.LogRusEntry
+	collection db.Collection
+	raw        *mongo.Collection
+}
+
+// Insert registra el mensaje, si la clave ya existe devuelve errs.AlreadyExist
+func (r *messagesRepository) Insert(message *ProcessedMessage) (*ProcessedMessage, error) {
+	if err := message.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	if _, err := r.collection.InsertOne(context.Background(), message); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errs.AlreadyExist
+		}
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// FindByKey busca un mensaje procesado por su clave
+func (r *messagesRepository) FindByKey(key string) (*ProcessedMessage, error) {
+	message := &ProcessedMessage{}
+	filter := bson.M{"key": key}
+	if err := r.collection.FindOne(context.Background(), filter, message); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errs.NotFound
+		}
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func (r *messagesRepository) Delete(key string) error {
+	if _, err := r.raw.DeleteOne(context.Background(), bson.M{"key": key}); err != nil {
+		r.log.Error(err)
+		return err
+	}
+
+	return nil
+}
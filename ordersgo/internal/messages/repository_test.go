@@ -0,0 +1,16 @@
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/messages"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+// TestMongoMessages corre contra MONGO_URL con las colecciones e índices del injector
+func TestMongoMessages(t *testing.T) {
+	deps := &di.Deps{CurrLog: log.Get("", "test"), CurrDatabase: repotest.Mongo(t)}
+	repotest.MessagesRepository(t, messages.NewMessagesRepository(deps.Logger(), deps.MessagesCollection(), deps.Database().Collection("processed_messages")))
+}
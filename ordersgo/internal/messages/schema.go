@@ -0,0 +1,45 @@
+package messages
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Mensaje de rabbit ya procesado, se guarda para descartar reentregas
+type ProcessedMessage struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty"`
+	Key     string             `bson:"key" validate:"required,min=1,max=200"`
+	Queue   string             `bson:"queue" validate:"required,min=1,max=100"`
+	Created time.Time          `bson:"created"`
+}
+
+// ValidateSchema valida la estructura para ser insertada en la db
+func (e *ProcessedMessage) ValidateSchema() error {
+	return validator.New().Struct(e)
+}
+
+// MessageKey genera la clave de idempotencia de un mensaje a partir del hash de su contenido.
+// Las reentregas de rabbit traen el mismo body, por lo que generan la misma clave.
+func MessageKey(queue string, message interface{}) string {
+	body, _ := json.Marshal(message)
+
+	hash := sha256.New()
+	hash.Write([]byte(queue))
+	hash.Write([]byte{0})
+	hash.Write(body)
+
+	return queue + ":" + hex.EncodeToString(hash.Sum(nil))
+}
+
+func newProcessedMessage(queue string, key string) *ProcessedMessage {
+	return &ProcessedMessage{
+		Key:     key,
+		Queue:   queue,
+		Created: time.Now(),
+	}
+}
@@ -0,0 +1,41 @@
+package messages
+
+import (
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+)
+
+type MessagesService interface {
+	Claim(queue string, key string) (bool, error)
+	Release(key string) error
+}
+
+func NewMessagesService(log log.LogRusEntry, repository MessagesRepository) MessagesService {
+	return &messagesService{
+		log:        log,
+		repository: repository,
+	}
+}
+
+type messagesService struct {
+	log        log.LogRusEntry
+	repository MessagesRepository
+}
+
+// Claim reserva el mensaje antes de procesarlo, el índice único sobre la clave garantiza que
+// solo un consumidor lo obtenga. Devuelve false si ya fue reservado o procesado.
+func (s *messagesService) Claim(queue string, key string) (bool, error) {
+	if _, err := s.repository.Insert(newProcessedMessage(queue, key)); err != nil {
+		if err == errs.AlreadyExist {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Release libera el mensaje cuando el procesamiento falló, para que la reentrega lo procese
+func (s *messagesService) Release(key string) error {
+	return s.repository.Delete(key)
+}
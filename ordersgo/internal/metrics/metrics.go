@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics colectores de prometheus del servicio, cada instancia tiene su propio registry
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	HttpRequests *prometheus.CounterVec
+	HttpDuration *prometheus.HistogramVec
+
+	GraphqlOperations *prometheus.CounterVec
+	GraphqlDuration   *prometheus.HistogramVec
+
+	MessagesConsumed  *prometheus.CounterVec
+	MessagesFailed    *prometheus.CounterVec
+	MessagesRetried   *prometheus.CounterVec
+	MessagesPublished *prometheus.CounterVec
+
+	EventStoreInsert *prometheus.HistogramVec
+
+	ProjectionDuration *prometheus.HistogramVec
+	ProjectionFailures *prometheus.CounterVec
+
+	ProjectionQueueDepth   prometheus.Gauge
+	ProjectionCoalesced    prometheus.Counter
+	ProjectionBackpressure prometheus.Counter
+	ProjectionRunnerEvents prometheus.Counter
+}
+
+// New crea los colectores y los registra en un registry nuevo
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+
+		HttpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_http_requests_total",
+			Help: "Requests REST atendidos por ruta y status.",
+		}, []string{"method", "route", "status"}),
+		HttpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "orders_http_request_duration_seconds",
+			Help:    "Latencia de los requests REST por ruta.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+
+		GraphqlOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_graphql_operations_total",
+			Help: "Operaciones GraphQL por nombre y resultado.",
+		}, []string{"operation", "status"}),
+		GraphqlDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "orders_graphql_operation_duration_seconds",
+			Help:    "Latencia de las operaciones GraphQL.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+
+		MessagesConsumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_rabbit_messages_consumed_total",
+			Help: "Mensajes rabbit procesados correctamente por cola.",
+		}, []string{"queue"}),
+		MessagesFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_rabbit_messages_failed_total",
+			Help: "Mensajes rabbit cuyo procesamiento falló por cola.",
+		}, []string{"queue"}),
+		MessagesRetried: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_rabbit_messages_retried_total",
+			Help: "Mensajes rabbit reentregados por cola.",
+		}, []string{"queue"}),
+		MessagesPublished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_rabbit_messages_published_total",
+			Help: "Mensajes rabbit publicados por exchange y resultado.",
+		}, []string{"exchange", "status"}),
+
+		EventStoreInsert: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "orders_event_store_insert_duration_seconds",
+			Help:    "Latencia de inserción en el event store por tipo de evento.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"type"}),
+
+		ProjectionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "orders_projection_update_duration_seconds",
+			Help:    "Duración de la actualización de cada proyección.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"projection"}),
+		ProjectionFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_projection_update_failures_total",
+			Help: "Actualizaciones de proyección fallidas.",
+		}, []string{"projection"}),
+
+		ProjectionQueueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "orders_projection_queue_depth",
+			Help: "Órdenes esperando actualización de proyecciones.",
+		}),
+		ProjectionCoalesced: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "orders_projection_coalesced_total",
+			Help: "Actualizaciones descartadas porque la orden ya estaba en cola.",
+		}),
+		ProjectionBackpressure: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "orders_projection_backpressure_total",
+			Help: "Actualizaciones que esperaron lugar en la cola del worker.",
+		}),
+		ProjectionRunnerEvents: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "orders_projection_runner_events_total",
+			Help: "Eventos del event store aplicados por el runner de proyecciones.",
+		}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HttpRequests,
+		m.HttpDuration,
+		m.GraphqlOperations,
+		m.GraphqlDuration,
+		m.MessagesConsumed,
+		m.MessagesFailed,
+		m.MessagesRetried,
+		m.MessagesPublished,
+		m.EventStoreInsert,
+		m.ProjectionDuration,
+		m.ProjectionFailures,
+		m.ProjectionQueueDepth,
+		m.ProjectionCoalesced,
+		m.ProjectionBackpressure,
+		m.ProjectionRunnerEvents,
+	)
+
+	return m
+}
+
+// Handler expone el registry en formato prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{
+		Registry:      m.Registry,
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// Status devuelve "ok" o "error" para usar como label
+func Status(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
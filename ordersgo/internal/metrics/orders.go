@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterOrdersByStatus registra un gauge de ordenes por estado que se calcula en cada scrape
+func (m *Metrics) RegisterOrdersByStatus(count func() (map[string]int64, error)) {
+	m.Registry.MustRegister(&ordersByStatusCollector{
+		count: count,
+		desc: prometheus.NewDesc(
+			"orders_by_status",
+			"Ordenes en la proyección por estado.",
+			[]string{"status"},
+			nil,
+		),
+	})
+}
+
+type ordersByStatusCollector struct {
+	desc  *prometheus.Desc
+	count func() (map[string]int64, error)
+}
+
+func (c *ordersByStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *ordersByStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.count()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), status)
+	}
+}
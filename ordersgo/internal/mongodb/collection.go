@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/db"
+	"github.com/nmarsollier/commongo/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewCollection igual que db.NewCollection pero las operaciones usan el contexto que reciben,
+// necesario para que participen de la sesión de una transacción.
+func NewCollection(
+	log log.LogRusEntry,
+	database *mongo.Database,
+	collectionName string,
+	onError func(error),
+	indexes ...string,
+) (db.Collection, error) {
+	collection := database.Collection(collectionName)
+
+	for _, index := range indexes {
+		_, err := collection.Indexes().CreateOne(
+			context.Background(),
+			mongo.IndexModel{
+				Keys: bson.M{index: 1},
+			},
+		)
+		if err != nil {
+			onError(err)
+			log.Info(err)
+			return nil, err
+		}
+	}
+
+	return &mongoCollection{
+		collection: collection,
+		onError:    onError,
+	}, nil
+}
+
+type mongoCollection struct {
+	collection *mongo.Collection
+	onError    func(error)
+}
+
+func (m *mongoCollection) FindOne(ctx context.Context, filter interface{}, v interface{}) error {
+	if err := m.collection.FindOne(ctx, filter).Decode(v); err != nil {
+		m.onError(err)
+		return err
+	}
+	return nil
+}
+
+func (m *mongoCollection) InsertOne(ctx context.Context, document interface{}) (interface{}, error) {
+	result, err := m.collection.InsertOne(ctx, document)
+	if err != nil {
+		m.onError(err)
+		return nil, err
+	}
+	return result.InsertedID, nil
+}
+
+func (m *mongoCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, optn *options.UpdateOptions) (int64, error) {
+	result, err := m.collection.UpdateOne(ctx, filter, update, optn)
+	if err != nil {
+		m.onError(err)
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (m *mongoCollection) Find(ctx context.Context, filter interface{}) (db.Cursor, error) {
+	cursor, err := m.collection.Find(ctx, filter)
+	if err != nil {
+		m.onError(err)
+		return nil, err
+	}
+	return db.NewCursor(cursor), nil
+}
+
+func (m *mongoCollection) ReplaceOne(ctx context.Context, filter interface{}, replacement interface{}) (int64, error) {
+	result, err := m.collection.ReplaceOne(ctx, filter, replacement)
+	if err != nil {
+		m.onError(err)
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,17 @@
+-- Event store, append only con versión por orden
+CREATE TABLE events (
+    id         TEXT PRIMARY KEY,
+    order_id   TEXT NOT NULL,
+    version    INTEGER NOT NULL,
+    type       TEXT NOT NULL,
+    cart_id    TEXT,
+    payment_id TEXT,
+    payload    JSONB NOT NULL,
+    created    TIMESTAMPTZ NOT NULL,
+    updated    TIMESTAMPTZ NOT NULL,
+    CONSTRAINT events_order_version UNIQUE (order_id, version)
+);
+
+-- Un solo place_order por carrito
+CREATE UNIQUE INDEX events_place_cart ON events (cart_id) WHERE type = 'place_order';
+CREATE INDEX events_payment ON events (payment_id) WHERE type = 'payment';
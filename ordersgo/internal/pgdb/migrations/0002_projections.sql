@@ -0,0 +1,29 @@
+-- Proyección de ordenes, seq conserva el orden de inserción
+CREATE TABLE order_projection (
+    order_id TEXT PRIMARY KEY,
+    id       TEXT NOT NULL,
+    seq      BIGSERIAL NOT NULL,
+    status   TEXT NOT NULL,
+    user_id  TEXT NOT NULL,
+    cart_id  TEXT NOT NULL,
+    articles JSONB NOT NULL,
+    payments JSONB NOT NULL,
+    created  TIMESTAMPTZ NOT NULL,
+    updated  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX order_projection_user ON order_projection (user_id, seq);
+
+-- Proyección de estados
+CREATE TABLE status_projection (
+    order_id          TEXT PRIMARY KEY,
+    id                TEXT NOT NULL,
+    user_id           TEXT NOT NULL,
+    placed            BOOLEAN NOT NULL,
+    partial_validated BOOLEAN NOT NULL,
+    validated         BOOLEAN NOT NULL,
+    partial_payment   BOOLEAN NOT NULL,
+    payment_completed BOOLEAN NOT NULL,
+    created           TIMESTAMPTZ NOT NULL,
+    updated           TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,9 @@
+-- Mensajes rabbit procesados, se depuran al insertar según MESSAGES_TTL_HOURS
+CREATE TABLE processed_messages (
+    key     TEXT PRIMARY KEY,
+    id      TEXT NOT NULL,
+    queue   TEXT NOT NULL,
+    created TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX processed_messages_created ON processed_messages (created);
@@ -0,0 +1,11 @@
+-- Orden global de los eventos, el runner de proyecciones lee por seq
+ALTER TABLE events ADD COLUMN seq BIGSERIAL;
+CREATE UNIQUE INDEX events_seq ON events (seq);
+
+-- Posición de cada runner de proyecciones en el event store
+CREATE TABLE projection_checkpoints (
+    name     TEXT PRIMARY KEY,
+    id       TEXT NOT NULL,
+    position TEXT NOT NULL,
+    updated  TIMESTAMPTZ NOT NULL
+);
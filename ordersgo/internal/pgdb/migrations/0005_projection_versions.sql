@@ -0,0 +1,7 @@
+-- Versión con la que está construida cada proyección
+CREATE TABLE projection_versions (
+    projection TEXT PRIMARY KEY,
+    id         TEXT NOT NULL,
+    version    INTEGER NOT NULL,
+    updated    TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,11 @@
+-- La proyección de estados pasa a ser una línea de tiempo de hitos.
+-- Los estados anteriores no se pueden convertir, se reconstruye con POST /projections/status/rebuild.
+DELETE FROM status_projection;
+
+ALTER TABLE status_projection
+    DROP COLUMN placed,
+    DROP COLUMN partial_validated,
+    DROP COLUMN validated,
+    DROP COLUMN partial_payment,
+    DROP COLUMN payment_completed,
+    ADD COLUMN milestones JSONB NOT NULL DEFAULT '[]';
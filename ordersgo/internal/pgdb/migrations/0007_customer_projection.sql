@@ -0,0 +1,15 @@
+-- Aporte de cada orden a las estadísticas de su cliente, se agrupa por user_id al consultar
+CREATE TABLE customer_projection (
+    order_id TEXT PRIMARY KEY,
+    id       TEXT NOT NULL,
+    user_id  TEXT NOT NULL,
+    status   TEXT NOT NULL,
+    total    REAL NOT NULL,
+    spent    REAL NOT NULL,
+    refunded REAL NOT NULL,
+    articles JSONB NOT NULL,
+    created  TIMESTAMPTZ NOT NULL,
+    updated  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX customer_projection_user ON customer_projection (user_id);
@@ -0,0 +1,12 @@
+-- Hechos de venta de cada orden, los reportes agrupan por hora, día o mes los que caen en el rango
+CREATE TABLE sales_projection (
+    order_id TEXT PRIMARY KEY,
+    id       TEXT NOT NULL,
+    items    INTEGER NOT NULL,
+    facts    JSONB NOT NULL,
+    first    TIMESTAMPTZ NOT NULL,
+    last     TIMESTAMPTZ NOT NULL,
+    updated  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX sales_projection_range ON sales_projection (first, last);
@@ -0,0 +1,9 @@
+-- Aporte de cada orden a la demanda de sus artículos, se agrupa por articleId al consultar
+CREATE TABLE article_projection (
+    order_id TEXT PRIMARY KEY,
+    id       TEXT NOT NULL,
+    articles JSONB NOT NULL,
+    updated  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX article_projection_articles ON article_projection USING GIN (articles jsonb_path_ops);
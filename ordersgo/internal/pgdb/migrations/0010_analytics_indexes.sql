@@ -0,0 +1,3 @@
+-- Consultas por rango de fechas de los reportes de embudo y pagos rechazados
+CREATE INDEX status_projection_created ON status_projection (created);
+CREATE INDEX events_type_created ON events (type, created);
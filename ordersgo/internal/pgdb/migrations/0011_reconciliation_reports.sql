@@ -0,0 +1,15 @@
+-- Reportes de conciliación de pagos contra payments_node
+CREATE TABLE reconciliation_reports (
+    id            TEXT PRIMARY KEY,
+    range_from    TIMESTAMPTZ NOT NULL,
+    range_to      TIMESTAMPTZ NOT NULL,
+    status        TEXT NOT NULL,
+    error         TEXT NOT NULL,
+    orders        INTEGER NOT NULL,
+    payments      INTEGER NOT NULL,
+    discrepancies JSONB NOT NULL,
+    created       TIMESTAMPTZ NOT NULL,
+    updated       TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX reconciliation_reports_created ON reconciliation_reports (created DESC);
@@ -0,0 +1,3 @@
+-- Total reembolsado de la orden, se completa al reconstruir order_projection
+ALTER TABLE order_projection
+    ADD COLUMN refunded REAL NOT NULL DEFAULT 0;
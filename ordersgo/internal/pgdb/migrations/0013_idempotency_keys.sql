@@ -0,0 +1,12 @@
+-- Respuestas guardadas por Idempotency-Key, se depuran al insertar según IDEMPOTENCY_TTL_HOURS
+CREATE TABLE idempotency_keys (
+    key          TEXT PRIMARY KEY,
+    id           TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status       INTEGER NOT NULL,
+    content_type TEXT NOT NULL,
+    body         TEXT NOT NULL,
+    created      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idempotency_keys_created ON idempotency_keys (created);
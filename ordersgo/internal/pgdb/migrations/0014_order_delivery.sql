@@ -0,0 +1,3 @@
+-- Datos de entrega de la orden, se completan al reconstruir order_projection
+ALTER TABLE order_projection
+    ADD COLUMN delivery JSONB;
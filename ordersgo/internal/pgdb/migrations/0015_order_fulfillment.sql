@@ -0,0 +1,3 @@
+-- Estado del envío de la orden, se completa al reconstruir order_projection
+ALTER TABLE order_projection
+    ADD COLUMN fulfillment JSONB;
@@ -0,0 +1,3 @@
+-- Un solo evento inicial por pago, cierra la carrera entre el find y el insert de los pagos manuales
+CREATE UNIQUE INDEX events_payment_first ON events (order_id, payment_id)
+    WHERE type = 'payment' AND COALESCE(payload->'payment'->>'Status', '') = '';
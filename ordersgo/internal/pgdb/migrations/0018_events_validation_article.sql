@@ -0,0 +1,9 @@
+-- Una sola validación por artículo de la orden. Si hay duplicados previos no se puede crear,
+-- se sigue sin el indice igual que en mongo
+DO $$
+BEGIN
+    CREATE UNIQUE INDEX events_validation_article ON events (order_id, (payload->'validation'->>'articleId'))
+        WHERE type = 'aticle_validation';
+EXCEPTION WHEN unique_violation THEN
+    RAISE WARNING 'events_validation_article not created, duplicated validations';
+END $$;
@@ -0,0 +1,4 @@
+-- Un solo reembolso por número en cada pago, serializa los reembolsos concurrentes.
+-- Los reembolsos anteriores no tienen número.
+CREATE UNIQUE INDEX events_refund_sequence ON events (order_id, payment_id, ((payload->'refund'->>'sequence')::int))
+    WHERE type = 'payment_refund' AND payload->'refund' ? 'sequence';
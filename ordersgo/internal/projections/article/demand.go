@@ -0,0 +1,82 @@
+package article
+
+import (
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+)
+
+// newOrderArticles un artículo se cuenta pagado si la orden se pagó por completo y no se canceló,
+// el ingreso es el precio validado por la cantidad.
+func newOrderArticles(orderId string, ev []*events.Event) *OrderArticles {
+	current := order.Project(orderId, ev)
+	timeline := status.Project(orderId, ev)
+	canceled := timeline.Reached(status.Canceled)
+	paid := timeline.Reached(status.FullyPaid) && !canceled
+
+	result := &OrderArticles{
+		OrderId:  orderId,
+		Articles: []*ArticleLine{},
+		Updated:  current.Updated,
+	}
+
+	for _, a := range current.Articles {
+		line := &ArticleLine{
+			ArticleId: a.ArticleId,
+			Quantity:  a.Quantity,
+		}
+		if a.IsValidated {
+			line.Validated = 1
+			if !a.IsValid {
+				line.Invalid = 1
+			}
+		}
+		if paid {
+			line.QuantityPaid = a.Quantity
+			line.Revenue = a.UnitaryPrice * float32(a.Quantity)
+		}
+		if canceled {
+			line.QuantityCanceled = a.Quantity
+		}
+		result.Articles = append(result.Articles, line)
+	}
+
+	return result
+}
+
+// rates completa los valores calculados después de agrupar
+func (d *ArticleDemand) rates() *ArticleDemand {
+	d.InvalidRate = 0
+	if d.Validations > 0 {
+		d.InvalidRate = float32(d.Invalid) / float32(d.Validations)
+	}
+	return d
+}
+
+// aggregate agrupa por artículo como lo hacen las consultas de mongo y postgres, vacío agrupa todos
+func aggregate(orders []*OrderArticles, articleId string) []*ArticleDemand {
+	result := []*ArticleDemand{}
+	index := map[string]*ArticleDemand{}
+	for _, o := range orders {
+		for _, line := range o.Articles {
+			if articleId != "" && line.ArticleId != articleId {
+				continue
+			}
+
+			demand, ok := index[line.ArticleId]
+			if !ok {
+				demand = &ArticleDemand{ArticleId: line.ArticleId}
+				index[line.ArticleId] = demand
+				result = append(result, demand)
+			}
+			demand.Orders++
+			demand.QuantityOrdered += line.Quantity
+			demand.QuantityPaid += line.QuantityPaid
+			demand.QuantityCanceled += line.QuantityCanceled
+			demand.Validations += line.Validated
+			demand.Invalid += line.Invalid
+			demand.Revenue += line.Revenue
+		}
+	}
+	return result
+}
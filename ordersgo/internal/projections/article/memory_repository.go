@@ -0,0 +1,59 @@
+package article
+
+import (
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+)
+
+// NewMemoryArticleRepository demanda de artículos en memoria, la tabla se comparte entre requests
+func NewMemoryArticleRepository(log log.LogRusEntry, table *memdb.Table[OrderArticles]) ArticleRepository {
+	return &memoryArticleRepository{
+		log:   log,
+		table: table,
+	}
+}
+
+type memoryArticleRepository struct {
+	log   log.LogRusEntry
+	table *memdb.Table[OrderArticles]
+}
+
+func (r *memoryArticleRepository) Insert(articles *OrderArticles) (*OrderArticles, error) {
+	if err := articles.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	err := r.table.Upsert(articles, func(current *OrderArticles) bool {
+		return current.OrderId == articles.OrderId
+	})
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+func (r *memoryArticleRepository) FindByArticleId(articleId string) (*ArticleDemand, error) {
+	orders, err := r.table.Find(func(*OrderArticles) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	result := aggregate(orders, articleId)
+	if len(result) == 0 {
+		return nil, errs.NotFound
+	}
+	return result[0], nil
+}
+
+func (r *memoryArticleRepository) FindAll() ([]*ArticleDemand, error) {
+	orders, err := r.table.Find(func(*OrderArticles) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregate(orders, ""), nil
+}
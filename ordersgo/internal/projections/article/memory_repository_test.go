@@ -0,0 +1,14 @@
+package article_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/projections/article"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestMemoryArticle(t *testing.T) {
+	repotest.ArticleRepository(t, article.NewMemoryArticleRepository(log.Get("", "test"), memdb.NewTable[article.OrderArticles]()))
+}
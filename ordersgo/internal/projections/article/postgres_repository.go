@@ -0,0 +1,132 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewPostgresArticleRepository demanda de artículos sobre la tabla article_projection
+func NewPostgresArticleRepository(log log.LogRusEntry, db pgdb.DB) ArticleRepository {
+	return &postgresArticleRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+type postgresArticleRepository struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+}
+
+// Insert crea o reemplaza el aporte de la orden, conserva el id de la primera inserción
+func (r *postgresArticleRepository) Insert(articles *OrderArticles) (*OrderArticles, error) {
+	if err := articles.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	lines, err := json.Marshal(articles.Articles)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	id := articles.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	_, err = r.db.Exec(context.Background(), `
+		INSERT INTO article_projection (order_id, id, articles, updated)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (order_id) DO UPDATE SET
+			articles = EXCLUDED.articles,
+			updated = EXCLUDED.updated`,
+		articles.OrderId, id.Hex(), lines, articles.Updated,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// groupDemand suma las líneas de cada artículo, las condiciones se agregan antes del GROUP BY
+const groupDemand = `
+	SELECT a->>'articleId',
+		COUNT(*),
+		SUM((a->>'quantity')::int),
+		SUM((a->>'quantityPaid')::int),
+		SUM((a->>'quantityCanceled')::int),
+		SUM((a->>'validated')::int),
+		SUM((a->>'invalid')::int),
+		SUM((a->>'revenue')::real)
+	FROM article_projection, jsonb_array_elements(articles) a `
+
+func (r *postgresArticleRepository) FindByArticleId(articleId string) (*ArticleDemand, error) {
+	rows, err := r.db.Query(context.Background(), groupDemand+`
+		WHERE articles @> jsonb_build_array(jsonb_build_object('articleId', $1::text))
+			AND a->>'articleId' = $1
+		GROUP BY 1`, articleId,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	demand, err := pgx.CollectOneRow(rows, scanDemand)
+	if err == pgx.ErrNoRows {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return demand, nil
+}
+
+func (r *postgresArticleRepository) FindAll() ([]*ArticleDemand, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx, groupDemand+"GROUP BY 1")
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	result, err := pgx.CollectRows(rows, scanDemand)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func scanDemand(row pgx.CollectableRow) (*ArticleDemand, error) {
+	demand := &ArticleDemand{}
+	err := row.Scan(
+		&demand.ArticleId,
+		&demand.Orders,
+		&demand.QuantityOrdered,
+		&demand.QuantityPaid,
+		&demand.QuantityCanceled,
+		&demand.Validations,
+		&demand.Invalid,
+		&demand.Revenue,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return demand, nil
+}
@@ -0,0 +1,13 @@
+package article_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/projections/article"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestPostgresArticle(t *testing.T) {
+	repotest.ArticleRepository(t, article.NewPostgresArticleRepository(log.Get("", "test"), repotest.Postgres(t)))
+}
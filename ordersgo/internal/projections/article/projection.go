@@ -0,0 +1,35 @@
+package article
+
+import (
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// Version de article_projection, se incrementa al cambiar cómo se proyecta para reconstruirla
+const Version = 2
+
+// NewArticleProjection registra article_projection en el registro de proyecciones
+func NewArticleProjection(service ArticleService) *ArticleProjection {
+	return &ArticleProjection{
+		service: service,
+	}
+}
+
+type ArticleProjection struct {
+	service ArticleService
+}
+
+func (p *ArticleProjection) Name() string {
+	return "article"
+}
+
+func (p *ArticleProjection) Version() int {
+	return Version
+}
+
+func (p *ArticleProjection) EventTypes() []events.EventType {
+	return []events.EventType{events.Place, events.Validation, events.Payment, events.Refund, events.Cancel}
+}
+
+func (p *ArticleProjection) Apply(orderId string, ev []*events.Event) error {
+	return p.service.Update(orderId, ev)
+}
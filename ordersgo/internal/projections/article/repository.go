@@ -0,0 +1,134 @@
+package article
+
+import (
+	"context"
+	"time"
+
+	"github.com/nmarsollier/commongo/db"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ArticleRepository interface {
+	Insert(articles *OrderArticles) (*OrderArticles, error)
+	FindByArticleId(articleId string) (*ArticleDemand, error)
+	FindAll() ([]*ArticleDemand, error)
+}
+
+// NewArticleRepository aggregate es la misma colección, se usa para las operaciones que db.Collection no soporta
+func NewArticleRepository(log log.LogRusEntry, collection db.Collection, aggregate *mongo.Collection) ArticleRepository {
+	return &articleRepository{
+		log:        log,
+		collection: collection,
+		aggregate:  aggregate,
+	}
+}
+
+type articleRepository struct {
+	log        log.LogRusEntry
+	collection db.Collection
+	aggregate  *mongo.Collection
+}
+
+// Insert crea o reemplaza el aporte de la orden
+func (r *articleRepository) Insert(articles *OrderArticles) (*OrderArticles, error) {
+	if err := articles.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	filter := bson.M{"orderId": articles.OrderId}
+	updateOptions := options.Update().SetUpsert(true)
+	document := upsertArticles{
+		Set: articles,
+	}
+
+	if _, err := r.collection.UpdateOne(context.Background(), filter, document, updateOptions); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	return articles, nil
+}
+
+type upsertArticles struct {
+	Set *OrderArticles `bson:"$set"`
+}
+
+func (r *articleRepository) FindByArticleId(articleId string) (*ArticleDemand, error) {
+	match := bson.M{"articles.articleId": articleId}
+	result, err := r.group(
+		bson.D{{Key: "$match", Value: match}},
+		bson.D{{Key: "$unwind", Value: "$articles"}},
+		bson.D{{Key: "$match", Value: match}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, errs.NotFound
+	}
+	return result[0], nil
+}
+
+func (r *articleRepository) FindAll() ([]*ArticleDemand, error) {
+	return r.group(bson.D{{Key: "$unwind", Value: "$articles"}})
+}
+
+// group suma las líneas de cada artículo después de los stages
+func (r *articleRepository) group(stages ...bson.D) ([]*ArticleDemand, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline(stages)
+	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.M{
+		"_id":              "$articles.articleId",
+		"orders":           bson.M{"$sum": 1},
+		"quantityOrdered":  bson.M{"$sum": "$articles.quantity"},
+		"quantityPaid":     bson.M{"$sum": "$articles.quantityPaid"},
+		"quantityCanceled": bson.M{"$sum": "$articles.quantityCanceled"},
+		"validations":      bson.M{"$sum": "$articles.validated"},
+		"invalid":          bson.M{"$sum": "$articles.invalid"},
+		"revenue":          bson.M{"$sum": "$articles.revenue"},
+	}}})
+
+	cur, err := r.aggregate.Aggregate(ctx, pipeline)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	result := []*ArticleDemand{}
+	for cur.Next(ctx) {
+		// Las sumas de float32 vuelven como double, no se pueden decodificar directo en float32
+		row := struct {
+			ArticleId        string  `bson:"_id"`
+			Orders           int     `bson:"orders"`
+			QuantityOrdered  int     `bson:"quantityOrdered"`
+			QuantityPaid     int     `bson:"quantityPaid"`
+			QuantityCanceled int     `bson:"quantityCanceled"`
+			Validations      int     `bson:"validations"`
+			Invalid          int     `bson:"invalid"`
+			Revenue          float64 `bson:"revenue"`
+		}{}
+		if err := cur.Decode(&row); err != nil {
+			r.log.Error(err)
+			return nil, err
+		}
+		result = append(result, &ArticleDemand{
+			ArticleId:        row.ArticleId,
+			Orders:           row.Orders,
+			QuantityOrdered:  row.QuantityOrdered,
+			QuantityPaid:     row.QuantityPaid,
+			QuantityCanceled: row.QuantityCanceled,
+			Validations:      row.Validations,
+			Invalid:          row.Invalid,
+			Revenue:          float32(row.Revenue),
+		})
+	}
+
+	return result, nil
+}
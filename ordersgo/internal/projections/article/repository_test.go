@@ -0,0 +1,16 @@
+package article_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/projections/article"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+// TestMongoArticle corre contra MONGO_URL con las colecciones e índices del injector
+func TestMongoArticle(t *testing.T) {
+	deps := &di.Deps{CurrLog: log.Get("", "test"), CurrDatabase: repotest.Mongo(t)}
+	repotest.ArticleRepository(t, article.NewArticleRepository(deps.Logger(), deps.ArticlesCollection(), deps.Database().Collection("article_projection")))
+}
@@ -0,0 +1,45 @@
+package article
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OrderArticles aporte de una orden a la demanda de cada uno de sus artículos.
+// Las cantidades ya vienen resueltas para que agrupar por artículo sea solo sumar.
+type OrderArticles struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OrderId  string             `bson:"orderId" json:"orderId" validate:"required,min=1,max=100"`
+	Articles []*ArticleLine     `bson:"articles" json:"articles"`
+	Updated  time.Time          `bson:"updated" json:"updated"`
+}
+
+type ArticleLine struct {
+	ArticleId        string  `bson:"articleId" json:"articleId"`
+	Quantity         int     `bson:"quantity" json:"quantity"`
+	QuantityPaid     int     `bson:"quantityPaid" json:"quantityPaid"`
+	QuantityCanceled int     `bson:"quantityCanceled" json:"quantityCanceled"`
+	Validated        int     `bson:"validated" json:"validated"` // 1 si el artículo se validó
+	Invalid          int     `bson:"invalid" json:"invalid"`     // 1 si la validación lo rechazó
+	Revenue          float32 `bson:"revenue" json:"revenue"`
+}
+
+// ValidateSchema valida la estructura para ser insertada en la db
+func (e *OrderArticles) ValidateSchema() error {
+	return validator.New().Struct(e)
+}
+
+// ArticleDemand demanda de un artículo en todas las ordenes
+type ArticleDemand struct {
+	ArticleId        string  `json:"articleId"`
+	Orders           int     `json:"orders"`
+	QuantityOrdered  int     `json:"quantityOrdered"`
+	QuantityPaid     int     `json:"quantityPaid"`
+	QuantityCanceled int     `json:"quantityCanceled"`
+	Validations      int     `json:"validations"`
+	Invalid          int     `json:"invalid"`
+	InvalidRate      float32 `json:"invalidRate"`
+	Revenue          float32 `json:"revenue"`
+}
@@ -0,0 +1,112 @@
+package article
+
+import (
+	"sort"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+type ArticleService interface {
+	Update(orderId string, ev []*events.Event) error
+	FindByArticleId(articleId string) (*ArticleDemand, error)
+	Top(limit int) ([]*ArticleDemand, error)
+	Problems(limit int) ([]*ArticleDemand, error)
+}
+
+func NewArticleService(log log.LogRusEntry, repository ArticleRepository) ArticleService {
+	return &articleService{
+		log:        log,
+		repository: repository,
+	}
+}
+
+type articleService struct {
+	log        log.LogRusEntry
+	repository ArticleRepository
+}
+
+// Update reemplaza el aporte de la orden, calculado con todos sus eventos
+func (s *articleService) Update(orderId string, ev []*events.Event) error {
+	articles := newOrderArticles(orderId, ev)
+	if len(articles.Articles) == 0 {
+		// Sin place_order todavía no hay artículos
+		return nil
+	}
+
+	if _, err := s.repository.Insert(articles); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *articleService) FindByArticleId(articleId string) (*ArticleDemand, error) {
+	demand, err := s.repository.FindByArticleId(articleId)
+	if err != nil {
+		return nil, err
+	}
+	return demand.rates(), nil
+}
+
+// Top artículos más pedidos
+func (s *articleService) Top(limit int) ([]*ArticleDemand, error) {
+	all, err := s.findAll()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].QuantityOrdered != all[j].QuantityOrdered {
+			return all[i].QuantityOrdered > all[j].QuantityOrdered
+		}
+		return all[i].ArticleId < all[j].ArticleId
+	})
+	return first(all, limit), nil
+}
+
+// Problems artículos rechazados en la validación o en ordenes canceladas,
+// primero los de mayor tasa de rechazo
+func (s *articleService) Problems(limit int) ([]*ArticleDemand, error) {
+	all, err := s.findAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*ArticleDemand{}
+	for _, demand := range all {
+		if demand.Invalid > 0 || demand.QuantityCanceled > 0 {
+			result = append(result, demand)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].InvalidRate != result[j].InvalidRate {
+			return result[i].InvalidRate > result[j].InvalidRate
+		}
+		if result[i].QuantityCanceled != result[j].QuantityCanceled {
+			return result[i].QuantityCanceled > result[j].QuantityCanceled
+		}
+		return result[i].ArticleId < result[j].ArticleId
+	})
+	return first(result, limit), nil
+}
+
+func (s *articleService) findAll() ([]*ArticleDemand, error) {
+	all, err := s.repository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, demand := range all {
+		demand.rates()
+	}
+	return all, nil
+}
+
+func first(demand []*ArticleDemand, limit int) []*ArticleDemand {
+	if limit > 0 && len(demand) > limit {
+		return demand[:limit]
+	}
+	return demand
+}
@@ -0,0 +1,42 @@
+package checkpoint
+
+import (
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+)
+
+// NewMemoryCheckpointRepository checkpoints en memoria, la tabla se comparte entre requests
+func NewMemoryCheckpointRepository(log log.LogRusEntry, table *memdb.Table[Checkpoint]) CheckpointRepository {
+	return &memoryCheckpointRepository{
+		log:   log,
+		table: table,
+	}
+}
+
+type memoryCheckpointRepository struct {
+	log   log.LogRusEntry
+	table *memdb.Table[Checkpoint]
+}
+
+func (r *memoryCheckpointRepository) Save(checkpoint *Checkpoint) (*Checkpoint, error) {
+	if err := checkpoint.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	err := r.table.Upsert(checkpoint, func(current *Checkpoint) bool {
+		return current.Name == checkpoint.Name
+	})
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+func (r *memoryCheckpointRepository) FindByName(name string) (*Checkpoint, error) {
+	return r.table.FindOne(func(checkpoint *Checkpoint) bool {
+		return checkpoint.Name == name
+	})
+}
@@ -0,0 +1,14 @@
+package checkpoint_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/projections/checkpoint"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestMemoryCheckpoint(t *testing.T) {
+	repotest.CheckpointRepository(t, checkpoint.NewMemoryCheckpointRepository(log.Get("", "test"), memdb.NewTable[checkpoint.Checkpoint]()))
+}
@@ -0,0 +1,78 @@
+package checkpoint
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewPostgresCheckpointRepository checkpoints sobre la tabla projection_checkpoints
+func NewPostgresCheckpointRepository(log log.LogRusEntry, db pgdb.DB) CheckpointRepository {
+	return &postgresCheckpointRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+type postgresCheckpointRepository struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+}
+
+// Save crea o reemplaza el checkpoint, conserva el id de la primera inserción
+func (r *postgresCheckpointRepository) Save(checkpoint *Checkpoint) (*Checkpoint, error) {
+	if err := checkpoint.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	id := checkpoint.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	_, err := r.db.Exec(context.Background(), `
+		INSERT INTO projection_checkpoints (name, id, position, updated)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE SET
+			position = EXCLUDED.position,
+			updated = EXCLUDED.updated`,
+		checkpoint.Name, id.Hex(), checkpoint.Position, checkpoint.Updated,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+func (r *postgresCheckpointRepository) FindByName(name string) (*Checkpoint, error) {
+	var id string
+	var updated time.Time
+	checkpoint := &Checkpoint{}
+
+	err := r.db.QueryRow(context.Background(), `
+		SELECT id, name, position, updated FROM projection_checkpoints WHERE name = $1`, name,
+	).Scan(&id, &checkpoint.Name, &checkpoint.Position, &updated)
+	if err == pgx.ErrNoRows {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	if checkpoint.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	checkpoint.Updated = updated.UTC()
+	return checkpoint, nil
+}
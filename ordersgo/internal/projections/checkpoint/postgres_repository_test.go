@@ -0,0 +1,13 @@
+package checkpoint_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/projections/checkpoint"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestPostgresCheckpoint(t *testing.T) {
+	repotest.CheckpointRepository(t, checkpoint.NewPostgresCheckpointRepository(log.Get("", "test"), repotest.Postgres(t)))
+}
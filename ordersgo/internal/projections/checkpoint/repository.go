@@ -0,0 +1,67 @@
+package checkpoint
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/db"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CheckpointRepository interface {
+	Save(checkpoint *Checkpoint) (*Checkpoint, error)
+	FindByName(name string) (*Checkpoint, error)
+}
+
+func NewCheckpointRepository(log log.LogRusEntry, collection db.Collection) CheckpointRepository {
+	return &checkpointRepository{
+		log:        log,
+		collection: collection,
+	}
+}
+
+type checkpointRepository struct {
+	log        log.LogRusEntry
+	collection db.Collection
+}
+
+// Save crea o reemplaza el checkpoint con el mismo nombre
+func (r *checkpointRepository) Save(checkpoint *Checkpoint) (*Checkpoint, error) {
+	if err := checkpoint.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	filter := bson.M{"name": checkpoint.Name}
+	updateOptions := options.Update().SetUpsert(true)
+	document := upsertCheckpoint{
+		Set: checkpoint,
+	}
+
+	if _, err := r.collection.UpdateOne(context.Background(), filter, document, updateOptions); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+type upsertCheckpoint struct {
+	Set *Checkpoint `bson:"$set"`
+}
+
+func (r *checkpointRepository) FindByName(name string) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{}
+	filter := bson.M{"name": name}
+	if err := r.collection.FindOne(context.Background(), filter, checkpoint); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errs.NotFound
+		}
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
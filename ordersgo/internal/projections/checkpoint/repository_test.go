@@ -0,0 +1,16 @@
+package checkpoint_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/projections/checkpoint"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+// TestMongoCheckpoint corre contra MONGO_URL con las colecciones e índices del injector
+func TestMongoCheckpoint(t *testing.T) {
+	deps := &di.Deps{CurrLog: log.Get("", "test"), CurrDatabase: repotest.Mongo(t)}
+	repotest.CheckpointRepository(t, checkpoint.NewCheckpointRepository(deps.Logger(), deps.CheckpointsCollection()))
+}
@@ -0,0 +1,23 @@
+package checkpoint
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Checkpoint posición de un runner de proyecciones en el event store.
+// Position depende del backend: resume token del change stream en mongo,
+// seq del evento en postgres o cantidad de eventos leídos en memoria.
+type Checkpoint struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name     string             `bson:"name" validate:"required"`
+	Position string             `bson:"position"`
+	Updated  time.Time          `bson:"updated"`
+}
+
+// ValidateSchema valida la estructura para ser insertada en la db
+func (e *Checkpoint) ValidateSchema() error {
+	return validator.New().Struct(e)
+}
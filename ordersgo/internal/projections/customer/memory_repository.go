@@ -0,0 +1,42 @@
+package customer
+
+import (
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+)
+
+// NewMemoryCustomerRepository estadísticas de clientes en memoria, la tabla se comparte entre requests
+func NewMemoryCustomerRepository(log log.LogRusEntry, table *memdb.Table[OrderStats]) CustomerRepository {
+	return &memoryCustomerRepository{
+		log:   log,
+		table: table,
+	}
+}
+
+type memoryCustomerRepository struct {
+	log   log.LogRusEntry
+	table *memdb.Table[OrderStats]
+}
+
+func (r *memoryCustomerRepository) Insert(stats *OrderStats) (*OrderStats, error) {
+	if err := stats.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	err := r.table.Upsert(stats, func(current *OrderStats) bool {
+		return current.OrderId == stats.OrderId
+	})
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *memoryCustomerRepository) FindByUserId(userId string) ([]*OrderStats, error) {
+	return r.table.Find(func(stats *OrderStats) bool {
+		return stats.UserId == userId
+	})
+}
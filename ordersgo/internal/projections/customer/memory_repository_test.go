@@ -0,0 +1,14 @@
+package customer_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/projections/customer"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestMemoryCustomer(t *testing.T) {
+	repotest.CustomerRepository(t, customer.NewMemoryCustomerRepository(log.Get("", "test"), memdb.NewTable[customer.OrderStats]()))
+}
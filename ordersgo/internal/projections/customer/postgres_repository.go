@@ -0,0 +1,107 @@
+package customer
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewPostgresCustomerRepository estadísticas de clientes sobre la tabla customer_projection
+func NewPostgresCustomerRepository(log log.LogRusEntry, db pgdb.DB) CustomerRepository {
+	return &postgresCustomerRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+type postgresCustomerRepository struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+}
+
+// Insert crea o reemplaza el aporte de la orden, conserva el id de la primera inserción
+func (r *postgresCustomerRepository) Insert(stats *OrderStats) (*OrderStats, error) {
+	if err := stats.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	articles, err := json.Marshal(stats.Articles)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	id := stats.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	_, err = r.db.Exec(context.Background(), `
+		INSERT INTO customer_projection (order_id, id, user_id, status, total, spent, refunded, articles, created, updated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (order_id) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			status = EXCLUDED.status,
+			total = EXCLUDED.total,
+			spent = EXCLUDED.spent,
+			refunded = EXCLUDED.refunded,
+			articles = EXCLUDED.articles,
+			created = EXCLUDED.created,
+			updated = EXCLUDED.updated`,
+		stats.OrderId, id.Hex(), stats.UserId, stats.Status, stats.Total, stats.Spent, stats.Refunded, articles, stats.Created, stats.Updated,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *postgresCustomerRepository) FindByUserId(userId string) ([]*OrderStats, error) {
+	rows, err := r.db.Query(context.Background(), `
+		SELECT id, order_id, user_id, status, total, spent, refunded, articles, created, updated
+		FROM customer_projection WHERE user_id = $1`, userId,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	result, err := pgx.CollectRows(rows, scanStats)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func scanStats(row pgx.CollectableRow) (*OrderStats, error) {
+	var id string
+	var articles []byte
+	var created, updated time.Time
+	stats := &OrderStats{}
+
+	err := row.Scan(&id, &stats.OrderId, &stats.UserId, &stats.Status, &stats.Total, &stats.Spent, &stats.Refunded, &articles, &created, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(articles, &stats.Articles); err != nil {
+		return nil, err
+	}
+
+	stats.Created = created.UTC()
+	stats.Updated = updated.UTC()
+	return stats, nil
+}
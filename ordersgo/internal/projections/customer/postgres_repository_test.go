@@ -0,0 +1,13 @@
+package customer_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/projections/customer"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestPostgresCustomer(t *testing.T) {
+	repotest.CustomerRepository(t, customer.NewPostgresCustomerRepository(log.Get("", "test"), repotest.Postgres(t)))
+}
@@ -0,0 +1,35 @@
+package customer
+
+import (
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// Version de customer_projection, se incrementa al cambiar cómo se proyecta para reconstruirla
+const Version = 3
+
+// NewCustomerProjection registra customer_projection en el registro de proyecciones
+func NewCustomerProjection(service CustomerService) *CustomerProjection {
+	return &CustomerProjection{
+		service: service,
+	}
+}
+
+type CustomerProjection struct {
+	service CustomerService
+}
+
+func (p *CustomerProjection) Name() string {
+	return "customer"
+}
+
+func (p *CustomerProjection) Version() int {
+	return Version
+}
+
+func (p *CustomerProjection) EventTypes() []events.EventType {
+	return []events.EventType{events.Place, events.Validation, events.Payment, events.Refund, events.Cancel, events.Fulfillment}
+}
+
+func (p *CustomerProjection) Apply(orderId string, ev []*events.Event) error {
+	return p.service.Update(orderId, ev)
+}
@@ -0,0 +1,73 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/db"
+	"github.com/nmarsollier/commongo/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CustomerRepository interface {
+	Insert(stats *OrderStats) (*OrderStats, error)
+	FindByUserId(userId string) ([]*OrderStats, error)
+}
+
+func NewCustomerRepository(log log.LogRusEntry, collection db.Collection) CustomerRepository {
+	return &customerRepository{
+		log:        log,
+		collection: collection,
+	}
+}
+
+type customerRepository struct {
+	log        log.LogRusEntry
+	collection db.Collection
+}
+
+// Insert crea o reemplaza el aporte de la orden
+func (r *customerRepository) Insert(stats *OrderStats) (*OrderStats, error) {
+	if err := stats.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	filter := bson.M{"orderId": stats.OrderId}
+	updateOptions := options.Update().SetUpsert(true)
+	document := upsertStats{
+		Set: stats,
+	}
+
+	if _, err := r.collection.UpdateOne(context.Background(), filter, document, updateOptions); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	return stats, nil
+}
+
+type upsertStats struct {
+	Set *OrderStats `bson:"$set"`
+}
+
+func (r *customerRepository) FindByUserId(userId string) ([]*OrderStats, error) {
+	filter := bson.M{"userId": userId}
+	cur, err := r.collection.Find(context.Background(), filter)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	result := []*OrderStats{}
+	for cur.Next(context.Background()) {
+		stats := &OrderStats{}
+		if err := cur.Decode(stats); err != nil {
+			r.log.Error(err)
+			return nil, err
+		}
+		result = append(result, stats)
+	}
+
+	return result, nil
+}
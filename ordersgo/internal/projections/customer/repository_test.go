@@ -0,0 +1,16 @@
+package customer_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/projections/customer"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+// TestMongoCustomer corre contra MONGO_URL con las colecciones e índices del injector
+func TestMongoCustomer(t *testing.T) {
+	deps := &di.Deps{CurrLog: log.Get("", "test"), CurrDatabase: repotest.Mongo(t)}
+	repotest.CustomerRepository(t, customer.NewCustomerRepository(deps.Logger(), deps.CustomerCollection()))
+}
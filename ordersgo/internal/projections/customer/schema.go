@@ -0,0 +1,57 @@
+package customer
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OrderStats aporte de una orden a las estadísticas de su cliente.
+// Se guarda uno por orden para que reproyectar una orden sea idempotente
+// y dos ordenes del mismo cliente no compitan por el mismo documento.
+type OrderStats struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OrderId  string             `bson:"orderId" json:"orderId" validate:"required,min=1,max=100"`
+	UserId   string             `bson:"userId" json:"userId" validate:"required,min=1,max=100"`
+	Status   order.OrderStatus  `bson:"status" json:"status" validate:"required"`
+	Total    float32            `bson:"total" json:"total"`
+	Spent    float32            `bson:"spent" json:"spent"`
+	Refunded float32            `bson:"refunded" json:"refunded"`
+	Articles []*ArticleCount    `bson:"articles" json:"articles"`
+	Created  time.Time          `bson:"created" json:"created"`
+	Updated  time.Time          `bson:"updated" json:"updated"`
+}
+
+type ArticleCount struct {
+	ArticleId string `bson:"articleId" json:"articleId"`
+	Quantity  int    `bson:"quantity" json:"quantity"`
+}
+
+// ValidateSchema valida la estructura para ser insertada en la db
+func (e *OrderStats) ValidateSchema() error {
+	return validator.New().Struct(e)
+}
+
+// CustomerStats estadísticas de las ordenes de un cliente
+type CustomerStats struct {
+	UserId   string                    `json:"userId"`
+	Orders   int                       `json:"orders"`
+	ByStatus map[order.OrderStatus]int `json:"byStatus"`
+
+	TotalSpent        float32 `json:"totalSpent"`
+	TotalRefunded     float32 `json:"totalRefunded"`
+	AverageOrderValue float32 `json:"averageOrderValue"`
+
+	FirstOrder *time.Time `json:"firstOrder,omitempty"`
+	LastOrder  *time.Time `json:"lastOrder,omitempty"`
+
+	FavouriteArticles []*FavouriteArticle `json:"favouriteArticles"`
+}
+
+type FavouriteArticle struct {
+	ArticleId string `json:"articleId"`
+	Quantity  int    `json:"quantity"`
+	Orders    int    `json:"orders"`
+}
@@ -0,0 +1,48 @@
+package customer
+
+import (
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+type CustomerService interface {
+	Update(orderId string, ev []*events.Event) error
+	FindByUserId(userId string) (*CustomerStats, error)
+}
+
+func NewCustomerService(log log.LogRusEntry, repository CustomerRepository) CustomerService {
+	return &customerService{
+		log:        log,
+		repository: repository,
+	}
+}
+
+type customerService struct {
+	log        log.LogRusEntry
+	repository CustomerRepository
+}
+
+// Update reemplaza el aporte de la orden, calculado con todos sus eventos
+func (s *customerService) Update(orderId string, ev []*events.Event) error {
+	stats := newOrderStats(orderId, ev)
+	if stats.UserId == "" {
+		// Sin place_order todavía no se sabe a que cliente pertenece
+		return nil
+	}
+
+	if _, err := s.repository.Insert(stats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindByUserId un cliente sin ordenes tiene estadísticas vacías
+func (s *customerService) FindByUserId(userId string) (*CustomerStats, error) {
+	orders, err := s.repository.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCustomerStats(userId, orders), nil
+}
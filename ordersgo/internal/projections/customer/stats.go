@@ -0,0 +1,103 @@
+package customer
+
+import (
+	"sort"
+
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+)
+
+// favouriteArticles cantidad de artículos que se informan como favoritos
+const favouriteArticles = 5
+
+// newOrderStats calcula el aporte de la orden con las mismas reglas que order_projection
+func newOrderStats(orderId string, ev []*events.Event) *OrderStats {
+	current := order.Project(orderId, ev)
+
+	stats := &OrderStats{
+		OrderId:  current.OrderId,
+		UserId:   current.UserId,
+		Status:   current.Status,
+		Total:    current.TotalPrice(),
+		Articles: []*ArticleCount{},
+		Created:  current.Created,
+		Updated:  current.Updated,
+	}
+
+	for _, a := range current.Articles {
+		stats.Articles = append(stats.Articles, &ArticleCount{
+			ArticleId: a.ArticleId,
+			Quantity:  a.Quantity,
+		})
+	}
+
+	// Lo capturado es lo gastado, los contracargos se cuentan como reembolsos
+	for _, p := range current.Payments {
+		stats.Spent += p.Captured
+		stats.Refunded += p.Refunded + p.ChargedBack
+	}
+
+	return stats
+}
+
+// newCustomerStats agrupa los aportes de las ordenes del cliente.
+// El valor promedio se calcula sobre las ordenes con pagos capturados.
+func newCustomerStats(userId string, orders []*OrderStats) *CustomerStats {
+	result := &CustomerStats{
+		UserId:            userId,
+		Orders:            len(orders),
+		ByStatus:          map[order.OrderStatus]int{},
+		FavouriteArticles: []*FavouriteArticle{},
+	}
+
+	paid := 0
+	articles := map[string]*FavouriteArticle{}
+	for _, o := range orders {
+		result.ByStatus[o.Status]++
+		result.TotalSpent += o.Spent
+		result.TotalRefunded += o.Refunded
+		if o.Spent > 0 {
+			paid++
+		}
+
+		if result.FirstOrder == nil || o.Created.Before(*result.FirstOrder) {
+			created := o.Created
+			result.FirstOrder = &created
+		}
+		if result.LastOrder == nil || o.Created.After(*result.LastOrder) {
+			created := o.Created
+			result.LastOrder = &created
+		}
+
+		for _, a := range o.Articles {
+			article, ok := articles[a.ArticleId]
+			if !ok {
+				article = &FavouriteArticle{ArticleId: a.ArticleId}
+				articles[a.ArticleId] = article
+				result.FavouriteArticles = append(result.FavouriteArticles, article)
+			}
+			article.Quantity += a.Quantity
+			article.Orders++
+		}
+	}
+
+	if paid > 0 {
+		result.AverageOrderValue = result.TotalSpent / float32(paid)
+	}
+
+	sort.SliceStable(result.FavouriteArticles, func(i, j int) bool {
+		a, b := result.FavouriteArticles[i], result.FavouriteArticles[j]
+		if a.Quantity != b.Quantity {
+			return a.Quantity > b.Quantity
+		}
+		if a.Orders != b.Orders {
+			return a.Orders > b.Orders
+		}
+		return a.ArticleId < b.ArticleId
+	})
+	if len(result.FavouriteArticles) > favouriteArticles {
+		result.FavouriteArticles = result.FavouriteArticles[:favouriteArticles]
+	}
+
+	return result
+}
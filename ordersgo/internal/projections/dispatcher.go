@@ -0,0 +1,216 @@
+package projections
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/metrics"
+)
+
+// Dispatcher actualiza las proyecciones en un pool acotado de workers.
+// Cada orden se asigna siempre al mismo worker, por lo que las actualizaciones de
+// una orden se ejecutan en serie y no compiten en el read-modify-upsert.
+type Dispatcher struct {
+	log     log.LogRusEntry
+	metrics *metrics.Metrics
+	update  func(orderId string, projections []string) error
+
+	queues  []chan string
+	workers sync.WaitGroup
+	senders sync.WaitGroup
+
+	mutex   sync.Mutex
+	pending map[string]*pendingUpdate
+	closed  bool
+
+	// late serializa las actualizaciones que llegan después de Drain
+	late sync.Mutex
+}
+
+// pendingUpdate orden en cola, waiters reciben el resultado de la actualización.
+// projections son las proyecciones a actualizar, nil para todas.
+type pendingUpdate struct {
+	lagId       int64
+	projections map[string]bool
+	waiters     []chan error
+}
+
+// merge agrega las proyecciones pedidas a la actualización pendiente
+func (p *pendingUpdate) merge(projections []string) {
+	if len(projections) == 0 {
+		p.projections = nil
+		return
+	}
+	if p.projections == nil {
+		return
+	}
+	for _, name := range projections {
+		p.projections[name] = true
+	}
+}
+
+func (p *pendingUpdate) names() []string {
+	if p.projections == nil {
+		return nil
+	}
+	names := make([]string, 0, len(p.projections))
+	for name := range p.projections {
+		names = append(names, name)
+	}
+	return names
+}
+
+// NewDispatcher inicia workers con una cola de queueSize órdenes cada uno.
+// update se ejecuta en el worker de la orden con las proyecciones a actualizar, nil para todas.
+func NewDispatcher(
+	log log.LogRusEntry,
+	metrics *metrics.Metrics,
+	workers int,
+	queueSize int,
+	update func(orderId string, projections []string) error,
+) *Dispatcher {
+	d := &Dispatcher{
+		log:     log,
+		metrics: metrics,
+		update:  update,
+		queues:  make([]chan string, max(workers, 1)),
+		pending: map[string]*pendingUpdate{},
+	}
+
+	for index := range d.queues {
+		d.queues[index] = make(chan string, max(queueSize, 1))
+		d.workers.Add(1)
+		go d.work(d.queues[index])
+	}
+
+	return d
+}
+
+// Enqueue agenda la actualización de las proyecciones de la orden, sin projections se actualizan todas.
+// Si la orden ya está en cola no se vuelve a agregar, la actualización pendiente va a leer todos
+// los eventos. Si la cola del worker está llena espera a que se libere lugar, frenando a quien
+// produce los eventos.
+func (d *Dispatcher) Enqueue(orderId string, projections ...string) {
+	d.enqueue(orderId, projections, nil)
+}
+
+// EnqueueWait agenda la actualización igual que Enqueue y espera a que termine.
+// Si la orden ya estaba en cola espera esa actualización, que todavía no leyó los eventos.
+func (d *Dispatcher) EnqueueWait(orderId string, projections ...string) error {
+	done := make(chan error, 1)
+	d.enqueue(orderId, projections, done)
+	return <-done
+}
+
+func (d *Dispatcher) enqueue(orderId string, projections []string, done chan error) {
+	d.mutex.Lock()
+	if current, ok := d.pending[orderId]; ok {
+		current.merge(projections)
+		if done != nil {
+			current.waiters = append(current.waiters, done)
+		}
+		d.mutex.Unlock()
+		d.metrics.ProjectionCoalesced.Inc()
+		return
+	}
+
+	if d.closed {
+		// Durante el apagado se actualiza en línea para no perder la actualización. Primero se
+		// espera que los workers terminen lo pendiente, así no compite con el worker de la orden.
+		d.mutex.Unlock()
+		d.workers.Wait()
+
+		d.late.Lock()
+		defer d.late.Unlock()
+
+		id := lag.start()
+		err := d.update(orderId, projections)
+		lag.done(id, err)
+		if done != nil {
+			done <- err
+		}
+		return
+	}
+
+	current := &pendingUpdate{lagId: lag.start()}
+	if len(projections) > 0 {
+		current.projections = map[string]bool{}
+		current.merge(projections)
+	}
+	if done != nil {
+		current.waiters = append(current.waiters, done)
+	}
+	d.pending[orderId] = current
+	d.senders.Add(1)
+	d.mutex.Unlock()
+	defer d.senders.Done()
+
+	d.metrics.ProjectionQueueDepth.Inc()
+	queue := d.queues[d.worker(orderId)]
+	select {
+	case queue <- orderId:
+	default:
+		d.metrics.ProjectionBackpressure.Inc()
+		queue <- orderId
+	}
+}
+
+// Drain deja de encolar y espera que los workers terminen lo pendiente
+func (d *Dispatcher) Drain(ctx context.Context) error {
+	d.mutex.Lock()
+	closed := d.closed
+	d.closed = true
+	d.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		// Las colas se cierran cuando no queda nadie enviando
+		if !closed {
+			d.senders.Wait()
+			for _, queue := range d.queues {
+				close(queue)
+			}
+		}
+		d.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) worker(orderId string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(orderId))
+	return int(hash.Sum32() % uint32(len(d.queues)))
+}
+
+func (d *Dispatcher) work(queue chan string) {
+	defer d.workers.Done()
+
+	for orderId := range queue {
+		d.metrics.ProjectionQueueDepth.Dec()
+
+		// Se saca de pendientes antes de actualizar, un evento que llegue durante
+		// la actualización vuelve a encolar la orden
+		d.mutex.Lock()
+		current := d.pending[orderId]
+		delete(d.pending, orderId)
+		d.mutex.Unlock()
+
+		err := d.update(orderId, current.names())
+		if err != nil {
+			d.log.Error(err)
+		}
+		lag.done(current.lagId, err)
+		for _, done := range current.waiters {
+			done <- err
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package projections
+
+import (
+	"sync"
+	"time"
+)
+
+// LagStatus estado de las actualizaciones de proyecciones pendientes
+type LagStatus struct {
+	Pending      int        `json:"pending"`
+	OldestMillis int64      `json:"oldestMillis"`
+	LastError    string     `json:"lastError,omitempty"`
+	LastUpdated  *time.Time `json:"lastUpdated,omitempty"`
+}
+
+var lag = &lagTracker{
+	pending: map[int64]time.Time{},
+}
+
+type lagTracker struct {
+	mutex       sync.Mutex
+	sequence    int64
+	pending     map[int64]time.Time
+	lastError   string
+	lastUpdated *time.Time
+}
+
+// Lag devuelve las actualizaciones pendientes y la antigüedad de la más vieja
+func Lag() LagStatus {
+	lag.mutex.Lock()
+	defer lag.mutex.Unlock()
+
+	status := LagStatus{
+		Pending:     len(lag.pending),
+		LastError:   lag.lastError,
+		LastUpdated: lag.lastUpdated,
+	}
+
+	for _, queued := range lag.pending {
+		if age := time.Since(queued).Milliseconds(); age > status.OldestMillis {
+			status.OldestMillis = age
+		}
+	}
+
+	return status
+}
+
+func (t *lagTracker) start() int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.sequence++
+	t.pending[t.sequence] = time.Now()
+	return t.sequence
+}
+
+func (t *lagTracker) done(id int64, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.pending, id)
+	now := time.Now()
+	t.lastUpdated = &now
+	if err != nil {
+		t.lastError = err.Error()
+	} else {
+		t.lastError = ""
+	}
+}
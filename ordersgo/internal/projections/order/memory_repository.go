@@ -0,0 +1,62 @@
+package order
+
+import (
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+)
+
+// NewMemoryOrderRepository proyección de ordenes en memoria, la tabla se comparte entre requests
+func NewMemoryOrderRepository(log log.LogRusEntry, table *memdb.Table[Order]) OrderRepository {
+	return &memoryOrderRepository{
+		log:   log,
+		table: table,
+	}
+}
+
+type memoryOrderRepository struct {
+	log   log.LogRusEntry
+	table *memdb.Table[Order]
+}
+
+func (r *memoryOrderRepository) Insert(order *Order) (*Order, error) {
+	if err := order.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	err := r.table.Upsert(order, func(current *Order) bool {
+		return current.OrderId == order.OrderId
+	})
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *memoryOrderRepository) FindByOrderId(orderId string) (*Order, error) {
+	return r.table.FindOne(func(order *Order) bool {
+		return order.OrderId == orderId
+	})
+}
+
+func (r *memoryOrderRepository) FindByUserId(userId string) ([]*Order, error) {
+	return r.table.Find(func(order *Order) bool {
+		return order.UserId == userId
+	})
+}
+
+func (r *memoryOrderRepository) CountByStatus() (map[OrderStatus]int64, error) {
+	orders, err := r.table.Find(nil)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	result := map[OrderStatus]int64{}
+	for _, order := range orders {
+		result[order.Status]++
+	}
+	return result, nil
+}
@@ -0,0 +1,14 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestMemoryOrder(t *testing.T) {
+	repotest.OrderRepository(t, order.NewMemoryOrderRepository(log.Get("", "test"), memdb.NewTable[order.Order]()))
+}
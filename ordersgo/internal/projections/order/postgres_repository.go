@@ -0,0 +1,199 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewPostgresOrderRepository proyección de ordenes sobre la tabla order_projection
+func NewPostgresOrderRepository(log log.LogRusEntry, db pgdb.DB) OrderRepository {
+	return &postgresOrderRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+type postgresOrderRepository struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+}
+
+const selectOrder = `SELECT id, order_id, status, user_id, cart_id, articles, payments, refunded, delivery, fulfillment, returns, created, updated FROM order_projection `
+
+// Insert crea o reemplaza la orden, conserva el id y la posición de la primera inserción
+func (r *postgresOrderRepository) Insert(order *Order) (*Order, error) {
+	if err := order.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	articles, err := json.Marshal(order.Articles)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	payments, err := json.Marshal(order.Payments)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	delivery, err := json.Marshal(order.Delivery)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	fulfillment, err := json.Marshal(order.Fulfillment)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	returns, err := json.Marshal(order.Returns)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	id := order.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	_, err = r.db.Exec(context.Background(), `
+		INSERT INTO order_projection
+			(order_id, id, status, user_id, cart_id, articles, payments, refunded, delivery, fulfillment, returns, created, updated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (order_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			user_id = EXCLUDED.user_id,
+			cart_id = EXCLUDED.cart_id,
+			articles = EXCLUDED.articles,
+			payments = EXCLUDED.payments,
+			refunded = EXCLUDED.refunded,
+			delivery = EXCLUDED.delivery,
+			fulfillment = EXCLUDED.fulfillment,
+			returns = EXCLUDED.returns,
+			created = EXCLUDED.created,
+			updated = EXCLUDED.updated`,
+		order.OrderId, id.Hex(), order.Status, order.UserId, order.CartId, articles, payments,
+		order.Refunded, delivery, fulfillment, returns, order.Created, order.Updated,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *postgresOrderRepository) FindByOrderId(orderId string) (*Order, error) {
+	rows, err := r.db.Query(context.Background(), selectOrder+"WHERE order_id = $1", orderId)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	order, err := pgx.CollectOneRow(rows, scanOrder)
+	if err == pgx.ErrNoRows {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *postgresOrderRepository) FindByUserId(userId string) ([]*Order, error) {
+	rows, err := r.db.Query(context.Background(), selectOrder+"WHERE user_id = $1 ORDER BY seq", userId)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	orders, err := pgx.CollectRows(rows, scanOrder)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (r *postgresOrderRepository) CountByStatus() (map[OrderStatus]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx, "SELECT status, COUNT(*) FROM order_projection GROUP BY status")
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[OrderStatus]int64{}
+	for rows.Next() {
+		var status OrderStatus
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			r.log.Error(err)
+			return nil, err
+		}
+		result[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func scanOrder(row pgx.CollectableRow) (*Order, error) {
+	var id string
+	var articles, payments, delivery, fulfillment, returns []byte
+	var created, updated time.Time
+	order := &Order{}
+
+	err := row.Scan(&id, &order.OrderId, &order.Status, &order.UserId, &order.CartId, &articles, &payments, &order.Refunded, &delivery, &fulfillment, &returns, &created, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(articles, &order.Articles); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(payments, &order.Payments); err != nil {
+		return nil, err
+	}
+	// Las filas proyectadas antes de las migraciones tienen las columnas en NULL
+	if delivery != nil {
+		if err := json.Unmarshal(delivery, &order.Delivery); err != nil {
+			return nil, err
+		}
+	}
+	if fulfillment != nil {
+		if err := json.Unmarshal(fulfillment, &order.Fulfillment); err != nil {
+			return nil, err
+		}
+	}
+	if returns != nil {
+		if err := json.Unmarshal(returns, &order.Returns); err != nil {
+			return nil, err
+		}
+	}
+
+	order.Created = created.UTC()
+	order.Updated = updated.UTC()
+	return order, nil
+}
@@ -0,0 +1,13 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestPostgresOrder(t *testing.T) {
+	repotest.OrderRepository(t, order.NewPostgresOrderRepository(log.Get("", "test"), repotest.Postgres(t)))
+}
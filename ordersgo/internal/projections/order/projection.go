@@ -0,0 +1,36 @@
+package order
+
+import (
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// Version de order_projection, se incrementa al cambiar cómo se proyecta para reconstruirla
+const Version = 7
+
+// NewOrderProjection registra order_projection en el registro de proyecciones
+func NewOrderProjection(service OrderService) *OrderProjection {
+	return &OrderProjection{
+		service: service,
+	}
+}
+
+type OrderProjection struct {
+	service OrderService
+}
+
+func (p *OrderProjection) Name() string {
+	return "order"
+}
+
+func (p *OrderProjection) Version() int {
+	return Version
+}
+
+func (p *OrderProjection) EventTypes() []events.EventType {
+	return []events.EventType{events.Place, events.Validation, events.Payment, events.Refund, events.Cancel, events.AddressSet, events.Fulfillment, events.Return}
+}
+
+func (p *OrderProjection) Apply(orderId string, ev []*events.Event) error {
+	_, err := p.service.Update(orderId, ev)
+	return err
+}
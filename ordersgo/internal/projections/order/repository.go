@@ -2,11 +2,13 @@ package order
 
 import (
 	"context"
+	"time"
 
 	"github.com/nmarsollier/commongo/db"
 	"github.com/nmarsollier/commongo/errs"
 	"github.com/nmarsollier/commongo/log"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -14,18 +16,22 @@ type OrderRepository interface {
 	Insert(order *Order) (*Order, error)
 	FindByOrderId(orderId string) (*Order, error)
 	FindByUserId(userId string) ([]*Order, error)
+	CountByStatus() (map[OrderStatus]int64, error)
 }
 
-func NewOrderRepository(log log.LogRusEntry, collection db.Collection) OrderRepository {
+// NewOrderRepository aggregate es la misma colección, se usa para las operaciones que db.Collection no soporta
+func NewOrderRepository(log log.LogRusEntry, collection db.Collection, aggregate *mongo.Collection) OrderRepository {
 	return &orderRepository{
 		log:        log,
 		collection: collection,
+		aggregate:  aggregate,
 	}
 }
 
 type orderRepository struct {
 	log        log.LogRusEntry
 	collection db.Collection
+	aggregate  *mongo.Collection
 }
 
 func (r *orderRepository) Insert(order *Order) (*Order, error) {
@@ -90,3 +96,34 @@ func (r *orderRepository) FindByUserId(userId string) ([]*Order, error) {
 
 	return orders, nil
 }
+
+// CountByStatus cuenta las ordenes agrupadas por estado
+func (r *orderRepository) CountByStatus() (map[OrderStatus]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+	cur, err := r.aggregate.Aggregate(ctx, pipeline)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	result := map[OrderStatus]int64{}
+	for cur.Next(ctx) {
+		row := struct {
+			Status OrderStatus `bson:"_id"`
+			Count  int64       `bson:"count"`
+		}{}
+		if err := cur.Decode(&row); err != nil {
+			r.log.Error(err)
+			return nil, err
+		}
+		result[row.Status] = row.Count
+	}
+
+	return result, nil
+}
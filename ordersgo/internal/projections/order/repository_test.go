@@ -0,0 +1,16 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+// TestMongoOrder corre contra MONGO_URL con las colecciones e índices del injector
+func TestMongoOrder(t *testing.T) {
+	deps := &di.Deps{CurrLog: log.Get("", "test"), CurrDatabase: repotest.Mongo(t)}
+	repotest.OrderRepository(t, order.NewOrderRepository(deps.Logger(), deps.OrdersCollection(), deps.Database().Collection("order_projection")))
+}
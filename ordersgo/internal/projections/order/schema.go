@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/nmarsollier/ordersgo/internal/events"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -17,6 +18,10 @@ const (
 	PartiallyPaid   OrderStatus = "partially_paid"
 	Paid            OrderStatus = "paid"
 	Canceled        OrderStatus = "canceled"
+	Packing         OrderStatus = "packing"
+	Shipped         OrderStatus = "shipped"
+	Delivered       OrderStatus = "delivered"
+	DeliveryFailed  OrderStatus = "delivery_failed"
 )
 
 // Estuctura basica de del evento
@@ -30,6 +35,11 @@ type Order struct {
 	Articles []*Article `bson:"articles"  json:"articles"`
 
 	Payments []*PaymentEvent `bson:"payments" json:"payments"`
+	Refunded float32         `bson:"refunded" json:"refunded"`
+
+	Delivery    *events.Delivery         `bson:"delivery,omitempty" json:"delivery,omitempty"`
+	Fulfillment *events.FulfillmentState `bson:"fulfillment,omitempty" json:"fulfillment,omitempty"`
+	Returns     []*events.ReturnState    `bson:"returns,omitempty" json:"returns,omitempty"`
 
 	Created time.Time `bson:"created" json:"created"`
 	Updated time.Time `bson:"updated" json:"updated"`
@@ -43,14 +53,75 @@ type Article struct {
 	IsValidated  bool    `json:"isValidated" `
 }
 
+// PaymentEvent estado actual del pago, resulta de aplicar sus transiciones en orden
 type PaymentEvent struct {
-	PaymentID     string `bson:"paymentId" json:"paymentId"`
-	Method        string `bson:"method" json:"method"`
-	Amount        float32 `bson:"amount" json:"amount"`
-	TransactionID string `bson:"transactionId" json:"transactionId"`
-	Status        string `bson:"status" json:"status"` // approved, rejected, refunded
-	ErrorMessage  string `bson:"errorMessage,omitempty" json:"errorMessage,omitempty"`
-	ErrorCode     string `bson:"errorCode,omitempty" json:"errorCode,omitempty"`
+	PaymentID     string               `bson:"paymentId" json:"paymentId"`
+	Method        string               `bson:"method" json:"method"`
+	Amount        float32              `bson:"amount" json:"amount"`
+	TransactionID string               `bson:"transactionId" json:"transactionId"`
+	Status        events.PaymentStatus `bson:"status" json:"status"`
+	Captured      float32              `bson:"captured" json:"captured"`
+	Refunded      float32              `bson:"refunded" json:"refunded"`
+	ChargedBack   float32              `bson:"chargedBack" json:"chargedBack"`
+	Remaining     float32              `bson:"remaining" json:"remaining"`
+	ErrorMessage  string               `bson:"errorMessage,omitempty" json:"errorMessage,omitempty"`
+	ErrorCode     string               `bson:"errorCode,omitempty" json:"errorCode,omitempty"`
+	Transitions   []*PaymentTransition `bson:"transitions" json:"transitions"`
+}
+
+// PaymentTransition cada reembolso es una transición con el monto reembolsado
+type PaymentTransition struct {
+	Status events.PaymentStatus `bson:"status" json:"status"`
+	Amount float32              `bson:"amount" json:"amount"`
+	Reason string               `bson:"reason,omitempty" json:"reason,omitempty"`
+	Time   time.Time            `bson:"time" json:"time"`
+}
+
+func (p *PaymentEvent) state() *events.PaymentState {
+	state := &events.PaymentState{
+		PaymentId:     p.PaymentID,
+		Method:        p.Method,
+		TransactionId: p.TransactionID,
+		Status:        p.Status,
+		Amount:        p.Amount,
+		Captured:      p.Captured,
+		Refunded:      p.Refunded,
+		ChargedBack:   p.ChargedBack,
+		ErrorMessage:  p.ErrorMessage,
+		ErrorCode:     p.ErrorCode,
+	}
+	for _, t := range p.Transitions {
+		state.Transitions = append(state.Transitions, &events.PaymentTransition{
+			Status: t.Status,
+			Amount: t.Amount,
+			Reason: t.Reason,
+			Time:   t.Time,
+		})
+	}
+	return state
+}
+
+func (p *PaymentEvent) update(state *events.PaymentState) {
+	p.PaymentID = state.PaymentId
+	p.Method = state.Method
+	p.Amount = state.Amount
+	p.TransactionID = state.TransactionId
+	p.Status = state.Status
+	p.Captured = state.Captured
+	p.Refunded = state.Refunded
+	p.ChargedBack = state.ChargedBack
+	p.Remaining = state.Remaining()
+	p.ErrorMessage = state.ErrorMessage
+	p.ErrorCode = state.ErrorCode
+	p.Transitions = make([]*PaymentTransition, len(state.Transitions))
+	for i, t := range state.Transitions {
+		p.Transitions[i] = &PaymentTransition{
+			Status: t.Status,
+			Amount: t.Amount,
+			Reason: t.Reason,
+			Time:   t.Time,
+		}
+	}
 }
 
 // ValidateSchema valida la estructura para ser insertada en la db
@@ -61,15 +132,116 @@ func (e *Order) ValidateSchema() error {
 func (e *Order) TotalPrice() float32 {
 	var result float32
 	for _, a := range e.Articles {
-		result += a.UnitaryPrice
+		result += a.UnitaryPrice * float32(a.Quantity)
 	}
 	return result
 }
 
+// CanChangeDelivery indica si todavía se pueden cambiar los datos de entrega, no se cambian
+// una vez despachada
+func (e *Order) CanChangeDelivery() bool {
+	switch e.Status {
+	case Invalid, Canceled, Shipped, Delivered, DeliveryFailed:
+		return false
+	}
+	return true
+}
+
+// IsShipped indica si la orden ya salió del depósito, en ese caso solo se puede devolver
+func (e *Order) IsShipped() bool {
+	switch e.Status {
+	case Shipped, Delivered, DeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// CanCancel indica si la orden se puede cancelar, las inválidas, canceladas o despachadas no.
+// Hasta el despacho se cancela en cualquier estado, incluso pagada o preparando el envío.
+func (e *Order) CanCancel() bool {
+	return e.Status != Invalid && e.Status != Canceled && !e.IsShipped()
+}
+
+// CanFulfill indica si la orden puede avanzar en el envío. Para empezar tiene que estar pagada,
+// después solo importa que no esté cancelada.
+func (e *Order) CanFulfill() bool {
+	if e.Status == Canceled {
+		return false
+	}
+	return e.Fulfillment != nil || e.Status == Paid
+}
+
+// Return devolución de la orden, nil si no existe
+func (e *Order) Return(returnId string) *events.ReturnState {
+	for _, r := range e.Returns {
+		if r.ReturnId == returnId {
+			return r
+		}
+	}
+	return nil
+}
+
+// Returnable cantidad del artículo que todavía se puede devolver, las devoluciones rechazadas no cuentan
+func (e *Order) Returnable(articleId string) int {
+	var result int
+	for _, a := range e.Articles {
+		if a.ArticleId == articleId {
+			result += a.Quantity
+		}
+	}
+	for _, r := range e.Returns {
+		if r.Status == events.ReturnRejected {
+			continue
+		}
+		for _, line := range r.Lines {
+			if line.ArticleId == articleId {
+				result -= line.Quantity
+			}
+		}
+	}
+	return result
+}
+
+// Article artículo de la orden, nil si no está en la orden
+func (e *Order) Article(articleId string) *Article {
+	for _, a := range e.Articles {
+		if a.ArticleId == articleId {
+			return a
+		}
+	}
+	return nil
+}
+
+// IsPayable indica si la orden acepta pagos
+func (e *Order) IsPayable() bool {
+	switch e.Status {
+	case Validated, Payment_Defined, PartiallyPaid:
+		return true
+	}
+	return false
+}
+
+// Balance lo que falta pagar. Los pagos pendientes, autorizados o informados a mano cuentan por su
+// monto, los capturados por lo que queda y los rechazados no cuentan.
+func (e *Order) Balance() float32 {
+	balance := e.TotalPrice()
+	for _, p := range e.Payments {
+		switch p.Status {
+		case "", events.PaymentPending, events.PaymentAuthorized:
+			balance -= p.Amount
+		case events.PaymentRejected:
+		default:
+			balance -= p.Remaining
+		}
+	}
+	return balance
+}
+
+// TotalPayment neto cobrado, lo capturado menos lo reembolsado y contracargado
 func (e *Order) TotalPayment() float32 {
 	var result float32
 	for _, p := range e.Payments {
-		result += p.Amount
+		result += p.Captured - p.Refunded - p.ChargedBack
 	}
 	return result
 }
@@ -9,6 +9,7 @@ type OrderService interface {
 	Update(orderId string, ev []*events.Event) (*Order, error)
 	FindByOrderId(orderId string) (*Order, error)
 	FindByUserId(userId string) ([]*Order, error)
+	CountByStatus() (map[OrderStatus]int64, error)
 }
 
 func NewOrderService(log log.LogRusEntry, repository OrderRepository) OrderService {
@@ -23,16 +24,12 @@ type orderService struct {
 	repository OrderRepository
 }
 
+// Update los eventos son todos los de la orden, se proyecta desde cero para no aplicar
+// dos veces las transiciones de pago
 func (s *orderService) Update(orderId string, ev []*events.Event) (*Order, error) {
-	order, _ := s.repository.FindByOrderId(orderId)
-	if order == nil {
-		order = &Order{
-			OrderId: orderId,
-		}
-	}
-
-	for _, e := range ev {
-		order = s.update(order, e)
+	order := Project(orderId, ev)
+	if current, _ := s.repository.FindByOrderId(orderId); current != nil {
+		order.ID = current.ID
 	}
 
 	if _, err := s.repository.Insert(order); err != nil {
@@ -42,16 +39,40 @@ func (s *orderService) Update(orderId string, ev []*events.Event) (*Order, error
 	return order, nil
 }
 
+// Project arma la orden desde cero con sus eventos, sin leer ni guardar la proyección
+func Project(orderId string, ev []*events.Event) *Order {
+	s := &orderService{}
+	order := &Order{
+		OrderId: orderId,
+	}
+
+	for _, e := range ev {
+		order = s.update(order, e)
+	}
+	return order
+}
+
 func (s *orderService) update(order *Order, event *events.Event) *Order {
 	switch event.Type {
 	case events.Place:
 		order = s.updatePlace(order, event)
 	case events.Validation:
 		order = s.updateValidation(order, event)
-	case events.Payment:
+	case events.Payment, events.Refund:
 		order = s.updatePayment(order, event)
 	case events.Cancel:
 		order = s.updateCancel(order, event)
+	case events.AddressSet:
+		order = s.updateAddressSet(order, event)
+	case events.Fulfillment:
+		order = s.updateFulfillment(order, event)
+	case events.Return:
+		order = s.updateReturn(order, event)
+	}
+
+	// Una vez empezado el envío el estado de la orden es el del envío, salvo que se cancele
+	if order.Fulfillment != nil && order.Status != Canceled {
+		order.Status = OrderStatus(order.Fulfillment.Status)
 	}
 	return order
 }
@@ -73,6 +94,50 @@ func (s *orderService) updatePlace(o *Order, e *events.Event) *Order {
 	}
 
 	o.Articles = articles
+	o.Delivery = e.PlaceEvent.Delivery
+	return o
+}
+
+func (s *orderService) updateAddressSet(o *Order, e *events.Event) *Order {
+	current := o.Delivery
+	if current == nil {
+		current = &events.Delivery{}
+	}
+
+	o.Delivery = current.Merge(&e.AddressSet.Delivery)
+	o.Updated = e.Updated
+	return o
+}
+
+// updateFulfillment aplica la transición del envío, las transiciones inválidas se ignoran
+func (s *orderService) updateFulfillment(o *Order, e *events.Event) *Order {
+	current := o.Fulfillment
+	if current == nil {
+		current = &events.FulfillmentState{}
+	}
+	if !current.Apply(e) {
+		return o
+	}
+
+	o.Fulfillment = current
+	o.Updated = e.Updated
+	return o
+}
+
+// updateReturn aplica la transición a la devolución, las transiciones inválidas se ignoran
+func (s *orderService) updateReturn(o *Order, e *events.Event) *Order {
+	current := o.Return(e.Return.ReturnId)
+	if current == nil {
+		current = &events.ReturnState{}
+		if !current.Apply(e) {
+			return o
+		}
+		o.Returns = append(o.Returns, current)
+	} else if !current.Apply(e) {
+		return o
+	}
+
+	o.Updated = e.Updated
 	return o
 }
 
@@ -99,48 +164,45 @@ func (s *orderService) updateValidation(o *Order, e *events.Event) *Order {
 	return o
 }
 
+// updatePayment aplica la transición o el reembolso al pago, las transiciones inválidas se ignoran.
+// La orden está pagada cuando el neto cobrado cubre el total.
 func (s *orderService) updatePayment(o *Order, e *events.Event) *Order {
-	// Verificar si el pago ya existe (por paymentId) para evitar duplicados
-	paymentExists := false
+	paymentId := e.PaymentId()
+
+	var payment *PaymentEvent
 	for _, existingPayment := range o.Payments {
-		if existingPayment.PaymentID == e.Payment.PaymentId {
-			paymentExists = true
-			// Actualizar el pago existente en caso de que el status haya cambiado
-			existingPayment.Status = e.Payment.Status
-			existingPayment.ErrorMessage = e.Payment.ErrorMessage
-			existingPayment.ErrorCode = e.Payment.ErrorCode
+		if paymentId != "" && existingPayment.PaymentID == paymentId {
+			payment = existingPayment
 			break
 		}
 	}
 
-	// Solo agregar si no existe
-	if !paymentExists {
-		o.Payments = append(o.Payments, &PaymentEvent{
-			PaymentID:     e.Payment.PaymentId,
-			Method:        e.Payment.Method,
-			Amount:        e.Payment.Amount,
-			TransactionID: e.Payment.TransactionId,
-			Status:        e.Payment.Status,
-			ErrorMessage:  e.Payment.ErrorMessage,
-			ErrorCode:     e.Payment.ErrorCode,
-		})
+	state := &events.PaymentState{PaymentId: paymentId}
+	if payment != nil {
+		state = payment.state()
+	}
+	if !state.Apply(e) {
+		return o
+	}
+
+	if payment == nil {
+		payment = &PaymentEvent{}
+		o.Payments = append(o.Payments, payment)
 	}
+	payment.update(state)
 
-	// Calcular total de pagos aprobados
-	var totalApproved float32
+	o.Refunded = 0
 	for _, p := range o.Payments {
-		if p.Status == "approved" {
-			totalApproved += p.Amount
-		}
+		o.Refunded += p.Refunded
 	}
 
-	// Actualizar estado según pagos
 	// IMPORTANTE: No modificar el estado si la orden ya fue cancelada
 	if o.Status != Canceled {
+		totalPaid := o.TotalPayment()
 		totalPrice := o.TotalPrice()
-		if totalApproved >= totalPrice && totalPrice > 0 {
+		if totalPaid >= totalPrice && totalPrice > 0 {
 			o.Status = Paid
-		} else if totalApproved > 0 {
+		} else if totalPaid > 0 {
 			o.Status = PartiallyPaid
 		} else if o.Status == Paid || o.Status == PartiallyPaid {
 			// Si había pagos pero se reembolsaron todos
@@ -167,3 +229,7 @@ func (s *orderService) FindByOrderId(orderId string) (*Order, error) {
 func (s *orderService) FindByUserId(userId string) ([]*Order, error) {
 	return s.repository.FindByUserId(userId)
 }
+
+func (s *orderService) CountByStatus() (map[OrderStatus]int64, error) {
+	return s.repository.CountByStatus()
+}
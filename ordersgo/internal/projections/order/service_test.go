@@ -0,0 +1,180 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+)
+
+// validOrder orden colocada con un artículo de 50 x 2 ya validado, total 100
+func validOrder() []*events.Event {
+	return []*events.Event{
+		{
+			OrderId: "order",
+			Type:    events.Place,
+			PlaceEvent: &events.PlaceEvent{
+				CartId:   "cart",
+				UserId:   "user",
+				Articles: []events.Article{{ArticleId: "article", Quantity: 2}},
+			},
+		},
+		{
+			OrderId: "order",
+			Type:    events.Validation,
+			Validation: &events.ValidationEvent{
+				ArticleId:   "article",
+				ReferenceId: "order",
+				IsValid:     true,
+				Price:       50,
+			},
+		},
+	}
+}
+
+func payment(paymentId string, status events.PaymentStatus, amount float32) *events.Event {
+	return &events.Event{
+		OrderId: "order",
+		Type:    events.Payment,
+		Payment: &events.PaymentEvent{
+			OrderId:   "order",
+			Method:    string(events.Cash),
+			Amount:    amount,
+			PaymentId: paymentId,
+			Status:    status,
+		},
+	}
+}
+
+func refund(paymentId string, amount float32) *events.Event {
+	return &events.Event{
+		OrderId: "order",
+		Type:    events.Refund,
+		Refund: &events.RefundEvent{
+			OrderId:   "order",
+			PaymentId: paymentId,
+			Amount:    amount,
+		},
+	}
+}
+
+func TestProjectPayments(t *testing.T) {
+	tests := []struct {
+		name         string
+		payments     []*events.Event
+		status       order.OrderStatus
+		totalPayment float32
+		balance      float32
+		refunded     float32
+	}{
+		{
+			name:    "validated without payments",
+			status:  order.Validated,
+			balance: 100,
+		},
+		{
+			name:     "manual payment is not paid",
+			payments: []*events.Event{payment("a", "", 100)},
+			status:   order.Validated,
+			balance:  0,
+		},
+		{
+			name:     "authorized counts for the balance",
+			payments: []*events.Event{payment("a", events.PaymentAuthorized, 30)},
+			status:   order.Validated,
+			balance:  70,
+		},
+		{
+			name:         "partially paid",
+			payments:     []*events.Event{payment("a", events.PaymentCaptured, 40)},
+			status:       order.PartiallyPaid,
+			totalPayment: 40,
+			balance:      60,
+		},
+		{
+			name: "paid with two payments",
+			payments: []*events.Event{
+				payment("a", events.PaymentCaptured, 40),
+				payment("b", events.PaymentApproved, 60),
+			},
+			status:       order.Paid,
+			totalPayment: 100,
+			balance:      0,
+		},
+		{
+			name: "rejected does not count",
+			payments: []*events.Event{
+				payment("a", events.PaymentAuthorized, 100),
+				payment("a", events.PaymentRejected, 0),
+			},
+			status:  order.Validated,
+			balance: 100,
+		},
+		{
+			name: "partial refund leaves it partially paid",
+			payments: []*events.Event{
+				payment("a", events.PaymentCaptured, 100),
+				refund("a", 30),
+			},
+			status:       order.PartiallyPaid,
+			totalPayment: 70,
+			balance:      30,
+			refunded:     30,
+		},
+		{
+			name: "full refund leaves the payment defined",
+			payments: []*events.Event{
+				payment("a", events.PaymentCaptured, 100),
+				payment("a", events.PaymentRefunded, 0),
+			},
+			status:   order.Payment_Defined,
+			balance:  100,
+			refunded: 100,
+		},
+		{
+			name: "invalid transition is ignored",
+			payments: []*events.Event{
+				payment("a", events.PaymentCaptured, 100),
+				payment("a", events.PaymentAuthorized, 10),
+			},
+			status:       order.Paid,
+			totalPayment: 100,
+			balance:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projected := order.Project("order", append(validOrder(), tt.payments...))
+
+			if projected.Status != tt.status {
+				t.Errorf("status = %q, want %q", projected.Status, tt.status)
+			}
+			if got := projected.TotalPayment(); got != tt.totalPayment {
+				t.Errorf("totalPayment = %v, want %v", got, tt.totalPayment)
+			}
+			if got := projected.Balance(); got != tt.balance {
+				t.Errorf("balance = %v, want %v", got, tt.balance)
+			}
+			if projected.Refunded != tt.refunded {
+				t.Errorf("refunded = %v, want %v", projected.Refunded, tt.refunded)
+			}
+		})
+	}
+}
+
+func TestProjectCanceledKeepsStatus(t *testing.T) {
+	ev := append(validOrder(), &events.Event{
+		OrderId:     "order",
+		Type:        events.Cancel,
+		CancelEvent: &events.CancelEvent{},
+	}, payment("a", events.PaymentCaptured, 100))
+
+	projected := order.Project("order", ev)
+	if projected.Status != order.Canceled {
+		t.Errorf("status = %q, want %q", projected.Status, order.Canceled)
+	}
+	if got := projected.TotalPayment(); got != 100 {
+		t.Errorf("totalPayment = %v, want 100", got)
+	}
+}
@@ -0,0 +1,73 @@
+package projections
+
+import (
+	"slices"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// Projection read model que se construye con los eventos de una orden.
+// Para agregar un read model se implementa Projection y se registra en di.ProjectionRegistry.
+type Projection interface {
+	// Name identifica la proyección en métricas, versiones y rebuilds
+	Name() string
+	// Version se incrementa al cambiar cómo se proyecta, una versión distinta a la construida requiere rebuild
+	Version() int
+	// EventTypes eventos que modifican la proyección, las órdenes sin estos eventos no se proyectan
+	EventTypes() []events.EventType
+	// Apply proyecta la orden con todos sus eventos, en el orden en que se guardaron
+	Apply(orderId string, events []*events.Event) error
+}
+
+// Registry proyecciones registradas, se aplican en el orden en que se registran
+type Registry struct {
+	projections []Projection
+}
+
+func NewRegistry(projections ...Projection) *Registry {
+	return &Registry{
+		projections: projections,
+	}
+}
+
+// All devuelve las proyecciones registradas
+func (r *Registry) All() []Projection {
+	return r.projections
+}
+
+// Get busca la proyección por nombre, o errs.NotFound
+func (r *Registry) Get(name string) (Projection, error) {
+	for _, projection := range r.projections {
+		if projection.Name() == name {
+			return projection, nil
+		}
+	}
+	return nil, errs.NotFound
+}
+
+// Select devuelve las proyecciones con esos nombres, sin nombres devuelve todas
+func (r *Registry) Select(names []string) []Projection {
+	if len(names) == 0 {
+		return r.projections
+	}
+
+	result := []Projection{}
+	for _, projection := range r.projections {
+		if slices.Contains(names, projection.Name()) {
+			result = append(result, projection)
+		}
+	}
+	return result
+}
+
+// interested indica si alguno de los eventos modifica la proyección
+func interested(projection Projection, ev []*events.Event) bool {
+	types := projection.EventTypes()
+	for _, e := range ev {
+		if slices.Contains(types, e.Type) {
+			return true
+		}
+	}
+	return false
+}
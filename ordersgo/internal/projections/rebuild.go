@@ -0,0 +1,164 @@
+package projections
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
+	"github.com/nmarsollier/ordersgo/internal/projections/version"
+)
+
+// Órdenes que se reconstruyen en paralelo
+const rebuildConcurrency = 16
+
+// ProjectionVersion versión del código y versión construida de una proyección
+type ProjectionVersion struct {
+	Name         string     `json:"name"`
+	Version      int        `json:"version"`
+	BuiltVersion int        `json:"builtVersion"`
+	Stale        bool       `json:"stale"`
+	Rebuilding   bool       `json:"rebuilding"`
+	Updated      *time.Time `json:"updated,omitempty"`
+}
+
+// Rebuilds en curso en este proceso
+var rebuilding = struct {
+	mutex sync.Mutex
+	names map[string]bool
+}{names: map[string]bool{}}
+
+// Versions devuelve el estado de las proyecciones registradas. Una proyección que nunca se
+// reconstruyó está construida con la versión 1, la anterior a versionar las proyecciones.
+func (s *projectionsService) Versions() ([]*ProjectionVersion, error) {
+	result := []*ProjectionVersion{}
+	for _, projection := range s.registry.All() {
+		built, err := s.versions.FindByProjection(projection.Name())
+		if err == errs.NotFound {
+			built, err = &version.Version{Projection: projection.Name(), Version: 1}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rebuilding.mutex.Lock()
+		current := rebuilding.names[projection.Name()]
+		rebuilding.mutex.Unlock()
+
+		result = append(result, &ProjectionVersion{
+			Name:         projection.Name(),
+			Version:      projection.Version(),
+			BuiltVersion: built.Version,
+			Stale:        built.Version != projection.Version(),
+			Rebuilding:   current,
+			Updated:      updated(built),
+		})
+	}
+	return result, nil
+}
+
+// Rebuild vuelve a proyectar en background los eventos de todas las órdenes con la versión actual.
+// Cada orden se reemplaza en su lugar, mientras dura el rebuild las lecturas ven la versión anterior
+// o la nueva de cada orden, nunca la proyección vacía. Las órdenes pasan por el dispatcher y no
+// compiten con las actualizaciones de los eventos que llegan durante el rebuild. Solo se actualiza
+// esta proyección, el resto no se modifica. Devuelve error si ya hay un rebuild en curso.
+func (s *projectionsService) Rebuild(name string) error {
+	projection, err := s.registry.Get(name)
+	if err != nil {
+		return err
+	}
+
+	rebuilding.mutex.Lock()
+	if rebuilding.names[name] {
+		rebuilding.mutex.Unlock()
+		return errs.NewValidation().Add("name", "rebuild in progress")
+	}
+	rebuilding.names[name] = true
+	rebuilding.mutex.Unlock()
+
+	lifecycle.Go(func() {
+		defer func() {
+			rebuilding.mutex.Lock()
+			delete(rebuilding.names, name)
+			rebuilding.mutex.Unlock()
+		}()
+
+		if err := s.rebuild(projection); err != nil {
+			s.log.Error("Projection rebuild failed: ", err)
+		}
+	})
+
+	return nil
+}
+
+func (s *projectionsService) rebuild(projection Projection) error {
+	orderIds, err := s.events.FindOrderIds()
+	if err != nil {
+		return err
+	}
+
+	s.log.Info("Rebuilding projection ", projection.Name(), ", orders: ", len(orderIds))
+	if err := s.rebuildOrders(projection.Name(), orderIds); err != nil {
+		return err
+	}
+
+	if _, err := s.saveVersion(projection); err != nil {
+		return err
+	}
+
+	s.log.Info("Projection rebuilt: ", projection.Name())
+	return nil
+}
+
+// rebuildOrders si falla alguna orden continúa con el resto, la versión no se actualiza
+// y la proyección queda desactualizada hasta un nuevo rebuild
+func (s *projectionsService) rebuildOrders(name string, orderIds []string) error {
+	var mutex sync.Mutex
+	var failed error
+
+	pending := make(chan string)
+	workers := sync.WaitGroup{}
+	for range rebuildConcurrency {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for orderId := range pending {
+				if err := s.dispatcher.EnqueueWait(orderId, name); err != nil {
+					mutex.Lock()
+					failed = err
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, orderId := range orderIds {
+		if lifecycle.IsStopping() {
+			mutex.Lock()
+			failed = errors.New("rebuild interrupted by shutdown")
+			mutex.Unlock()
+			break
+		}
+		pending <- orderId
+	}
+	close(pending)
+	workers.Wait()
+
+	return failed
+}
+
+func updated(built *version.Version) *time.Time {
+	if built.Updated.IsZero() {
+		return nil
+	}
+	return &built.Updated
+}
+
+func (s *projectionsService) saveVersion(projection Projection) (*version.Version, error) {
+	return s.versions.Save(&version.Version{
+		Projection: projection.Name(),
+		Version:    projection.Version(),
+		Updated:    time.Now(),
+	})
+}
@@ -0,0 +1,124 @@
+package projections
+
+import (
+	"context"
+	"time"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
+	"github.com/nmarsollier/ordersgo/internal/metrics"
+	"github.com/nmarsollier/ordersgo/internal/projections/checkpoint"
+)
+
+// Runner sigue el event store y actualiza las proyecciones de cada evento nuevo, incluidos
+// los que guardan otras herramientas o réplicas. El checkpoint avanza recién cuando la
+// proyección del evento quedó aplicada, al reiniciar continúa desde el último evento aplicado.
+type Runner struct {
+	log         log.LogRusEntry
+	metrics     *metrics.Metrics
+	name        string
+	feed        events.EventFeed
+	checkpoints checkpoint.CheckpointRepository
+	apply       func(orderId string) error
+}
+
+// NewRunner name identifica el checkpoint, apply actualiza las proyecciones de la orden y espera que terminen
+func NewRunner(
+	log log.LogRusEntry,
+	metrics *metrics.Metrics,
+	name string,
+	feed events.EventFeed,
+	checkpoints checkpoint.CheckpointRepository,
+	apply func(orderId string) error,
+) *Runner {
+	return &Runner{
+		log:         log,
+		metrics:     metrics,
+		name:        name,
+		feed:        feed,
+		checkpoints: checkpoints,
+		apply:       apply,
+	}
+}
+
+// Start sigue el event store hasta el apagado, ante errores reintenta desde el último checkpoint
+func (r *Runner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	// Al apagar se termina de aplicar el evento en curso, antes de esperar las proyecciones pendientes
+	lifecycle.OnShutdown(lifecycle.Consumers, "projection runner", func(shutdownCtx context.Context) error {
+		cancel()
+		select {
+		case <-stopped:
+			return nil
+		case <-shutdownCtx.Done():
+			return shutdownCtx.Err()
+		}
+	})
+
+	defer close(stopped)
+
+	r.log.Info("Projection runner started: ", r.name)
+	for {
+		if err := r.tail(ctx); err != nil {
+			r.log.Error(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			r.log.Info("Projection runner stopped: ", r.name)
+			return
+		case <-time.After(5 * time.Second):
+			r.log.Info("Projection runner restarting: ", r.name)
+		}
+	}
+}
+
+func (r *Runner) tail(ctx context.Context) error {
+	position, err := r.position(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.feed.Tail(ctx, position, func(event *events.Event, position string) error {
+		if err := r.apply(event.OrderId); err != nil {
+			return err
+		}
+		r.metrics.ProjectionRunnerEvents.Inc()
+
+		_, err := r.checkpoints.Save(&checkpoint.Checkpoint{
+			Name:     r.name,
+			Position: position,
+			Updated:  time.Now(),
+		})
+		return err
+	})
+}
+
+// position la primera vez arranca desde el evento actual, los anteriores los proyectó quien los escribió
+func (r *Runner) position(ctx context.Context) (string, error) {
+	current, err := r.checkpoints.FindByName(r.name)
+	if err == nil {
+		return current.Position, nil
+	}
+	if err != errs.NotFound {
+		return "", err
+	}
+
+	position, err := r.feed.Head(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := r.checkpoints.Save(&checkpoint.Checkpoint{
+		Name:     r.name,
+		Position: position,
+		Updated:  time.Now(),
+	}); err != nil {
+		return "", err
+	}
+	return position, nil
+}
@@ -0,0 +1,61 @@
+package sales
+
+import (
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+)
+
+// newOrderSales toma los hitos de la línea de tiempo de la orden y agrega cada pago capturado.
+// Un pago capturado y luego reembolsado suma al ingreso bruto cuando se captura y al reembolso cuando
+// se reembolsa, los contracargos se cuentan como reembolsos.
+func newOrderSales(orderId string, ev []*events.Event) *OrderSales {
+	sales := &OrderSales{
+		OrderId: orderId,
+		Facts:   []*Fact{},
+	}
+
+	for _, m := range status.Project(orderId, ev).Milestones {
+		switch m.Type {
+		case status.Placed:
+			sales.add(&Fact{Type: Placed, Time: m.Time})
+		case status.Validated:
+			sales.add(&Fact{Type: Validated, Time: m.Time})
+		case status.FullyPaid:
+			sales.add(&Fact{Type: Paid, Time: m.Time})
+		case status.Canceled:
+			sales.add(&Fact{Type: Canceled, Time: m.Time})
+		case status.Refunded, status.Chargeback:
+			sales.add(&Fact{Type: Refund, Time: m.Time, Amount: m.Amount})
+		}
+	}
+
+	for _, e := range ev {
+		if e.Type == events.Place {
+			sales.Items = 0
+			for _, a := range e.PlaceEvent.Articles {
+				sales.Items += a.Quantity
+			}
+		}
+		sales.Updated = e.Updated
+	}
+
+	for _, payment := range events.FoldPayments(ev) {
+		for _, t := range payment.Transitions {
+			if t.Status == events.PaymentCaptured {
+				sales.add(&Fact{Type: Payment, Time: t.Time, Amount: t.Amount})
+			}
+		}
+	}
+
+	return sales
+}
+
+func (s *OrderSales) add(fact *Fact) {
+	if len(s.Facts) == 0 || fact.Time.Before(s.First) {
+		s.First = fact.Time
+	}
+	if len(s.Facts) == 0 || fact.Time.After(s.Last) {
+		s.Last = fact.Time
+	}
+	s.Facts = append(s.Facts, fact)
+}
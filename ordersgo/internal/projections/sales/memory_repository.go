@@ -0,0 +1,44 @@
+package sales
+
+import (
+	"time"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+)
+
+// NewMemorySalesRepository hechos de venta en memoria, la tabla se comparte entre requests
+func NewMemorySalesRepository(log log.LogRusEntry, table *memdb.Table[OrderSales]) SalesRepository {
+	return &memorySalesRepository{
+		log:   log,
+		table: table,
+	}
+}
+
+type memorySalesRepository struct {
+	log   log.LogRusEntry
+	table *memdb.Table[OrderSales]
+}
+
+func (r *memorySalesRepository) Insert(sales *OrderSales) (*OrderSales, error) {
+	if err := sales.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	err := r.table.Upsert(sales, func(current *OrderSales) bool {
+		return current.OrderId == sales.OrderId
+	})
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return sales, nil
+}
+
+func (r *memorySalesRepository) FindBetween(from time.Time, to time.Time) ([]*OrderSales, error) {
+	return r.table.Find(func(sales *OrderSales) bool {
+		return sales.First.Before(to) && !sales.Last.Before(from)
+	})
+}
@@ -0,0 +1,14 @@
+package sales_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/projections/sales"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestMemorySales(t *testing.T) {
+	repotest.SalesRepository(t, sales.NewMemorySalesRepository(log.Get("", "test"), memdb.NewTable[sales.OrderSales]()))
+}
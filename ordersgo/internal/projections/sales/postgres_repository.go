@@ -0,0 +1,105 @@
+package sales
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewPostgresSalesRepository hechos de venta sobre la tabla sales_projection
+func NewPostgresSalesRepository(log log.LogRusEntry, db pgdb.DB) SalesRepository {
+	return &postgresSalesRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+type postgresSalesRepository struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+}
+
+// Insert crea o reemplaza los hechos de la orden, conserva el id de la primera inserción
+func (r *postgresSalesRepository) Insert(sales *OrderSales) (*OrderSales, error) {
+	if err := sales.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	facts, err := json.Marshal(sales.Facts)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	id := sales.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	_, err = r.db.Exec(context.Background(), `
+		INSERT INTO sales_projection (order_id, id, items, facts, first, last, updated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (order_id) DO UPDATE SET
+			items = EXCLUDED.items,
+			facts = EXCLUDED.facts,
+			first = EXCLUDED.first,
+			last = EXCLUDED.last,
+			updated = EXCLUDED.updated`,
+		sales.OrderId, id.Hex(), sales.Items, facts, sales.First, sales.Last, sales.Updated,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return sales, nil
+}
+
+func (r *postgresSalesRepository) FindBetween(from time.Time, to time.Time) ([]*OrderSales, error) {
+	rows, err := r.db.Query(context.Background(), `
+		SELECT id, order_id, items, facts, first, last, updated
+		FROM sales_projection WHERE first < $2 AND last >= $1`, from, to,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	result, err := pgx.CollectRows(rows, scanSales)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func scanSales(row pgx.CollectableRow) (*OrderSales, error) {
+	var id string
+	var facts []byte
+	var first, last, updated time.Time
+	sales := &OrderSales{}
+
+	err := row.Scan(&id, &sales.OrderId, &sales.Items, &facts, &first, &last, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	if sales.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(facts, &sales.Facts); err != nil {
+		return nil, err
+	}
+
+	sales.First = first.UTC()
+	sales.Last = last.UTC()
+	sales.Updated = updated.UTC()
+	return sales, nil
+}
@@ -0,0 +1,13 @@
+package sales_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/projections/sales"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestPostgresSales(t *testing.T) {
+	repotest.SalesRepository(t, sales.NewPostgresSalesRepository(log.Get("", "test"), repotest.Postgres(t)))
+}
@@ -0,0 +1,35 @@
+package sales
+
+import (
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// Version de sales_projection, se incrementa al cambiar cómo se proyecta para reconstruirla
+const Version = 2
+
+// NewSalesProjection registra sales_projection en el registro de proyecciones
+func NewSalesProjection(service SalesService) *SalesProjection {
+	return &SalesProjection{
+		service: service,
+	}
+}
+
+type SalesProjection struct {
+	service SalesService
+}
+
+func (p *SalesProjection) Name() string {
+	return "sales"
+}
+
+func (p *SalesProjection) Version() int {
+	return Version
+}
+
+func (p *SalesProjection) EventTypes() []events.EventType {
+	return []events.EventType{events.Place, events.Validation, events.Payment, events.Refund, events.Cancel}
+}
+
+func (p *SalesProjection) Apply(orderId string, ev []*events.Event) error {
+	return p.service.Update(orderId, ev)
+}
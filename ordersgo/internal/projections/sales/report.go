@@ -0,0 +1,145 @@
+package sales
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nmarsollier/commongo/errs"
+)
+
+// maxBuckets evita reportes con demasiados periodos, por ejemplo un año agrupado por hora
+const maxBuckets = 2000
+
+// ParseGranularity vacío agrupa por día
+func ParseGranularity(value string) (Granularity, error) {
+	switch Granularity(value) {
+	case "":
+		return Day, nil
+	case Hour, Day, Month:
+		return Granularity(value), nil
+	}
+	return "", errs.NewValidation().Add("granularity", "must be hour, day or month")
+}
+
+// truncate inicio del periodo que contiene t
+func (g Granularity) truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch g {
+	case Hour:
+		return t.Truncate(time.Hour)
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (g Granularity) next(t time.Time) time.Time {
+	switch g {
+	case Hour:
+		return t.Add(time.Hour)
+	case Month:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// validateRange el rango no puede estar vacío ni tener más de maxBuckets periodos
+func validateRange(from time.Time, to time.Time, granularity Granularity) error {
+	if !from.Before(to) {
+		return errs.NewValidation().Add("from", "must be before to")
+	}
+
+	count := 0
+	for start := granularity.truncate(from); start.Before(to); start = granularity.next(start) {
+		if count++; count > maxBuckets {
+			return errs.NewValidation().Add("granularity", fmt.Sprintf("more than %d periods, use a shorter range", maxBuckets))
+		}
+	}
+	return nil
+}
+
+// newReport arma todos los periodos del rango, también los que no tienen ventas
+func newReport(from time.Time, to time.Time, granularity Granularity, orders []*OrderSales) *Report {
+	report := &Report{
+		From:        from.UTC(),
+		To:          to.UTC(),
+		Granularity: granularity,
+		Buckets:     []*Bucket{},
+	}
+
+	index := map[time.Time]*Bucket{}
+	for start := granularity.truncate(from); start.Before(to); start = granularity.next(start) {
+		bucket := &Bucket{Start: start}
+		report.Buckets = append(report.Buckets, bucket)
+		index[start] = bucket
+	}
+
+	items := map[*Bucket]int{}
+	for _, o := range orders {
+		for _, fact := range o.Facts {
+			if fact.Time.Before(from) || !fact.Time.Before(to) {
+				continue
+			}
+
+			bucket := index[granularity.truncate(fact.Time)]
+			switch fact.Type {
+			case Placed:
+				bucket.Placed++
+				items[bucket] += o.Items
+			case Validated:
+				bucket.Validated++
+			case Paid:
+				bucket.Paid++
+			case Canceled:
+				bucket.Canceled++
+			case Payment:
+				bucket.GrossRevenue += fact.Amount
+			case Refund:
+				bucket.Refunds += fact.Amount
+			}
+		}
+	}
+
+	for _, bucket := range report.Buckets {
+		bucket.NetRevenue = bucket.GrossRevenue - bucket.Refunds
+		if bucket.Placed > 0 {
+			bucket.AverageBasketSize = float32(items[bucket]) / float32(bucket.Placed)
+		}
+	}
+
+	return report
+}
+
+// WriteCSV un periodo por fila, con encabezado
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{
+		"start", "placed", "validated", "paid", "canceled",
+		"grossRevenue", "refunds", "netRevenue", "averageBasketSize",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, b := range r.Buckets {
+		err := writer.Write([]string{
+			b.Start.Format(time.RFC3339),
+			fmt.Sprint(b.Placed),
+			fmt.Sprint(b.Validated),
+			fmt.Sprint(b.Paid),
+			fmt.Sprint(b.Canceled),
+			fmt.Sprintf("%.2f", b.GrossRevenue),
+			fmt.Sprintf("%.2f", b.Refunds),
+			fmt.Sprintf("%.2f", b.NetRevenue),
+			fmt.Sprintf("%.2f", b.AverageBasketSize),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
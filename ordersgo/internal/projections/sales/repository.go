@@ -0,0 +1,78 @@
+package sales
+
+import (
+	"context"
+	"time"
+
+	"github.com/nmarsollier/commongo/db"
+	"github.com/nmarsollier/commongo/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SalesRepository interface {
+	Insert(sales *OrderSales) (*OrderSales, error)
+	// FindBetween ordenes con algún hecho entre from (incluido) y to (excluido)
+	FindBetween(from time.Time, to time.Time) ([]*OrderSales, error)
+}
+
+func NewSalesRepository(log log.LogRusEntry, collection db.Collection) SalesRepository {
+	return &salesRepository{
+		log:        log,
+		collection: collection,
+	}
+}
+
+type salesRepository struct {
+	log        log.LogRusEntry
+	collection db.Collection
+}
+
+// Insert crea o reemplaza los hechos de la orden
+func (r *salesRepository) Insert(sales *OrderSales) (*OrderSales, error) {
+	if err := sales.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	filter := bson.M{"orderId": sales.OrderId}
+	updateOptions := options.Update().SetUpsert(true)
+	document := upsertSales{
+		Set: sales,
+	}
+
+	if _, err := r.collection.UpdateOne(context.Background(), filter, document, updateOptions); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	return sales, nil
+}
+
+type upsertSales struct {
+	Set *OrderSales `bson:"$set"`
+}
+
+func (r *salesRepository) FindBetween(from time.Time, to time.Time) ([]*OrderSales, error) {
+	filter := bson.M{
+		"first": bson.M{"$lt": to},
+		"last":  bson.M{"$gte": from},
+	}
+	cur, err := r.collection.Find(context.Background(), filter)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	result := []*OrderSales{}
+	for cur.Next(context.Background()) {
+		sales := &OrderSales{}
+		if err := cur.Decode(sales); err != nil {
+			r.log.Error(err)
+			return nil, err
+		}
+		result = append(result, sales)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,16 @@
+package sales_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/projections/sales"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+// TestMongoSales corre contra MONGO_URL con las colecciones e índices del injector
+func TestMongoSales(t *testing.T) {
+	deps := &di.Deps{CurrLog: log.Get("", "test"), CurrDatabase: repotest.Mongo(t)}
+	repotest.SalesRepository(t, sales.NewSalesRepository(deps.Logger(), deps.SalesCollection()))
+}
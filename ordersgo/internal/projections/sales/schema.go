@@ -0,0 +1,71 @@
+package sales
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FactType string
+
+const (
+	Placed    FactType = "placed"
+	Validated FactType = "validated"
+	Paid      FactType = "paid"
+	Canceled  FactType = "canceled"
+	Payment   FactType = "payment"
+	Refund    FactType = "refund"
+)
+
+// OrderSales hechos de venta de una orden, cada uno con la fecha en que ocurrió.
+// First y Last acotan las fechas de los hechos para buscar por rango.
+type OrderSales struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OrderId string             `bson:"orderId" json:"orderId" validate:"required,min=1,max=100"`
+	Items   int                `bson:"items" json:"items"`
+	Facts   []*Fact            `bson:"facts" json:"facts" validate:"required,min=1"`
+	First   time.Time          `bson:"first" json:"first"`
+	Last    time.Time          `bson:"last" json:"last"`
+	Updated time.Time          `bson:"updated" json:"updated"`
+}
+
+type Fact struct {
+	Type   FactType  `bson:"type" json:"type"`
+	Time   time.Time `bson:"time" json:"time"`
+	Amount float32   `bson:"amount,omitempty" json:"amount,omitempty"`
+}
+
+// ValidateSchema valida la estructura para ser insertada en la db
+func (e *OrderSales) ValidateSchema() error {
+	return validator.New().Struct(e)
+}
+
+type Granularity string
+
+const (
+	Hour  Granularity = "hour"
+	Day   Granularity = "day"
+	Month Granularity = "month"
+)
+
+// Bucket ventas de un periodo, Start es el inicio del periodo en UTC
+type Bucket struct {
+	Start             time.Time `json:"start"`
+	Placed            int       `json:"placed"`
+	Validated         int       `json:"validated"`
+	Paid              int       `json:"paid"`
+	Canceled          int       `json:"canceled"`
+	GrossRevenue      float32   `json:"grossRevenue"`
+	Refunds           float32   `json:"refunds"`
+	NetRevenue        float32   `json:"netRevenue"`
+	AverageBasketSize float32   `json:"averageBasketSize"`
+}
+
+// Report ventas agrupadas por periodo entre From (incluido) y To (excluido)
+type Report struct {
+	From        time.Time   `json:"from"`
+	To          time.Time   `json:"to"`
+	Granularity Granularity `json:"granularity"`
+	Buckets     []*Bucket   `json:"buckets"`
+}
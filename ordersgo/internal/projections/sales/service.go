@@ -0,0 +1,54 @@
+package sales
+
+import (
+	"time"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+type SalesService interface {
+	Update(orderId string, ev []*events.Event) error
+	Report(from time.Time, to time.Time, granularity Granularity) (*Report, error)
+}
+
+func NewSalesService(log log.LogRusEntry, repository SalesRepository) SalesService {
+	return &salesService{
+		log:        log,
+		repository: repository,
+	}
+}
+
+type salesService struct {
+	log        log.LogRusEntry
+	repository SalesRepository
+}
+
+// Update reemplaza los hechos de la orden, calculados con todos sus eventos
+func (s *salesService) Update(orderId string, ev []*events.Event) error {
+	sales := newOrderSales(orderId, ev)
+	if len(sales.Facts) == 0 {
+		// Sin place_order todavía no hay nada que reportar
+		return nil
+	}
+
+	if _, err := s.repository.Insert(sales); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Report agrupa los hechos de las ordenes que tienen alguno dentro del rango
+func (s *salesService) Report(from time.Time, to time.Time, granularity Granularity) (*Report, error) {
+	if err := validateRange(from, to, granularity); err != nil {
+		return nil, err
+	}
+
+	orders, err := s.repository.FindBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReport(from, to, granularity, orders), nil
+}
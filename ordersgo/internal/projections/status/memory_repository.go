@@ -0,0 +1,50 @@
+package status
+
+import (
+	"time"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+)
+
+// NewMemoryStatusRepository proyección de estados en memoria, la tabla se comparte entre requests
+func NewMemoryStatusRepository(log log.LogRusEntry, table *memdb.Table[OrderStatus]) StatusRepository {
+	return &memoryStatusRepository{
+		log:   log,
+		table: table,
+	}
+}
+
+type memoryStatusRepository struct {
+	log   log.LogRusEntry
+	table *memdb.Table[OrderStatus]
+}
+
+func (r *memoryStatusRepository) Insert(order *OrderStatus) (*OrderStatus, error) {
+	if err := order.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	err := r.table.Upsert(order, func(current *OrderStatus) bool {
+		return current.OrderId == order.OrderId
+	})
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *memoryStatusRepository) FindByOrderId(orderId string) (*OrderStatus, error) {
+	return r.table.FindOne(func(order *OrderStatus) bool {
+		return order.OrderId == orderId
+	})
+}
+
+func (r *memoryStatusRepository) FindByCreated(from time.Time, to time.Time) ([]*OrderStatus, error) {
+	return r.table.Find(func(order *OrderStatus) bool {
+		return !order.Created.Before(from) && order.Created.Before(to)
+	})
+}
@@ -0,0 +1,14 @@
+package status_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestMemoryStatus(t *testing.T) {
+	repotest.StatusRepository(t, status.NewMemoryStatusRepository(log.Get("", "test"), memdb.NewTable[status.OrderStatus]()))
+}
@@ -0,0 +1,122 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewPostgresStatusRepository proyección de estados sobre la tabla status_projection
+func NewPostgresStatusRepository(log log.LogRusEntry, db pgdb.DB) StatusRepository {
+	return &postgresStatusRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+type postgresStatusRepository struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+}
+
+// Insert crea o reemplaza el estado, conserva el id de la primera inserción
+func (r *postgresStatusRepository) Insert(order *OrderStatus) (*OrderStatus, error) {
+	if err := order.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	id := order.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	milestones, err := json.Marshal(order.Milestones)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	_, err = r.db.Exec(context.Background(), `
+		INSERT INTO status_projection (order_id, id, user_id, milestones, created, updated)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (order_id) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			milestones = EXCLUDED.milestones,
+			created = EXCLUDED.created,
+			updated = EXCLUDED.updated`,
+		order.OrderId, id.Hex(), order.UserId, milestones, order.Created, order.Updated,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+const selectStatus = `SELECT id, order_id, user_id, milestones, created, updated FROM status_projection `
+
+func (r *postgresStatusRepository) FindByOrderId(orderId string) (*OrderStatus, error) {
+	rows, err := r.db.Query(context.Background(), selectStatus+"WHERE order_id = $1", orderId)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	order, err := pgx.CollectOneRow(rows, scanStatus)
+	if err == pgx.ErrNoRows {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *postgresStatusRepository) FindByCreated(from time.Time, to time.Time) ([]*OrderStatus, error) {
+	rows, err := r.db.Query(context.Background(), selectStatus+"WHERE created >= $1 AND created < $2", from, to)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	result, err := pgx.CollectRows(rows, scanStatus)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func scanStatus(row pgx.CollectableRow) (*OrderStatus, error) {
+	var id string
+	var milestones []byte
+	var created, updated time.Time
+	order := &OrderStatus{}
+
+	err := row.Scan(&id, &order.OrderId, &order.UserId, &milestones, &created, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(milestones, &order.Milestones); err != nil {
+		return nil, err
+	}
+
+	order.Created = created.UTC()
+	order.Updated = updated.UTC()
+	return order, nil
+}
@@ -0,0 +1,13 @@
+package status_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestPostgresStatus(t *testing.T) {
+	repotest.StatusRepository(t, status.NewPostgresStatusRepository(log.Get("", "test"), repotest.Postgres(t)))
+}
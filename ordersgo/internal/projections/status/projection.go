@@ -0,0 +1,35 @@
+package status
+
+import (
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// Version de status_projection, se incrementa al cambiar cómo se proyecta para reconstruirla
+const Version = 5
+
+// NewStatusProjection registra status_projection en el registro de proyecciones
+func NewStatusProjection(service StatusService) *StatusProjection {
+	return &StatusProjection{
+		service: service,
+	}
+}
+
+type StatusProjection struct {
+	service StatusService
+}
+
+func (p *StatusProjection) Name() string {
+	return "status"
+}
+
+func (p *StatusProjection) Version() int {
+	return Version
+}
+
+func (p *StatusProjection) EventTypes() []events.EventType {
+	return []events.EventType{events.Place, events.Validation, events.Payment, events.Refund, events.Cancel, events.Fulfillment, events.Return}
+}
+
+func (p *StatusProjection) Apply(orderId string, ev []*events.Event) error {
+	return p.service.Update(orderId, ev)
+}
@@ -2,6 +2,7 @@ package status
 
 import (
 	"context"
+	"time"
 
 	"github.com/nmarsollier/commongo/db"
 	"github.com/nmarsollier/commongo/errs"
@@ -13,6 +14,8 @@ import (
 type StatusRepository interface {
 	Insert(order *OrderStatus) (*OrderStatus, error)
 	FindByOrderId(orderId string) (*OrderStatus, error)
+	// FindByCreated ordenes colocadas entre from (incluido) y to (excluido)
+	FindByCreated(from time.Time, to time.Time) ([]*OrderStatus, error)
 }
 
 func NewStatusRepository(log log.LogRusEntry, collection db.Collection) StatusRepository {
@@ -63,3 +66,25 @@ func (r *statusRepository) FindByOrderId(orderId string) (*OrderStatus, error) {
 
 	return order, nil
 }
+
+func (r *statusRepository) FindByCreated(from time.Time, to time.Time) ([]*OrderStatus, error) {
+	filter := bson.M{"created": bson.M{"$gte": from, "$lt": to}}
+	cur, err := r.collection.Find(context.Background(), filter)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	result := []*OrderStatus{}
+	for cur.Next(context.Background()) {
+		order := &OrderStatus{}
+		if err := cur.Decode(order); err != nil {
+			r.log.Error(err)
+			return nil, err
+		}
+		result = append(result, order)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,16 @@
+package status_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+// TestMongoStatus corre contra MONGO_URL con las colecciones e índices del injector
+func TestMongoStatus(t *testing.T) {
+	deps := &di.Deps{CurrLog: log.Get("", "test"), CurrDatabase: repotest.Mongo(t)}
+	repotest.StatusRepository(t, status.NewStatusRepository(deps.Logger(), deps.StatusCollection()))
+}
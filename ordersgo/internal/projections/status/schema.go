@@ -7,20 +7,67 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Estuctura basica de del evento
+type MilestoneType string
+
+const (
+	Placed           MilestoneType = "placed"
+	ArticleValidated MilestoneType = "article_validated"
+	Validated        MilestoneType = "validated"
+	Invalid          MilestoneType = "invalid"
+	FirstPayment     MilestoneType = "first_payment"
+	FullyPaid        MilestoneType = "fully_paid"
+	Refunded         MilestoneType = "refunded"
+	Chargeback       MilestoneType = "chargeback"
+	Canceled         MilestoneType = "canceled"
+	Packing          MilestoneType = "packing"
+	Shipped          MilestoneType = "shipped"
+	Delivered        MilestoneType = "delivered"
+	DeliveryFailed   MilestoneType = "delivery_failed"
+	ReturnRequested  MilestoneType = "return_requested"
+	ReturnApproved   MilestoneType = "return_approved"
+	ReturnRejected   MilestoneType = "return_rejected"
+	ReturnReceived   MilestoneType = "return_received"
+	// Expired no tiene evento, se agrega al leer las ordenes sin pagos que superan el vencimiento
+	Expired MilestoneType = "expired"
+)
+
+// OrderStatus línea de tiempo de la orden, los hitos en el orden en que ocurrieron
 type OrderStatus struct {
 	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	OrderId string             `bson:"orderId"`
-	UserId  string             `bson:"userId"`
+	OrderId string             `bson:"orderId" json:"orderId" validate:"required,min=1,max=100"`
+	UserId  string             `bson:"userId" json:"userId"`
+
+	Milestones []*Milestone `bson:"milestones" json:"milestones"`
 
-	Placed           bool `bson:"placed"`
-	PartialValidated bool `bson:"partialValidated"`
-	Validated        bool `bson:"validated"`
-	PartialPayment   bool `bson:"partialPayment"`
-	PaymentCompleted bool `bson:"paymentCompleted"`
+	Created time.Time `bson:"created" json:"created"`
+	Updated time.Time `bson:"updated" json:"updated"`
+}
+
+// Milestone hito de la orden, los datos opcionales dependen del tipo:
+// article_validated informa el artículo y si es válido, los de pago el pago y el monto,
+// canceled el motivo, shipped el transporte y el número de seguimiento, delivery_failed el motivo y los de
+// devolución la devolución, el motivo y al recibirla el monto a reembolsar.
+type Milestone struct {
+	Type           MilestoneType `bson:"type" json:"type"`
+	Time           time.Time     `bson:"time" json:"time"`
+	ArticleId      string        `bson:"articleId,omitempty" json:"articleId,omitempty"`
+	Valid          *bool         `bson:"valid,omitempty" json:"valid,omitempty"`
+	PaymentId      string        `bson:"paymentId,omitempty" json:"paymentId,omitempty"`
+	Amount         float32       `bson:"amount,omitempty" json:"amount,omitempty"`
+	Reason         string        `bson:"reason,omitempty" json:"reason,omitempty"`
+	Carrier        string        `bson:"carrier,omitempty" json:"carrier,omitempty"`
+	TrackingNumber string        `bson:"trackingNumber,omitempty" json:"trackingNumber,omitempty"`
+	ReturnId       string        `bson:"returnId,omitempty" json:"returnId,omitempty"`
+}
 
-	Created time.Time `bson:"created"`
-	Updated time.Time `bson:"updated"`
+// Reached indica si la orden alcanzó el hito
+func (e *OrderStatus) Reached(milestone MilestoneType) bool {
+	for _, current := range e.Milestones {
+		if current.Type == milestone {
+			return true
+		}
+	}
+	return false
 }
 
 // ValidateSchema valida la estructura para ser insertada en la db
@@ -0,0 +1,218 @@
+package status
+
+import (
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// timeline acumula el estado necesario para decidir los hitos mientras se recorren los eventos
+type timeline struct {
+	status      *OrderStatus
+	articles    []*articleState
+	payments    []*events.PaymentState
+	fulfillment *events.FulfillmentState
+	returns     []*events.ReturnState
+}
+
+type articleState struct {
+	articleId string
+	quantity  int
+	price     float32
+	validated bool
+	valid     bool
+}
+
+func newTimeline(orderId string) *timeline {
+	return &timeline{
+		status: &OrderStatus{
+			OrderId:    orderId,
+			Milestones: []*Milestone{},
+		},
+	}
+}
+
+func (t *timeline) apply(e *events.Event) {
+	switch e.Type {
+	case events.Place:
+		t.applyPlace(e)
+	case events.Validation:
+		t.applyValidation(e)
+	case events.Payment, events.Refund:
+		t.applyPayment(e)
+	case events.Cancel:
+		t.applyCancel(e)
+	case events.Fulfillment:
+		t.applyFulfillment(e)
+	case events.Return:
+		t.applyReturn(e)
+	}
+	t.status.Updated = e.Updated
+}
+
+func (t *timeline) add(milestone *Milestone) {
+	t.status.Milestones = append(t.status.Milestones, milestone)
+}
+
+func (t *timeline) applyPlace(e *events.Event) {
+	t.status.UserId = e.PlaceEvent.UserId
+	t.status.Created = e.Created
+
+	t.articles = make([]*articleState, len(e.PlaceEvent.Articles))
+	for i, article := range e.PlaceEvent.Articles {
+		t.articles[i] = &articleState{
+			articleId: article.ArticleId,
+			quantity:  article.Quantity,
+		}
+	}
+
+	t.add(&Milestone{Type: Placed, Time: e.Created})
+}
+
+// applyValidation una validación repetida con el mismo resultado no agrega hitos
+func (t *timeline) applyValidation(e *events.Event) {
+	validation := e.Validation
+
+	var article *articleState
+	for _, current := range t.articles {
+		if current.articleId == validation.ArticleId {
+			article = current
+		}
+	}
+	if article == nil || (article.validated && article.valid == validation.IsValid) {
+		return
+	}
+
+	article.validated = true
+	article.valid = validation.IsValid
+	article.price = validation.Price
+
+	valid := validation.IsValid
+	t.add(&Milestone{Type: ArticleValidated, Time: e.Created, ArticleId: validation.ArticleId, Valid: &valid})
+
+	if !valid && !t.status.Reached(Invalid) {
+		t.add(&Milestone{Type: Invalid, Time: e.Created, ArticleId: validation.ArticleId})
+		return
+	}
+
+	if !t.status.Reached(Validated) && !t.status.Reached(Invalid) && t.allValid() {
+		t.add(&Milestone{Type: Validated, Time: e.Created})
+	}
+}
+
+func (t *timeline) allValid() bool {
+	for _, article := range t.articles {
+		if !article.validated || !article.valid {
+			return false
+		}
+	}
+	return len(t.articles) > 0
+}
+
+// applyPayment cada evento de pago o reembolso es una transición del pago, las inválidas no generan hitos
+func (t *timeline) applyPayment(e *events.Event) {
+	paymentId := e.PaymentId()
+
+	var current *events.PaymentState
+	for _, p := range t.payments {
+		if paymentId != "" && p.PaymentId == paymentId {
+			current = p
+		}
+	}
+	if current == nil {
+		current = &events.PaymentState{PaymentId: paymentId}
+		if !current.Apply(e) {
+			return
+		}
+		t.payments = append(t.payments, current)
+	} else if !current.Apply(e) {
+		return
+	}
+
+	transition := current.Transitions[len(current.Transitions)-1]
+	switch transition.Status {
+	case events.PaymentCaptured:
+		if !t.status.Reached(FirstPayment) {
+			t.add(&Milestone{Type: FirstPayment, Time: e.Created, PaymentId: paymentId, Amount: transition.Amount})
+		}
+		if !t.status.Reached(FullyPaid) && t.totalPrice() > 0 && t.paid() >= t.totalPrice() {
+			t.add(&Milestone{Type: FullyPaid, Time: e.Created, Amount: t.paid()})
+		}
+	case events.PaymentPartiallyRefunded, events.PaymentRefunded:
+		t.add(&Milestone{Type: Refunded, Time: e.Created, PaymentId: paymentId, Amount: transition.Amount})
+	case events.PaymentChargeback:
+		t.add(&Milestone{Type: Chargeback, Time: e.Created, PaymentId: paymentId, Amount: transition.Amount})
+	}
+}
+
+func (t *timeline) applyCancel(e *events.Event) {
+	if t.status.Reached(Canceled) {
+		return
+	}
+	t.add(&Milestone{Type: Canceled, Time: e.Created, Reason: e.CancelEvent.Reason})
+}
+
+// applyFulfillment cada transición válida del envío es un hito, se repiten si se vuelve a despachar
+func (t *timeline) applyFulfillment(e *events.Event) {
+	if t.fulfillment == nil {
+		t.fulfillment = &events.FulfillmentState{}
+	}
+	if !t.fulfillment.Apply(e) {
+		return
+	}
+
+	fulfillment := e.Fulfillment
+	milestone := &Milestone{Type: MilestoneType(fulfillment.Status), Time: e.Created}
+	switch fulfillment.Status {
+	case events.FulfillmentShipped:
+		milestone.Carrier = fulfillment.Carrier
+		milestone.TrackingNumber = fulfillment.TrackingNumber
+	case events.FulfillmentDeliveryFailed:
+		milestone.Reason = fulfillment.Reason
+	}
+	t.add(milestone)
+}
+
+// applyReturn cada transición válida de una devolución es un hito
+func (t *timeline) applyReturn(e *events.Event) {
+	data := e.Return
+
+	var current *events.ReturnState
+	for _, r := range t.returns {
+		if r.ReturnId == data.ReturnId {
+			current = r
+		}
+	}
+	if current == nil {
+		current = &events.ReturnState{}
+		if !current.Apply(e) {
+			return
+		}
+		t.returns = append(t.returns, current)
+	} else if !current.Apply(e) {
+		return
+	}
+
+	t.add(&Milestone{
+		Type:     MilestoneType("return_" + string(data.Status)),
+		Time:     e.Created,
+		ReturnId: data.ReturnId,
+		Reason:   data.Reason,
+		Amount:   data.Amount,
+	})
+}
+
+func (t *timeline) totalPrice() float32 {
+	var result float32
+	for _, article := range t.articles {
+		result += article.price * float32(article.quantity)
+	}
+	return result
+}
+
+// paid neto cobrado de todos los pagos
+func (t *timeline) paid() float32 {
+	var result float32
+	for _, payment := range t.payments {
+		result += payment.Remaining()
+	}
+	return result
+}
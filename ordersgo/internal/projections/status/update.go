@@ -1,91 +1,88 @@
 package status
 
 import (
+	"time"
+
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/ordersgo/internal/events"
-	"github.com/nmarsollier/ordersgo/internal/projections/order"
 )
 
 type StatusService interface {
-	Update(orderId string, ev []*events.Event, odr *order.Order) error
+	Update(orderId string, ev []*events.Event) error
+	FindByOrderId(orderId string) (*OrderStatus, error)
+	FindByCreated(from time.Time, to time.Time) ([]*OrderStatus, error)
 }
 
-func NewStatusService(log log.LogRusEntry, repository StatusRepository) StatusService {
+// NewStatusService expiration es el tiempo que una orden puede estar sin pagos antes de vencer
+func NewStatusService(log log.LogRusEntry, repository StatusRepository, expiration time.Duration) StatusService {
 	return &statusService{
 		log:        log,
 		repository: repository,
+		expiration: expiration,
 	}
 }
 
 type statusService struct {
 	log        log.LogRusEntry
 	repository StatusRepository
+	expiration time.Duration
 }
 
-func (s *statusService) Update(orderId string, ev []*events.Event, odr *order.Order) error {
-	status, _ := s.repository.FindByOrderId(orderId)
-	if status == nil {
-		status = &OrderStatus{
-			OrderId: orderId,
-		}
+// Update arma la línea de tiempo desde cero con todos los eventos de la orden
+func (s *statusService) Update(orderId string, ev []*events.Event) error {
+	if _, err := s.repository.Insert(Project(orderId, ev)); err != nil {
+		return err
 	}
 
-	switch odr.Status {
-	case order.Validated:
-		{
-			status.Validated = true
-		}
-	case order.Payment_Defined:
-		{
-			status.PaymentCompleted = true
-		}
-	}
+	return nil
+}
 
+// Project arma la línea de tiempo con los eventos, sin leer ni guardar la proyección
+func Project(orderId string, ev []*events.Event) *OrderStatus {
+	timeline := newTimeline(orderId)
 	for _, e := range ev {
-		status = s.update(status, e)
+		timeline.apply(e)
 	}
+	return timeline.status
+}
 
-	if _, err := s.repository.Insert(status); err != nil {
-		return err
+func (s *statusService) FindByOrderId(orderId string) (*OrderStatus, error) {
+	status, err := s.repository.FindByOrderId(orderId)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	Expire(status, s.expiration, time.Now())
+	return status, nil
 }
 
-func (s *statusService) update(order *OrderStatus, event *events.Event) *OrderStatus {
-	switch event.Type {
-	case events.Place:
-		order = s.updadatePlace(order, event)
-	case events.Validation:
-		order = s.updadateValidation(order, event)
-	case events.Payment:
-		order = s.updadatePayment(order, event)
+func (s *statusService) FindByCreated(from time.Time, to time.Time) ([]*OrderStatus, error) {
+	statuses, err := s.repository.FindByCreated(from, to)
+	if err != nil {
+		return nil, err
 	}
-	return order
-}
 
-func (s *statusService) updadatePlace(o *OrderStatus, e *events.Event) *OrderStatus {
-	o.OrderId = e.OrderId
-	o.UserId = e.PlaceEvent.UserId
-	o.Placed = true
-
-	o.Created = e.Created
-	o.Updated = e.Updated
-	return o
+	now := time.Now()
+	for _, status := range statuses {
+		Expire(status, s.expiration, now)
+	}
+	return statuses, nil
 }
 
-func (s *statusService) updadateValidation(o *OrderStatus, e *events.Event) *OrderStatus {
-	if e.Validation.IsValid {
-		o.PartialValidated = true
+// Expire agrega el hito expired a las ordenes que pasado el vencimiento no recibieron pagos ni se
+// cancelaron. No hay evento de expiración, depende de la hora de lectura y no se guarda en la proyección.
+func Expire(status *OrderStatus, expiration time.Duration, now time.Time) {
+	if expiration <= 0 || !status.Reached(Placed) {
+		return
+	}
+	if status.Reached(FirstPayment) || status.Reached(Canceled) || status.Reached(Invalid) || status.Reached(Expired) {
+		return
 	}
-	o.Updated = e.Updated
-	return o
-}
 
-func (s *statusService) updadatePayment(o *OrderStatus, e *events.Event) *OrderStatus {
-	if e.Payment.Amount > 0 {
-		o.PartialPayment = true
+	expiresAt := status.Created.Add(expiration)
+	if now.Before(expiresAt) {
+		return
 	}
-	o.Updated = e.Updated
-	return o
+
+	status.Milestones = append(status.Milestones, &Milestone{Type: Expired, Time: expiresAt})
 }
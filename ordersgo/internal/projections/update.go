@@ -1,44 +1,86 @@
 package projections
 
 import (
+	"time"
+
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/ordersgo/internal/events"
-	"github.com/nmarsollier/ordersgo/internal/projections/order"
-	"github.com/nmarsollier/ordersgo/internal/projections/status"
+	"github.com/nmarsollier/ordersgo/internal/metrics"
+	"github.com/nmarsollier/ordersgo/internal/projections/version"
 )
 
 type ProjectionsService interface {
-	Update(orderId string) error
+	Update(orderId string, projections ...string) error
+	UpdateAsync(orderId string)
+	Rebuild(name string) error
+	Versions() ([]*ProjectionVersion, error)
 }
 
-func NewProjectionsService(log log.LogRusEntry, events events.EventService, order order.OrderService, status status.StatusService) ProjectionsService {
+func NewProjectionsService(
+	log log.LogRusEntry,
+	metrics *metrics.Metrics,
+	dispatcher *Dispatcher,
+	events events.EventService,
+	registry *Registry,
+	versions version.VersionRepository,
+) ProjectionsService {
 	return &projectionsService{
-		log:    log,
-		events: events,
-		order:  order,
-		status: status,
+		log:        log,
+		metrics:    metrics,
+		dispatcher: dispatcher,
+		events:     events,
+		registry:   registry,
+		versions:   versions,
 	}
 }
 
 type projectionsService struct {
-	log    log.LogRusEntry
-	events events.EventService
-	order  order.OrderService
-	status status.StatusService
+	log        log.LogRusEntry
+	metrics    *metrics.Metrics
+	dispatcher *Dispatcher
+	events     events.EventService
+	registry   *Registry
+	versions   version.VersionRepository
 }
 
-func (s *projectionsService) Update(orderId string) error {
+// Update reconstruye las proyecciones de la orden, sin projections actualiza todas las registradas
+func (s *projectionsService) Update(orderId string, projections ...string) error {
 	ev, err := s.events.FindByOrderId(orderId)
 	if err != nil {
 		s.log.Error(err)
 		return err
 	}
 
-	order, err := s.order.Update(orderId, ev)
-	if err != nil {
-		s.log.Error(err)
+	for _, projection := range s.registry.Select(projections) {
+		if !interested(projection, ev) {
+			continue
+		}
+
+		err := s.observe(projection.Name(), func() error {
+			return projection.Apply(orderId, ev)
+		})
+		if err != nil {
+			s.log.Error(err)
+			return err
+		}
 	}
 
-	s.status.Update(orderId, ev, order)
 	return nil
 }
+
+// observe registra duración y fallos de la actualización de una proyección
+func (s *projectionsService) observe(projection string, update func() error) error {
+	start := time.Now()
+	err := update()
+
+	s.metrics.ProjectionDuration.WithLabelValues(projection).Observe(time.Since(start).Seconds())
+	if err != nil {
+		s.metrics.ProjectionFailures.WithLabelValues(projection).Inc()
+	}
+	return err
+}
+
+// UpdateAsync encola la actualización en el dispatcher, el apagado espera que terminen
+func (s *projectionsService) UpdateAsync(orderId string) {
+	s.dispatcher.Enqueue(orderId)
+}
@@ -0,0 +1,42 @@
+package version
+
+import (
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+)
+
+// NewMemoryVersionRepository versiones en memoria, la tabla se comparte entre requests
+func NewMemoryVersionRepository(log log.LogRusEntry, table *memdb.Table[Version]) VersionRepository {
+	return &memoryVersionRepository{
+		log:   log,
+		table: table,
+	}
+}
+
+type memoryVersionRepository struct {
+	log   log.LogRusEntry
+	table *memdb.Table[Version]
+}
+
+func (r *memoryVersionRepository) Save(version *Version) (*Version, error) {
+	if err := version.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	err := r.table.Upsert(version, func(current *Version) bool {
+		return current.Projection == version.Projection
+	})
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return version, nil
+}
+
+func (r *memoryVersionRepository) FindByProjection(projection string) (*Version, error) {
+	return r.table.FindOne(func(version *Version) bool {
+		return version.Projection == projection
+	})
+}
@@ -0,0 +1,14 @@
+package version_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/projections/version"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestMemoryVersion(t *testing.T) {
+	repotest.VersionRepository(t, version.NewMemoryVersionRepository(log.Get("", "test"), memdb.NewTable[version.Version]()))
+}
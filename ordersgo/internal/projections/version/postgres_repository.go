@@ -0,0 +1,78 @@
+package version
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewPostgresVersionRepository versiones sobre la tabla projection_versions
+func NewPostgresVersionRepository(log log.LogRusEntry, db pgdb.DB) VersionRepository {
+	return &postgresVersionRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+type postgresVersionRepository struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+}
+
+// Save crea o reemplaza la versión, conserva el id de la primera inserción
+func (r *postgresVersionRepository) Save(version *Version) (*Version, error) {
+	if err := version.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	id := version.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	_, err := r.db.Exec(context.Background(), `
+		INSERT INTO projection_versions (projection, id, version, updated)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (projection) DO UPDATE SET
+			version = EXCLUDED.version,
+			updated = EXCLUDED.updated`,
+		version.Projection, id.Hex(), version.Version, version.Updated,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return version, nil
+}
+
+func (r *postgresVersionRepository) FindByProjection(projection string) (*Version, error) {
+	var id string
+	var updated time.Time
+	version := &Version{}
+
+	err := r.db.QueryRow(context.Background(), `
+		SELECT id, projection, version, updated FROM projection_versions WHERE projection = $1`, projection,
+	).Scan(&id, &version.Projection, &version.Version, &updated)
+	if err == pgx.ErrNoRows {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	if version.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	version.Updated = updated.UTC()
+	return version, nil
+}
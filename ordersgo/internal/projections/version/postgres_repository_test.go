@@ -0,0 +1,13 @@
+package version_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/projections/version"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestPostgresVersion(t *testing.T) {
+	repotest.VersionRepository(t, version.NewPostgresVersionRepository(log.Get("", "test"), repotest.Postgres(t)))
+}
@@ -0,0 +1,67 @@
+package version
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/db"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type VersionRepository interface {
+	Save(version *Version) (*Version, error)
+	FindByProjection(projection string) (*Version, error)
+}
+
+func NewVersionRepository(log log.LogRusEntry, collection db.Collection) VersionRepository {
+	return &versionRepository{
+		log:        log,
+		collection: collection,
+	}
+}
+
+type versionRepository struct {
+	log        log.LogRusEntry
+	collection db.Collection
+}
+
+// Save crea o reemplaza la versión de la proyección
+func (r *versionRepository) Save(version *Version) (*Version, error) {
+	if err := version.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	filter := bson.M{"projection": version.Projection}
+	updateOptions := options.Update().SetUpsert(true)
+	document := upsertVersion{
+		Set: version,
+	}
+
+	if _, err := r.collection.UpdateOne(context.Background(), filter, document, updateOptions); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	return version, nil
+}
+
+type upsertVersion struct {
+	Set *Version `bson:"$set"`
+}
+
+func (r *versionRepository) FindByProjection(projection string) (*Version, error) {
+	version := &Version{}
+	filter := bson.M{"projection": projection}
+	if err := r.collection.FindOne(context.Background(), filter, version); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errs.NotFound
+		}
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return version, nil
+}
@@ -0,0 +1,16 @@
+package version_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/projections/version"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+// TestMongoVersion corre contra MONGO_URL con las colecciones e índices del injector
+func TestMongoVersion(t *testing.T) {
+	deps := &di.Deps{CurrLog: log.Get("", "test"), CurrDatabase: repotest.Mongo(t)}
+	repotest.VersionRepository(t, version.NewVersionRepository(deps.Logger(), deps.VersionsCollection()))
+}
@@ -0,0 +1,21 @@
+package version
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Version versión con la que está construida una proyección
+type Version struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Projection string             `bson:"projection" validate:"required"`
+	Version    int                `bson:"version" validate:"min=1"`
+	Updated    time.Time          `bson:"updated"`
+}
+
+// ValidateSchema valida la estructura para ser insertada en la db
+func (e *Version) ValidateSchema() error {
+	return validator.New().Struct(e)
+}
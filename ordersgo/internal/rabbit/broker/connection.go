@@ -0,0 +1,94 @@
+package broker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nmarsollier/ordersgo/internal/env"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
+	"github.com/streadway/amqp"
+)
+
+// Conexión compartida por todos los publishers, los consumers usan su propia conexión
+var (
+	mutex         sync.Mutex
+	connection    *amqp.Connection
+	channel       *amqp.Channel
+	channelClosed chan *amqp.Error
+	closeOnce     sync.Once
+)
+
+// publishChannel devuelve el canal compartido, reconectando si la conexión se cerró.
+// Un error de canal (por ejemplo un exchange declarado con otro tipo) cierra solo el canal,
+// en ese caso se abre uno nuevo sobre la misma conexión.
+func publishChannel() (*amqp.Channel, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if connection != nil && !connection.IsClosed() {
+		if channel != nil && !isClosed(channelClosed) {
+			return channel, nil
+		}
+
+		if err := openChannel(connection); err != nil {
+			return nil, err
+		}
+		return channel, nil
+	}
+
+	conn, err := amqp.Dial(env.Get().RabbitURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := openChannel(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	connection = conn
+
+	closeOnce.Do(func() {
+		lifecycle.OnShutdown(lifecycle.Connections, "rabbit", Close)
+	})
+
+	return channel, nil
+}
+
+// openChannel abre el canal compartido y se suscribe a su cierre
+func openChannel(conn *amqp.Connection) error {
+	chn, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+
+	channel = chn
+	channelClosed = chn.NotifyClose(make(chan *amqp.Error, 1))
+	return nil
+}
+
+// isClosed indica si llegó la notificación de cierre, amqp cierra el chan al cerrar el canal
+func isClosed(closed chan *amqp.Error) bool {
+	select {
+	case <-closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close cierra la conexión compartida de publicación
+func Close(ctx context.Context) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if connection == nil || connection.IsClosed() {
+		return nil
+	}
+
+	err := connection.Close()
+	connection = nil
+	channel = nil
+	channelClosed = nil
+	return err
+}
@@ -0,0 +1,169 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/commongo/rbt"
+	"github.com/nmarsollier/commongo/strs"
+	"github.com/nmarsollier/ordersgo/internal/env"
+	uuid "github.com/satori/go.uuid"
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Consumer define la suscripción de un consumer
+type Consumer struct {
+	Exchange    string
+	ChannelType string
+	Queue       string
+	RoutingKey  string
+}
+
+// Delivery mensaje recibido, Redelivered indica que rabbit ya lo había entregado antes.
+// Context continúa la traza de quien publicó el mensaje.
+type Delivery[T any] struct {
+	Context     context.Context
+	Queue       string
+	Redelivered bool
+	Message     *rbt.InputMessage[T]
+}
+
+// Handler procesa un mensaje, si devuelve error el mensaje se reencola una única vez
+type Handler[T any] func(log.LogRusEntry, *Delivery[T]) error
+
+// name identifica al consumer, las colas anónimas se identifican por exchange
+func (c Consumer) name() string {
+	if c.Queue == "" {
+		return c.Exchange
+	}
+	return c.Queue
+}
+
+// Consume escucha la cola hasta que se cierre la conexión o se cancele ctx.
+// Al cancelar ctx se termina de procesar el mensaje en curso y se cierra la conexión.
+func Consume[T any](
+	ctx context.Context,
+	consumer Consumer,
+	processIncomingMessage Handler[T],
+) (err error) {
+	logger := rbt.RbtLogger(env.Get().FluentURL, env.Get().ServerName, uuid.NewV4().String())
+
+	defer func() {
+		if ctx.Err() != nil {
+			setState(consumer.name(), Stopped, nil)
+		} else {
+			setState(consumer.name(), Reconnecting, err)
+		}
+	}()
+
+	conn, err := amqp.Dial(env.Get().RabbitURL)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+	defer conn.Close()
+
+	chn, err := conn.Channel()
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+	defer chn.Close()
+
+	if err = chn.ExchangeDeclare(consumer.Exchange, consumer.ChannelType, false, false, false, false, nil); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	queue, err := chn.QueueDeclare(consumer.Queue, false, false, false, false, nil)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err = chn.QueueBind(queue.Name, consumer.RoutingKey, consumer.Exchange, false, nil); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	mgs, err := chn.Consume(queue.Name, "", false, false, false, false, nil)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	setState(consumer.name(), Connected, nil)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Consumer canceled: ", consumer.Queue)
+			return nil
+		case closeErr := <-closed:
+			logger.Info("Closed connection: ", closeErr)
+			if closeErr != nil {
+				return closeErr
+			}
+			return nil
+		case d, ok := <-mgs:
+			if !ok {
+				return nil
+			}
+			deliver(ctx, d, consumer, processIncomingMessage)
+		}
+	}
+}
+
+func deliver[T any](
+	ctx context.Context,
+	d amqp.Delivery,
+	consumer Consumer,
+	processIncomingMessage Handler[T],
+) {
+	newMessage := &rbt.InputMessage[T]{}
+	if err := json.Unmarshal(d.Body, newMessage); err != nil {
+		// Mensaje mal formado, se descarta para no bloquear la cola
+		rbt.RbtLogger(env.Get().FluentURL, env.Get().ServerName, uuid.NewV4().String()).Error(err)
+		d.Ack(false)
+		return
+	}
+
+	correlationId := newMessage.CorrelationId
+	if len(correlationId) == 0 {
+		correlationId = uuid.NewV4().String()
+	}
+
+	l := rbt.RbtLogger(env.Get().FluentURL, env.Get().ServerName, correlationId).
+		WithField(log.LOG_FIELD_RABBIT_ACTION, "consume").
+		WithField(log.LOG_FIELD_RABBIT_EXCHANGE, consumer.Exchange).
+		WithField(log.LOG_FIELD_RABBIT_QUEUE, consumer.Queue)
+
+	l.Info("Incoming :", string(d.Body))
+
+	// El procesamiento no se corta al cancelar el consumer, solo se hereda la traza
+	spanCtx, span := startConsumeSpan(context.WithoutCancel(ctx), consumer, d.Headers)
+	defer span.End()
+
+	err := processIncomingMessage(l, &Delivery[T]{
+		Context:     spanCtx,
+		Queue:       consumer.name(),
+		Redelivered: d.Redelivered,
+		Message:     newMessage,
+	})
+	if err != nil {
+		// Igual que commongo/rbt el mensaje fallido se confirma, queda en el log y en
+		// orders_rabbit_messages_failed_total. Las colas no tienen dead letter exchange.
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		l.Error(err)
+	}
+
+	if err := d.Ack(false); err != nil {
+		l.Info("Failed ACK :", strs.ToJson(newMessage), err)
+	} else {
+		l.Info("Consumed :", strs.ToJson(newMessage))
+	}
+}
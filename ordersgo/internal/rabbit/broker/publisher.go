@@ -0,0 +1,117 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/commongo/rbt"
+	"github.com/nmarsollier/ordersgo/internal/metrics"
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// NewPublisher crea un publisher sobre la conexión compartida.
+// Es compatible con rbt.RabbitPublisher y mantiene el mismo formato de mensaje.
+// ctx es el contexto de la operación que publica, su traza se propaga en los headers.
+func NewPublisher[T any](
+	ctx context.Context,
+	log log.LogRusEntry,
+	metrics *metrics.Metrics,
+	exchangeName string,
+	channelType string,
+	routingKey string,
+) rbt.RabbitPublisher[T] {
+	return &publisher[T]{
+		ctx:          ctx,
+		log:          log,
+		metrics:      metrics,
+		exchangeName: exchangeName,
+		channelType:  channelType,
+		routingKey:   routingKey,
+	}
+}
+
+type publisher[T any] struct {
+	ctx          context.Context
+	log          log.LogRusEntry
+	metrics      *metrics.Metrics
+	exchangeName string
+	channelType  string
+	routingKey   string
+}
+
+func (p *publisher[T]) Publish(data T) error {
+	return p.PublishTo(p.exchangeName, p.routingKey, data)
+}
+
+func (p *publisher[T]) PublishForResult(data T, exchange string, routingKey string) error {
+	return p.publish(p.exchangeName, p.routingKey, data, exchange, routingKey)
+}
+
+func (p *publisher[T]) PublishTo(exchange string, routingKey string, data T) error {
+	return p.publish(exchange, routingKey, data, "", "")
+}
+
+func (p *publisher[T]) Logger() log.LogRusEntry {
+	return p.log
+}
+
+func (p *publisher[T]) publish(exchange string, routingKey string, data T, fbExchange string, fbRoutingKey string) (err error) {
+	headers := amqp.Table{}
+	span := startPublishSpan(p.ctx, exchange, routingKey, headers)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		p.metrics.MessagesPublished.WithLabelValues(exchange, metrics.Status(err)).Inc()
+	}()
+
+	logger := p.log.WithField(log.LOG_FIELD_RABBIT_ACTION, "Emit").
+		WithField(log.LOG_FIELD_RABBIT_EXCHANGE, exchange).
+		WithField(log.LOG_FIELD_RABBIT_QUEUE, routingKey)
+
+	chn, err := publishChannel()
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err = chn.ExchangeDeclare(exchange, p.channelType, false, false, false, false, nil); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	body, err := json.Marshal(outputMessage[T]{
+		CorrelationId: p.log.CorrelationId(),
+		Exchange:      fbExchange,
+		RoutingKey:    fbRoutingKey,
+		Message:       data,
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	err = chn.Publish(exchange, routingKey, false, false, amqp.Publishing{
+		Headers: headers,
+		Body:    body,
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Info("Rabbit publish ", exchange+" ", routingKey+" ", string(body))
+	return nil
+}
+
+// outputMessage mismo formato que publica commongo/rbt
+type outputMessage[T any] struct {
+	CorrelationId string `json:"correlation_id"`
+	Exchange      string `json:"exchange"`
+	RoutingKey    string `json:"routing_key"`
+	Message       T      `json:"message"`
+}
@@ -0,0 +1,53 @@
+package broker
+
+import (
+	"sync"
+	"time"
+)
+
+type ConsumerState string
+
+const (
+	Connected    ConsumerState = "connected"
+	Reconnecting ConsumerState = "reconnecting"
+	Stopped      ConsumerState = "stopped"
+)
+
+// ConsumerStatus estado de conexión de un consumer
+type ConsumerStatus struct {
+	State     ConsumerState `json:"state"`
+	LastError string        `json:"lastError,omitempty"`
+	Since     time.Time     `json:"since"`
+}
+
+var (
+	statusMutex sync.Mutex
+	consumers   = map[string]ConsumerStatus{}
+)
+
+// Consumers devuelve el estado de cada consumer por nombre de cola
+func Consumers() map[string]ConsumerStatus {
+	statusMutex.Lock()
+	defer statusMutex.Unlock()
+
+	result := make(map[string]ConsumerStatus, len(consumers))
+	for queue, status := range consumers {
+		result[queue] = status
+	}
+	return result
+}
+
+func setState(queue string, state ConsumerState, err error) {
+	statusMutex.Lock()
+	defer statusMutex.Unlock()
+
+	status := consumers[queue]
+	if status.State != state {
+		status.Since = time.Now()
+	}
+	status.State = state
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	consumers[queue] = status
+}
@@ -0,0 +1,75 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nmarsollier/ordersgo/internal/telemetry"
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// headersCarrier adapta los headers amqp para propagar el trace context W3C
+type headersCarrier amqp.Table
+
+func (c headersCarrier) Get(key string) string {
+	value, ok := c[key]
+	if !ok {
+		return ""
+	}
+	if str, ok := value.(string); ok {
+		return str
+	}
+	return fmt.Sprint(value)
+}
+
+func (c headersCarrier) Set(key string, value string) {
+	c[key] = value
+}
+
+func (c headersCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+var _ propagation.TextMapCarrier = headersCarrier{}
+
+// startPublishSpan inicia el span de publicación e inyecta el trace context en headers
+func startPublishSpan(ctx context.Context, exchange string, routingKey string, headers amqp.Table) trace.Span {
+	ctx, span := telemetry.Tracer().Start(ctx, exchange+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingDestinationName(exchange),
+			semconv.MessagingRabbitmqDestinationRoutingKey(routingKey),
+			semconv.MessagingOperationTypePublish,
+		),
+	)
+	otel.GetTextMapPropagator().Inject(ctx, headersCarrier(headers))
+	return span
+}
+
+// startConsumeSpan continúa la traza del publicador a partir de los headers del mensaje
+func startConsumeSpan(ctx context.Context, consumer Consumer, headers amqp.Table) (context.Context, trace.Span) {
+	if headers != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, headersCarrier(headers))
+	}
+
+	return telemetry.Tracer().Start(ctx, consumer.name()+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingDestinationName(consumer.Exchange),
+			semconv.MessagingRabbitmqDestinationRoutingKey(consumer.RoutingKey),
+			attribute.String("messaging.rabbitmq.queue", consumer.name()),
+			semconv.MessagingOperationTypeDeliver,
+		),
+	)
+}
@@ -0,0 +1,40 @@
+package rabbit
+
+import (
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/commongo/rbt"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/messages"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
+)
+
+// consumeOnce envuelve el procesamiento de un mensaje para que las reentregas de rabbit
+// no generen eventos duplicados. El mensaje se reserva antes de procesarlo, así dos
+// consumidores con la misma reentrega no lo procesan a la vez, y se libera si hubo error.
+// Si el proceso muere entre la reserva y el fin, la reentrega se descarta como duplicada.
+func consumeOnce[T any](
+	process func(di.Injector, *rbt.InputMessage[T]) error,
+) broker.Handler[T] {
+	return func(logger log.LogRusEntry, delivery *broker.Delivery[T]) error {
+		deps := deliveryDeps(logger, delivery)
+		key := messages.MessageKey(delivery.Queue, delivery.Message.Message)
+
+		claimed, err := deps.MessagesService().Claim(delivery.Queue, key)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			logger.Info("Message already processed, skipping duplicate: ", key)
+			return nil
+		}
+
+		if err := process(deps, delivery.Message); err != nil {
+			if err := deps.MessagesService().Release(key); err != nil {
+				logger.Error(err)
+			}
+			return err
+		}
+
+		return nil
+	}
+}
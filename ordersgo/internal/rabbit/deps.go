@@ -0,0 +1,26 @@
+package rabbit
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
+)
+
+type depsKey struct{}
+
+// withDeps asocia un injector al contexto del mensaje, igual que "di" en el contexto de gin
+func withDeps(ctx context.Context, deps di.Injector) context.Context {
+	return context.WithValue(ctx, depsKey{}, deps)
+}
+
+// deliveryDeps devuelve el injector asociado al mensaje o uno nuevo con la traza del mensaje
+func deliveryDeps[T any](logger log.LogRusEntry, delivery *broker.Delivery[T]) di.Injector {
+	if delivery.Context != nil {
+		if deps, ok := delivery.Context.Value(depsKey{}).(di.Injector); ok {
+			return deps
+		}
+	}
+	return di.NewInjectorWithContext(delivery.Context, logger)
+}
@@ -0,0 +1,43 @@
+package rabbit
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/commongo/rbt"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
+)
+
+// listenTransition escucha <topic>.<status> del exchange en la cola orders_<topic>_<status>.
+// Cada estado de un ciclo de vida tiene su routing key y su cola, process arma el handler del estado.
+func listenTransition[S ~string, T any](
+	exchange string,
+	topic string,
+	status S,
+	process func(S) func(di.Injector, *rbt.InputMessage[T]) error,
+) func(context.Context, log.LogRusEntry) {
+	routingKey := topic + "." + string(status)
+	return func(ctx context.Context, logger log.LogRusEntry) {
+		for {
+			err := broker.Consume[T](
+				ctx,
+				broker.Consumer{
+					Exchange:    exchange,
+					ChannelType: "topic",
+					Queue:       "orders_" + topic + "_" + string(status),
+					RoutingKey:  routingKey,
+				},
+				observe(consumeOnce(process(status))),
+			)
+
+			if err != nil {
+				logger.Error(err)
+			}
+			logger.Info("RabbitMQ ", routingKey, " conectando en 5 segundos.")
+			if !waitReconnect(ctx) {
+				return
+			}
+		}
+	}
+}
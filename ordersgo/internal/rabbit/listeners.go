@@ -1,28 +1,86 @@
 package rabbit
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
 )
 
 func Init(deps di.Injector) {
 	logger := deps.Logger().
 		WithField(log.LOG_FIELD_CONTROLLER, "Rabbit").
 		WithField(log.LOG_FIELD_RABBIT_ACTION, "Init")
-	
+
+	ctx, cancel := context.WithCancel(context.Background())
+	consumers := &sync.WaitGroup{}
+
+	// Al apagar se cancelan los consumers y se espera que terminen el mensaje en curso
+	lifecycle.OnShutdown(lifecycle.Consumers, "rabbit consumers", func(shutdownCtx context.Context) error {
+		cancel()
+		return waitConsumers(shutdownCtx, consumers)
+	})
+
+	start := func(listen func(context.Context, log.LogRusEntry)) {
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			listen(ctx, logger)
+		}()
+	}
+
 	logger.Info("Iniciando consumers de RabbitMQ...")
-	go listenArticleExist(logger)
+	start(listenArticleExist)
 
-	go listenLogout(logger)
+	start(listenLogout)
 
-	go listenPlaceOrder(logger)
+	start(listenPlaceOrder)
 
 	// Payment event consumers
-	go listenPaymentSuccess(logger)
+	start(listenPaymentSuccess)
+
+	start(listenPaymentPartial)
+
+	start(listenPaymentFailed)
 
-	go listenPaymentPartial(logger)
+	start(listenPaymentRefunded)
+
+	start(listenPaymentPartiallyRefunded)
+
+	for _, status := range paymentTransitions {
+		start(listenPaymentTransition(status))
+	}
+
+	// Envíos
+	for _, status := range shippingTransitions {
+		start(listenShippingTransition(status))
+	}
+}
+
+// waitReconnect espera antes de reconectar un consumer, devuelve false si fue cancelado
+func waitReconnect(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(5 * time.Second):
+		return true
+	}
+}
 
-	go listenPaymentFailed(logger)
+func waitConsumers(ctx context.Context, consumers *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		consumers.Wait()
+		close(done)
+	}()
 
-	go listenPaymentRefunded(logger)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
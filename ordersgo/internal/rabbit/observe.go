@@ -0,0 +1,25 @@
+package rabbit
+
+import (
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
+)
+
+// observe registra las métricas de consumo de cada mensaje
+func observe[T any](handler broker.Handler[T]) broker.Handler[T] {
+	return func(logger log.LogRusEntry, delivery *broker.Delivery[T]) error {
+		metrics := deliveryDeps(logger, delivery).Metrics()
+
+		if delivery.Redelivered {
+			metrics.MessagesRetried.WithLabelValues(delivery.Queue).Inc()
+		}
+
+		if err := handler(logger, delivery); err != nil {
+			metrics.MessagesFailed.WithLabelValues(delivery.Queue).Inc()
+			return err
+		}
+
+		metrics.MessagesConsumed.WithLabelValues(delivery.Queue).Inc()
+		return nil
+	}
+}
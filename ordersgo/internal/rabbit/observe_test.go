@@ -0,0 +1,83 @@
+package rabbit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/commongo/rbt"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/metrics"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/rbschema"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	uuid "github.com/satori/go.uuid"
+)
+
+// nopPublisher descarta los mensajes, el test no tiene rabbit
+type nopPublisher[T any] struct {
+	logger log.LogRusEntry
+}
+
+func (p nopPublisher[T]) PublishForResult(data T, exchange string, routingKey string) error {
+	return nil
+}
+
+func (p nopPublisher[T]) PublishTo(exchange string, routingKey string, data T) error {
+	return nil
+}
+
+func (p nopPublisher[T]) Publish(data T) error {
+	return nil
+}
+
+func (p nopPublisher[T]) Logger() log.LogRusEntry {
+	return p.logger
+}
+
+func TestObserveMetrics(t *testing.T) {
+	t.Setenv("STORAGE_BACKEND", "memory")
+	t.Setenv("PROJECTIONS_MODE", "sync")
+
+	logger := log.Get("", "test")
+	m := metrics.New()
+	deps := &di.Deps{
+		CurrLog:         logger,
+		CurrMetrics:     m,
+		CurrAVPublisher: nopPublisher[*rbschema.ArticleValidationData]{logger: logger},
+		CurrPLPublisher: nopPublisher[*rbschema.OrderPlacedData]{logger: logger},
+	}
+
+	delivery := &broker.Delivery[events.PlacedOrderData]{
+		Context: withDeps(context.Background(), deps),
+		Queue:   "order_place_order",
+		Message: &rbt.InputMessage[events.PlacedOrderData]{
+			Message: events.PlacedOrderData{
+				CartId:   uuid.NewV4().String(),
+				UserId:   uuid.NewV4().String(),
+				Articles: []events.PlacePrderArticleData{{Id: uuid.NewV4().String(), Quantity: 1}},
+			},
+		},
+	}
+
+	if err := observe(consumeOnce(processPlaceOrder))(logger, delivery); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := testutil.ToFloat64(m.MessagesConsumed.WithLabelValues("order_place_order")); got != 1 {
+		t.Errorf("consumed = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.MessagesFailed.WithLabelValues("order_place_order")); got != 0 {
+		t.Errorf("failed = %v, want 0", got)
+	}
+	if got := testutil.CollectAndCount(m.EventStoreInsert); got != 1 {
+		t.Errorf("event store insert series = %v, want 1", got)
+	}
+	if got := testutil.CollectAndCount(m.ProjectionDuration); got == 0 {
+		t.Error("projection update not observed")
+	}
+	if got := testutil.CollectAndCount(m.ProjectionFailures); got != 0 {
+		t.Errorf("projection failures series = %v, want 0", got)
+	}
+}
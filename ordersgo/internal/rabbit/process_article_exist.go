@@ -1,13 +1,13 @@
 package rabbit
 
 import (
-	"time"
+	"context"
 
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/commongo/rbt"
 	"github.com/nmarsollier/ordersgo/internal/di"
-	"github.com/nmarsollier/ordersgo/internal/env"
 	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
 )
 
 //	@Summary		Mensage Rabbit article_exist/order_article_exist
@@ -19,33 +19,35 @@ import (
 //	@Router			/rabbit/article_exist [get]
 //
 // Validar Artículos
-func listenArticleExist(logger log.LogRusEntry) {
+func listenArticleExist(ctx context.Context, logger log.LogRusEntry) {
 	for {
-		err := rbt.ConsumeRabbitEvent[events.ValidationEvent](
-			env.Get().FluentURL,
-			env.Get().RabbitURL,
-			env.Get().ServerName,
-			"article_exist",
-			"direct",
-			"order_article_exist",
-			"order_article_exist",
-			processArticleExist,
+		err := broker.Consume[events.ValidationEvent](
+			ctx,
+			broker.Consumer{
+				Exchange:    "article_exist",
+				ChannelType: "direct",
+				Queue:       "order_article_exist",
+				RoutingKey:  "order_article_exist",
+			},
+			observe(consumeOnce(processArticleExist)),
 		)
 
 		if err != nil {
 			logger.Error(err)
 		}
 		logger.Info("RabbitMQ listenLogout conectando en 5 segundos.")
-		time.Sleep(5 * time.Second)
+		if !waitReconnect(ctx) {
+			return
+		}
 	}
 }
 
-func processArticleExist(logger log.LogRusEntry, newMessage *rbt.InputMessage[events.ValidationEvent]) {
-	deps := di.NewInjector(logger)
-
+func processArticleExist(deps di.Injector, newMessage *rbt.InputMessage[events.ValidationEvent]) error {
 	_, err := deps.Service().ProcessArticleData(&newMessage.Message)
 	if err != nil {
 		deps.Logger().Error(err)
-		return
+		return err
 	}
+
+	return nil
 }
@@ -1,12 +1,12 @@
 package rabbit
 
 import (
-	"time"
+	"context"
 
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/commongo/rbt"
 	"github.com/nmarsollier/ordersgo/internal/di"
-	"github.com/nmarsollier/ordersgo/internal/env"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
 )
 
 //	@Summary		Mensage Rabbit logout
@@ -18,29 +18,30 @@ import (
 //	@Router			/rabbit/logout [get]
 //
 // Escucha de mensajes logout desde auth.
-func listenLogout(logger log.LogRusEntry) {
+func listenLogout(ctx context.Context, logger log.LogRusEntry) {
 	for {
-		err := rbt.ConsumeRabbitEvent[string](
-			env.Get().FluentURL,
-			env.Get().RabbitURL,
-			env.Get().ServerName,
-			"auth",
-			"fanout",
-			"",
-			"",
-			processLogout,
+		err := broker.Consume[string](
+			ctx,
+			broker.Consumer{
+				Exchange:    "auth",
+				ChannelType: "fanout",
+				Queue:       "",
+				RoutingKey:  "",
+			},
+			observe(consumeOnce(processLogout)),
 		)
 
 		if err != nil {
 			logger.Error(err)
 		}
 		logger.Info("RabbitMQ listenLogout conectando en 5 segundos.")
-		time.Sleep(5 * time.Second)
+		if !waitReconnect(ctx) {
+			return
+		}
 	}
 }
 
-func processLogout(logger log.LogRusEntry, newMessage *rbt.InputMessage[string]) {
-	deps := di.NewInjector(logger)
-
+func processLogout(deps di.Injector, newMessage *rbt.InputMessage[string]) error {
 	deps.SecurityService().Invalidate(newMessage.Message)
+	return nil
 }
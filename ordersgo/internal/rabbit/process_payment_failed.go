@@ -1,13 +1,13 @@
 package rabbit
 
 import (
-	"time"
+	"context"
 
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/commongo/rbt"
 	"github.com/nmarsollier/ordersgo/internal/di"
-	"github.com/nmarsollier/ordersgo/internal/env"
 	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
 )
 
 // PaymentFailedMessage estructura del mensaje de pago fallido
@@ -22,28 +22,31 @@ type PaymentFailedMessage struct {
 	ErrorCode string  `json:"errorCode"`
 }
 
-func listenPaymentFailed(logger log.LogRusEntry) {
+func listenPaymentFailed(ctx context.Context, logger log.LogRusEntry) {
 	for {
-		err := rbt.ConsumeRabbitEvent[PaymentFailedMessage](
-			env.Get().FluentURL,
-			env.Get().RabbitURL,
-			env.Get().ServerName,
-			"payments_exchange",
-			"topic",
-			"orders_payment_failed",
-			"payment.failed",
-			processPaymentFailed,
+		err := broker.Consume[PaymentFailedMessage](
+			ctx,
+			broker.Consumer{
+				Exchange:    "payments_exchange",
+				ChannelType: "topic",
+				Queue:       "orders_payment_failed",
+				RoutingKey:  "payment.failed",
+			},
+			observe(consumeOnce(processPaymentFailed)),
 		)
 
 		if err != nil {
 			logger.Error(err)
 		}
 		logger.Info("RabbitMQ listenPaymentFailed conectando en 5 segundos.")
-		time.Sleep(5 * time.Second)
+		if !waitReconnect(ctx) {
+			return
+		}
 	}
 }
 
-func processPaymentFailed(logger log.LogRusEntry, newMessage *rbt.InputMessage[PaymentFailedMessage]) {
+func processPaymentFailed(deps di.Injector, newMessage *rbt.InputMessage[PaymentFailedMessage]) error {
+	logger := deps.Logger()
 	message := newMessage.Message
 
 	logger.WithField("orderId", message.OrderID).
@@ -60,20 +63,21 @@ func processPaymentFailed(logger log.LogRusEntry, newMessage *rbt.InputMessage[P
 		Amount:        message.Amount,
 		PaymentId:     message.PaymentID,
 		TransactionId: "",
-		Status:        "rejected",
+		Status:        events.PaymentRejected,
 		ErrorMessage:  message.Reason,
 		ErrorCode:     message.ErrorCode,
 	}
 
 	// Save event and update projection
-	deps := di.NewInjector(logger)
 	if _, err := deps.Service().ProcessSavePayment(paymentEvent); err != nil {
 		logger.Error("Error saving payment event: ", err)
-		return
+		return err
 	}
 
 	logger.WithField("orderId", message.OrderID).
 		WithField("paymentId", message.PaymentID).
 		WithField("errorCode", message.ErrorCode).
 		Info("Failed payment processed successfully")
+
+	return nil
 }
@@ -1,13 +1,13 @@
 package rabbit
 
 import (
-	"time"
+	"context"
 
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/commongo/rbt"
 	"github.com/nmarsollier/ordersgo/internal/di"
-	"github.com/nmarsollier/ordersgo/internal/env"
 	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
 )
 
 // PaymentPartialMessage estructura del mensaje de pago parcial
@@ -25,28 +25,31 @@ type PaymentPartialMessage struct {
 	RemainingAmount  float32 `json:"remainingAmount"`
 }
 
-func listenPaymentPartial(logger log.LogRusEntry) {
+func listenPaymentPartial(ctx context.Context, logger log.LogRusEntry) {
 	for {
-		err := rbt.ConsumeRabbitEvent[PaymentPartialMessage](
-			env.Get().FluentURL,
-			env.Get().RabbitURL,
-			env.Get().ServerName,
-			"payments_exchange",
-			"topic",
-			"orders_payment_partial",
-			"payment.partial",
-			processPaymentPartial,
+		err := broker.Consume[PaymentPartialMessage](
+			ctx,
+			broker.Consumer{
+				Exchange:    "payments_exchange",
+				ChannelType: "topic",
+				Queue:       "orders_payment_partial",
+				RoutingKey:  "payment.partial",
+			},
+			observe(consumeOnce(processPaymentPartial)),
 		)
 
 		if err != nil {
 			logger.Error(err)
 		}
 		logger.Info("RabbitMQ listenPaymentPartial conectando en 5 segundos.")
-		time.Sleep(5 * time.Second)
+		if !waitReconnect(ctx) {
+			return
+		}
 	}
 }
 
-func processPaymentPartial(logger log.LogRusEntry, newMessage *rbt.InputMessage[PaymentPartialMessage]) {
+func processPaymentPartial(deps di.Injector, newMessage *rbt.InputMessage[PaymentPartialMessage]) error {
+	logger := deps.Logger()
 	message := newMessage.Message
 
 	logger.WithField("orderId", message.OrderID).
@@ -64,18 +67,19 @@ func processPaymentPartial(logger log.LogRusEntry, newMessage *rbt.InputMessage[
 		Amount:        message.Amount,
 		PaymentId:     message.PaymentID,
 		TransactionId: message.TransactionID,
-		Status:        "approved",
+		Status:        events.PaymentCaptured,
 	}
 
 	// Save event and update projection
-	deps := di.NewInjector(logger)
 	if _, err := deps.Service().ProcessSavePayment(paymentEvent); err != nil {
 		logger.Error("Error saving payment event: ", err)
-		return
+		return err
 	}
 
 	logger.WithField("orderId", message.OrderID).
 		WithField("paymentNumber", message.PaymentNumber).
 		WithField("totalPaidSoFar", message.TotalPaidSoFar).
 		Info("Partial payment processed successfully")
+
+	return nil
 }
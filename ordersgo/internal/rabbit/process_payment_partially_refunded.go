@@ -0,0 +1,77 @@
+package rabbit
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/commongo/rbt"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
+)
+
+// PaymentPartiallyRefundedMessage estructura del mensaje de reembolso parcial, Amount es lo reembolsado
+type PaymentPartiallyRefundedMessage struct {
+	RefundID  string  `json:"refundId"`
+	PaymentID string  `json:"paymentId"`
+	OrderID   string  `json:"orderId"`
+	UserID    string  `json:"userId"`
+	Amount    float32 `json:"amount"`
+	Currency  string  `json:"currency"`
+	Reason    string  `json:"reason"`
+}
+
+func listenPaymentPartiallyRefunded(ctx context.Context, logger log.LogRusEntry) {
+	for {
+		err := broker.Consume[PaymentPartiallyRefundedMessage](
+			ctx,
+			broker.Consumer{
+				Exchange:    "payments_exchange",
+				ChannelType: "topic",
+				Queue:       "orders_payment_partially_refunded",
+				RoutingKey:  "payment.partially_refunded",
+			},
+			observe(consumeOnce(processPaymentPartiallyRefunded)),
+		)
+
+		if err != nil {
+			logger.Error(err)
+		}
+		logger.Info("RabbitMQ listenPaymentPartiallyRefunded conectando en 5 segundos.")
+		if !waitReconnect(ctx) {
+			return
+		}
+	}
+}
+
+func processPaymentPartiallyRefunded(deps di.Injector, newMessage *rbt.InputMessage[PaymentPartiallyRefundedMessage]) error {
+	logger := deps.Logger()
+	message := newMessage.Message
+
+	logger.WithField("orderId", message.OrderID).
+		WithField("paymentId", message.PaymentID).
+		WithField("refundId", message.RefundID).
+		WithField("amount", message.Amount).
+		Info("Processing payment.partially_refunded")
+
+	// Refund event referencing the original payment
+	refundEvent := &events.RefundEvent{
+		OrderId:   message.OrderID,
+		PaymentId: message.PaymentID,
+		RefundId:  message.RefundID,
+		Amount:    message.Amount,
+		Reason:    message.Reason,
+	}
+
+	// Save event and update projection
+	if _, err := deps.Service().ProcessSaveRefund(refundEvent); err != nil {
+		logger.Error("Error saving refund event: ", err)
+		return err
+	}
+
+	logger.WithField("orderId", message.OrderID).
+		WithField("paymentId", message.PaymentID).
+		Info("Partial refund processed successfully")
+
+	return nil
+}
@@ -1,13 +1,13 @@
 package rabbit
 
 import (
-	"time"
+	"context"
 
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/commongo/rbt"
 	"github.com/nmarsollier/ordersgo/internal/di"
-	"github.com/nmarsollier/ordersgo/internal/env"
 	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
 )
 
 // PaymentRefundedMessage estructura del mensaje de reembolso
@@ -22,28 +22,31 @@ type PaymentRefundedMessage struct {
 	Reason        string  `json:"reason"`
 }
 
-func listenPaymentRefunded(logger log.LogRusEntry) {
+func listenPaymentRefunded(ctx context.Context, logger log.LogRusEntry) {
 	for {
-		err := rbt.ConsumeRabbitEvent[PaymentRefundedMessage](
-			env.Get().FluentURL,
-			env.Get().RabbitURL,
-			env.Get().ServerName,
-			"payments_exchange",
-			"topic",
-			"orders_payment_refunded",
-			"payment.refunded",
-			processPaymentRefunded,
+		err := broker.Consume[PaymentRefundedMessage](
+			ctx,
+			broker.Consumer{
+				Exchange:    "payments_exchange",
+				ChannelType: "topic",
+				Queue:       "orders_payment_refunded",
+				RoutingKey:  "payment.refunded",
+			},
+			observe(consumeOnce(processPaymentRefunded)),
 		)
 
 		if err != nil {
 			logger.Error(err)
 		}
 		logger.Info("RabbitMQ listenPaymentRefunded conectando en 5 segundos.")
-		time.Sleep(5 * time.Second)
+		if !waitReconnect(ctx) {
+			return
+		}
 	}
 }
 
-func processPaymentRefunded(logger log.LogRusEntry, newMessage *rbt.InputMessage[PaymentRefundedMessage]) {
+func processPaymentRefunded(deps di.Injector, newMessage *rbt.InputMessage[PaymentRefundedMessage]) error {
+	logger := deps.Logger()
 	message := newMessage.Message
 
 	logger.WithField("orderId", message.OrderID).
@@ -52,24 +55,23 @@ func processPaymentRefunded(logger log.LogRusEntry, newMessage *rbt.InputMessage
 		WithField("reason", message.Reason).
 		Info("Processing payment.refunded")
 
-	// Create payment event with refunded status
-	paymentEvent := &events.PaymentEvent{
-		OrderId:       message.OrderID,
-		Method:        message.Method,
-		Amount:        message.Amount,
-		PaymentId:     message.PaymentID,
-		TransactionId: message.TransactionID,
-		Status:        "refunded",
+	// Refund event referencing the original payment
+	refundEvent := &events.RefundEvent{
+		OrderId:   message.OrderID,
+		PaymentId: message.PaymentID,
+		Amount:    message.Amount,
+		Reason:    message.Reason,
 	}
 
 	// Save event and update projection
-	deps := di.NewInjector(logger)
-	if _, err := deps.Service().ProcessSavePayment(paymentEvent); err != nil {
-		logger.Error("Error saving payment event: ", err)
-		return
+	if _, err := deps.Service().ProcessSaveRefund(refundEvent); err != nil {
+		logger.Error("Error saving refund event: ", err)
+		return err
 	}
 
 	logger.WithField("orderId", message.OrderID).
 		WithField("paymentId", message.PaymentID).
 		Info("Refunded payment processed successfully")
+
+	return nil
 }
@@ -1,13 +1,13 @@
 package rabbit
 
 import (
-	"time"
+	"context"
 
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/commongo/rbt"
 	"github.com/nmarsollier/ordersgo/internal/di"
-	"github.com/nmarsollier/ordersgo/internal/env"
 	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
 )
 
 // PaymentSuccessMessage estructura del mensaje de pago exitoso
@@ -21,28 +21,31 @@ type PaymentSuccessMessage struct {
 	TransactionID string  `json:"transactionId"`
 }
 
-func listenPaymentSuccess(logger log.LogRusEntry) {
+func listenPaymentSuccess(ctx context.Context, logger log.LogRusEntry) {
 	for {
-		err := rbt.ConsumeRabbitEvent[PaymentSuccessMessage](
-			env.Get().FluentURL,
-			env.Get().RabbitURL,
-			env.Get().ServerName,
-			"payments_exchange",
-			"topic",
-			"orders_payment_success",
-			"payment.success",
-			processPaymentSuccess,
+		err := broker.Consume[PaymentSuccessMessage](
+			ctx,
+			broker.Consumer{
+				Exchange:    "payments_exchange",
+				ChannelType: "topic",
+				Queue:       "orders_payment_success",
+				RoutingKey:  "payment.success",
+			},
+			observe(consumeOnce(processPaymentSuccess)),
 		)
 
 		if err != nil {
 			logger.Error(err)
 		}
 		logger.Info("RabbitMQ listenPaymentSuccess conectando en 5 segundos.")
-		time.Sleep(5 * time.Second)
+		if !waitReconnect(ctx) {
+			return
+		}
 	}
 }
 
-func processPaymentSuccess(logger log.LogRusEntry, newMessage *rbt.InputMessage[PaymentSuccessMessage]) {
+func processPaymentSuccess(deps di.Injector, newMessage *rbt.InputMessage[PaymentSuccessMessage]) error {
+	logger := deps.Logger()
 	message := newMessage.Message
 
 	logger.WithField("orderId", message.OrderID).
@@ -57,15 +60,16 @@ func processPaymentSuccess(logger log.LogRusEntry, newMessage *rbt.InputMessage[
 		Amount:        message.Amount,
 		PaymentId:     message.PaymentID,
 		TransactionId: message.TransactionID,
-		Status:        "approved",
+		Status:        events.PaymentCaptured,
 	}
 
 	// Save event and update projection
-	deps := di.NewInjector(logger)
 	if _, err := deps.Service().ProcessSavePayment(paymentEvent); err != nil {
 		logger.Error("Error saving payment event: ", err)
-		return
+		return err
 	}
 
 	logger.WithField("orderId", message.OrderID).Info("Payment success processed successfully")
+
+	return nil
 }
@@ -0,0 +1,73 @@
+package rabbit
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/commongo/rbt"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// PaymentTransitionMessage estructura de los mensajes payment.<estado> del ciclo de vida del pago
+type PaymentTransitionMessage struct {
+	PaymentID     string  `json:"paymentId"`
+	OrderID       string  `json:"orderId"`
+	UserID        string  `json:"userId"`
+	Amount        float32 `json:"amount"`
+	Currency      string  `json:"currency"`
+	Method        string  `json:"method"`
+	TransactionID string  `json:"transactionId"`
+	Reason        string  `json:"reason"`
+	ErrorCode     string  `json:"errorCode"`
+}
+
+// paymentTransitions estados con routing key propia. payment.success, payment.partial, payment.failed
+// y los reembolsos tienen sus propios consumers.
+var paymentTransitions = []events.PaymentStatus{
+	events.PaymentPending,
+	events.PaymentAuthorized,
+	events.PaymentCaptured,
+	events.PaymentRejected,
+	events.PaymentChargeback,
+}
+
+// listenPaymentTransition escucha payment.<status> en la cola orders_payment_<status>
+func listenPaymentTransition(status events.PaymentStatus) func(context.Context, log.LogRusEntry) {
+	return listenTransition("payments_exchange", "payment", status, processPaymentTransition)
+}
+
+func processPaymentTransition(status events.PaymentStatus) func(di.Injector, *rbt.InputMessage[PaymentTransitionMessage]) error {
+	return func(deps di.Injector, newMessage *rbt.InputMessage[PaymentTransitionMessage]) error {
+		logger := deps.Logger()
+		message := newMessage.Message
+
+		logger.WithField("orderId", message.OrderID).
+			WithField("paymentId", message.PaymentID).
+			WithField("amount", message.Amount).
+			Info("Processing payment." + string(status))
+
+		paymentEvent := &events.PaymentEvent{
+			OrderId:       message.OrderID,
+			Method:        message.Method,
+			Amount:        message.Amount,
+			PaymentId:     message.PaymentID,
+			TransactionId: message.TransactionID,
+			Status:        status,
+			ErrorMessage:  message.Reason,
+			ErrorCode:     message.ErrorCode,
+		}
+
+		// Save event and update projection
+		if _, err := deps.Service().ProcessSavePayment(paymentEvent); err != nil {
+			logger.Error("Error saving payment event: ", err)
+			return err
+		}
+
+		logger.WithField("orderId", message.OrderID).
+			WithField("paymentId", message.PaymentID).
+			Info("Payment " + string(status) + " processed successfully")
+
+		return nil
+	}
+}
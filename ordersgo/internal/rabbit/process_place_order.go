@@ -1,13 +1,14 @@
 package rabbit
 
 import (
-	"time"
+	"context"
 
+	"github.com/nmarsollier/commongo/errs"
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/commongo/rbt"
 	"github.com/nmarsollier/ordersgo/internal/di"
-	"github.com/nmarsollier/ordersgo/internal/env"
 	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
 )
 
 //	@Summary		Mensage Rabbit place_order/order_place_order
@@ -19,35 +20,41 @@ import (
 //	@Router			/rabbit/place_order [get]
 //
 // Validar Artículos
-func listenPlaceOrder(logger log.LogRusEntry) {
+func listenPlaceOrder(ctx context.Context, logger log.LogRusEntry) {
 	logger.Info("Iniciando consumer de place_order...")
 	for {
 		logger.Info("Intentando conectar a RabbitMQ para place_order...")
-		err := rbt.ConsumeRabbitEvent[events.PlacedOrderData](
-			env.Get().FluentURL,
-			env.Get().RabbitURL,
-			env.Get().ServerName,
-			"place_order",
-			"direct",
-			"order_place_order",
-			"place_order",
-			processPlaceOrder,
+		err := broker.Consume[events.PlacedOrderData](
+			ctx,
+			broker.Consumer{
+				Exchange:    "place_order",
+				ChannelType: "direct",
+				Queue:       "order_place_order",
+				RoutingKey:  "place_order",
+			},
+			observe(consumeOnce(processPlaceOrder)),
 		)
 
 		if err != nil {
 			logger.Error(err)
 		}
 		logger.Info("RabbitMQ place_order reconectando en 5 segundos.")
-		time.Sleep(5 * time.Second)
+		if !waitReconnect(ctx) {
+			return
+		}
 	}
 }
 
-func processPlaceOrder(logger log.LogRusEntry, newMessage *rbt.InputMessage[events.PlacedOrderData]) {
-	deps := di.NewInjector(logger)
-
+func processPlaceOrder(deps di.Injector, newMessage *rbt.InputMessage[events.PlacedOrderData]) error {
 	_, err := deps.Service().PocessPlaceOrder(&newMessage.Message)
+	if err == errs.AlreadyExist {
+		// El carrito ya generó una orden, es una reentrega
+		return nil
+	}
 	if err != nil {
 		deps.Logger().Error(err)
-		return
+		return err
 	}
+
+	return nil
 }
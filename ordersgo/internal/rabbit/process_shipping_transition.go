@@ -0,0 +1,59 @@
+package rabbit
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/commongo/rbt"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// ShippingTransitionMessage estructura de los mensajes shipping.<estado> del servicio de envíos
+type ShippingTransitionMessage struct {
+	OrderID        string `json:"orderId"`
+	Carrier        string `json:"carrier"`
+	TrackingNumber string `json:"trackingNumber"`
+	Reason         string `json:"reason"`
+}
+
+// shippingTransitions estados del envío, cada uno con su routing key
+var shippingTransitions = []events.FulfillmentStatus{
+	events.FulfillmentPacking,
+	events.FulfillmentShipped,
+	events.FulfillmentDelivered,
+	events.FulfillmentDeliveryFailed,
+}
+
+// listenShippingTransition escucha shipping.<status> en la cola orders_shipping_<status>
+func listenShippingTransition(status events.FulfillmentStatus) func(context.Context, log.LogRusEntry) {
+	return listenTransition("shipping_exchange", "shipping", status, processShippingTransition)
+}
+
+func processShippingTransition(status events.FulfillmentStatus) func(di.Injector, *rbt.InputMessage[ShippingTransitionMessage]) error {
+	return func(deps di.Injector, newMessage *rbt.InputMessage[ShippingTransitionMessage]) error {
+		logger := deps.Logger()
+		message := newMessage.Message
+
+		logger.WithField("orderId", message.OrderID).
+			WithField("trackingNumber", message.TrackingNumber).
+			Info("Processing shipping." + string(status))
+
+		fulfillmentEvent := &events.FulfillmentEvent{
+			Status:         status,
+			Carrier:        message.Carrier,
+			TrackingNumber: message.TrackingNumber,
+			Reason:         message.Reason,
+		}
+
+		if _, err := deps.Service().ProcessFulfillment(message.OrderID, fulfillmentEvent); err != nil {
+			logger.Error("Error saving fulfillment event: ", err)
+			return err
+		}
+
+		logger.WithField("orderId", message.OrderID).
+			Info("Shipping " + string(status) + " processed successfully")
+
+		return nil
+	}
+}
@@ -3,9 +3,10 @@ package rabbit
 import (
 	"time"
 
-	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/commongo/rbt"
+	"github.com/nmarsollier/ordersgo/internal/di"
 	"github.com/nmarsollier/ordersgo/internal/env"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/broker"
 )
 
 // OrderCanceledMessage estructura del evento order.canceled
@@ -17,7 +18,9 @@ type OrderCanceledMessage struct {
 }
 
 // PublishOrderCanceled publica un evento de orden cancelada al exchange order_events
-func PublishOrderCanceled(logger log.LogRusEntry, orderId, userId, reason string) error {
+func PublishOrderCanceled(deps di.Injector, orderId, userId, reason string) error {
+	logger := deps.Logger()
+
 	message := &OrderCanceledMessage{
 		OrderID:    orderId,
 		UserID:     userId,
@@ -30,23 +33,22 @@ func PublishOrderCanceled(logger log.LogRusEntry, orderId, userId, reason string
 		WithField("reason", reason).
 		Info("Publishing order.canceled event")
 
-	// Crear publisher para payments_exchange (usando exchange existente)
-	publisher, err := rbt.NewRabbitPublisher[*OrderCanceledMessage](
+	// Publisher sobre payments_exchange (usando exchange existente)
+	publisher := broker.NewPublisher[*OrderCanceledMessage](
+		deps.Context(),
 		rbt.RbtLogger(env.Get().FluentURL, env.Get().ServerName, logger.CorrelationId()),
-		env.Get().RabbitURL,
+		deps.Metrics(),
 		"payments_exchange",
 		"topic",
 		"order.canceled",
 	)
 
-	if err != nil {
-		logger.Error("Error creating publisher: ", err)
+	// Publicar mensaje
+	if err := publisher.Publish(message); err != nil {
+		logger.Error("Error publishing order.canceled: ", err)
 		return err
 	}
 
-	// Publicar mensaje
-	publisher.Publish(message)
-
 	logger.Info("order.canceled event published successfully")
 	return nil
 }
@@ -6,6 +6,8 @@ type PlacedDataPublisher = rbt.RabbitPublisher[*OrderPlacedData]
 
 type ArticleValidationPublisher = rbt.RabbitPublisher[*ArticleValidationData]
 
+type RefundRequestPublisher = rbt.RabbitPublisher[*RefundRequestData]
+
 type OrderPlacedData struct {
 	OrderId string `json:"orderId"`
 
@@ -14,6 +16,8 @@ type OrderPlacedData struct {
 	UserId string `json:"userId"`
 
 	Articles []ArticlePlacedData `json:"articles"`
+
+	Delivery *DeliveryPlacedData `json:"delivery,omitempty"`
 }
 
 type ArticlePlacedData struct {
@@ -22,8 +26,56 @@ type ArticlePlacedData struct {
 	Quantity int `json:"quantity"`
 }
 
+type DeliveryPlacedData struct {
+	ShippingAddress *AddressPlacedData `json:"shippingAddress,omitempty"`
+
+	BillingAddress *AddressPlacedData `json:"billingAddress,omitempty"`
+
+	ContactPhone string `json:"contactPhone,omitempty"`
+
+	Instructions string `json:"instructions,omitempty"`
+}
+
+type AddressPlacedData struct {
+	Line1 string `json:"line1"`
+
+	Line2 string `json:"line2,omitempty"`
+
+	City string `json:"city"`
+
+	State string `json:"state,omitempty"`
+
+	PostalCode string `json:"postalCode"`
+
+	Country string `json:"country"`
+}
+
 type ArticleValidationData struct {
 	ReferenceId string `json:"referenceId"`
 
 	ArticleId string `json:"articleId"`
 }
+
+// RefundRequestData pedido de reembolso a payments_node por una devolución recibida,
+// RefundId es el id de la devolución
+type RefundRequestData struct {
+	OrderId string `json:"orderId"`
+
+	UserId string `json:"userId"`
+
+	RefundId string `json:"refundId"`
+
+	Amount float32 `json:"amount"`
+
+	Reason string `json:"reason"`
+
+	Articles []RefundArticleData `json:"articles"`
+}
+
+type RefundArticleData struct {
+	ArticleId string `json:"articleId"`
+
+	Quantity int `json:"quantity"`
+
+	Amount float32 `json:"amount"`
+}
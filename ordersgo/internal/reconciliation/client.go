@@ -0,0 +1,79 @@
+package reconciliation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/nmarsollier/commongo/httpx"
+	"github.com/nmarsollier/commongo/log"
+)
+
+// requestTimeout de cada consulta a payments_node
+const requestTimeout = 10 * time.Second
+
+// PaymentsClient consulta los pagos en payments_node
+type PaymentsClient interface {
+	FindByOrderId(token string, orderId string) ([]*RemotePayment, error)
+}
+
+// NewPaymentsClient serverUrl es la raíz de payments_node, por ejemplo http://localhost:3005
+func NewPaymentsClient(log log.LogRusEntry, client httpx.HTTPClient, serverUrl string) PaymentsClient {
+	return &paymentsClient{
+		log:       log,
+		client:    client,
+		serverUrl: serverUrl,
+	}
+}
+
+type paymentsClient struct {
+	log       log.LogRusEntry
+	client    httpx.HTTPClient
+	serverUrl string
+}
+
+type paymentsResponse struct {
+	Payments []*RemotePayment `json:"payments"`
+}
+
+// FindByOrderId payments_node responde 404 cuando la orden no tiene pagos
+func (c *paymentsClient) FindByOrderId(token string, orderId string) ([]*RemotePayment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.serverUrl+"/api/payments/order/"+url.PathEscape(orderId), nil)
+	if err != nil {
+		c.log.Error(err)
+		return nil, err
+	}
+	req.Header.Add("Authorization", "Bearer "+token)
+	if corrId, ok := c.log.Data()[log.LOG_FIELD_CORRELATION_ID].(string); ok {
+		req.Header.Add(log.LOG_FIELD_CORRELATION_ID, corrId)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.log.Error(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return []*RemotePayment{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("payments_node responded %d for order %s", resp.StatusCode, orderId)
+		c.log.Error(err)
+		return nil, err
+	}
+
+	result := &paymentsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		c.log.Error(err)
+		return nil, err
+	}
+	return result.Payments, nil
+}
@@ -0,0 +1,108 @@
+package reconciliation
+
+import (
+	"sort"
+
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// amountTolerance diferencia máxima entre montos para considerarlos iguales
+const amountTolerance = 0.01
+
+// localPayments estado de cada pago de la orden según el event store.
+// Los pagos sin paymentId no se pueden cruzar con payments_node y se devuelven aparte.
+func localPayments(orderEvents []*events.Event) (map[string]*events.PaymentState, []*events.PaymentState) {
+	sort.SliceStable(orderEvents, func(i, j int) bool {
+		return orderEvents[i].Created.Before(orderEvents[j].Created)
+	})
+
+	byId := map[string]*events.PaymentState{}
+	unidentified := []*events.PaymentState{}
+	for _, payment := range events.FoldPayments(orderEvents) {
+		if payment.PaymentId == "" {
+			unidentified = append(unidentified, payment)
+			continue
+		}
+		byId[payment.PaymentId] = payment
+	}
+	return byId, unidentified
+}
+
+// compareOrder cruza los pagos de una orden. Un pago pendiente en payments_node todavía
+// no generó evento, no se informa como faltante. payments_node informa approved para los
+// pagos capturados y no distingue los reembolsos parciales.
+func compareOrder(orderId string, orderEvents []*events.Event, remote []*RemotePayment) []*Discrepancy {
+	local, unidentified := localPayments(orderEvents)
+	result := []*Discrepancy{}
+
+	seen := map[string]bool{}
+	for _, payment := range remote {
+		seen[payment.ID] = true
+		current, ok := local[payment.ID]
+		if !ok {
+			if payment.Status == "pending" {
+				continue
+			}
+			result = append(result, &Discrepancy{
+				Type:         Missing,
+				OrderId:      orderId,
+				PaymentId:    payment.ID,
+				RemoteAmount: payment.Amount,
+				RemoteStatus: payment.Status,
+			})
+			continue
+		}
+
+		if diff := current.Amount - payment.Amount; diff > amountTolerance || diff < -amountTolerance {
+			result = append(result, &Discrepancy{
+				Type:         AmountMismatch,
+				OrderId:      orderId,
+				PaymentId:    payment.ID,
+				LocalAmount:  current.Amount,
+				RemoteAmount: payment.Amount,
+			})
+		}
+		if !sameStatus(current.Status, events.PaymentStatus(payment.Status)) {
+			result = append(result, &Discrepancy{
+				Type:         StatusMismatch,
+				OrderId:      orderId,
+				PaymentId:    payment.ID,
+				LocalStatus:  string(current.Status),
+				RemoteStatus: payment.Status,
+			})
+		}
+	}
+
+	ids := make([]string, 0, len(local))
+	for id := range local {
+		if !seen[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		result = append(result, extra(orderId, local[id]))
+	}
+	for _, payment := range unidentified {
+		result = append(result, extra(orderId, payment))
+	}
+
+	return result
+}
+
+func sameStatus(local events.PaymentStatus, remote events.PaymentStatus) bool {
+	if local == events.PaymentPartiallyRefunded {
+		return remote.Normalize() == events.PaymentCaptured
+	}
+	return local == remote.Normalize()
+}
+
+func extra(orderId string, payment *events.PaymentState) *Discrepancy {
+	return &Discrepancy{
+		Type:        Extra,
+		OrderId:     orderId,
+		PaymentId:   payment.PaymentId,
+		LocalAmount: payment.Amount,
+		LocalStatus: string(payment.Status),
+	}
+}
@@ -0,0 +1,63 @@
+package reconciliation
+
+import (
+	"sort"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewMemoryReportRepository reportes en memoria, la tabla se comparte entre requests
+func NewMemoryReportRepository(log log.LogRusEntry, table *memdb.Table[Report]) ReportRepository {
+	return &memoryReportRepository{
+		log:   log,
+		table: table,
+	}
+}
+
+type memoryReportRepository struct {
+	log   log.LogRusEntry
+	table *memdb.Table[Report]
+}
+
+func (r *memoryReportRepository) Insert(report *Report) (*Report, error) {
+	if err := report.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	err := r.table.Upsert(report, func(current *Report) bool {
+		return current.ID == report.ID
+	})
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (r *memoryReportRepository) FindById(id string) (*Report, error) {
+	_id, _ := primitive.ObjectIDFromHex(id)
+	return r.table.FindOne(func(report *Report) bool {
+		return !_id.IsZero() && report.ID == _id
+	})
+}
+
+func (r *memoryReportRepository) FindAll() ([]*Report, error) {
+	result, err := r.table.Find(func(*Report) bool {
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Created.After(result[j].Created)
+	})
+	for _, report := range result {
+		report.Discrepancies = nil
+	}
+	return result, nil
+}
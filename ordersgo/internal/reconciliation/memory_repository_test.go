@@ -0,0 +1,14 @@
+package reconciliation_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/reconciliation"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestMemoryReport(t *testing.T) {
+	repotest.ReportRepository(t, reconciliation.NewMemoryReportRepository(log.Get("", "test"), memdb.NewTable[reconciliation.Report]()))
+}
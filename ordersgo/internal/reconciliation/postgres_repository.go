@@ -0,0 +1,127 @@
+package reconciliation
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewPostgresReportRepository reportes sobre la tabla reconciliation_reports
+func NewPostgresReportRepository(log log.LogRusEntry, db pgdb.DB) ReportRepository {
+	return &postgresReportRepository{
+		log: log,
+		db:  db,
+	}
+}
+
+type postgresReportRepository struct {
+	log log.LogRusEntry
+	db  pgdb.DB
+}
+
+func (r *postgresReportRepository) Insert(report *Report) (*Report, error) {
+	if err := report.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	discrepancies, err := json.Marshal(report.Discrepancies)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	_, err = r.db.Exec(context.Background(), `
+		INSERT INTO reconciliation_reports
+			(id, range_from, range_to, status, error, orders, payments, discrepancies, created, updated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			error = EXCLUDED.error,
+			orders = EXCLUDED.orders,
+			payments = EXCLUDED.payments,
+			discrepancies = EXCLUDED.discrepancies,
+			updated = EXCLUDED.updated`,
+		report.ID.Hex(), report.From, report.To, report.Status, report.Error,
+		report.Orders, report.Payments, discrepancies, report.Created, report.Updated,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (r *postgresReportRepository) FindById(id string) (*Report, error) {
+	rows, err := r.db.Query(context.Background(), `
+		SELECT id, range_from, range_to, status, error, orders, payments, discrepancies, created, updated
+		FROM reconciliation_reports WHERE id = $1`, id,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	report, err := pgx.CollectOneRow(rows, scanReport)
+	if err == pgx.ErrNoRows {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (r *postgresReportRepository) FindAll() ([]*Report, error) {
+	rows, err := r.db.Query(context.Background(), `
+		SELECT id, range_from, range_to, status, error, orders, payments, 'null'::jsonb, created, updated
+		FROM reconciliation_reports ORDER BY created DESC`,
+	)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	result, err := pgx.CollectRows(rows, scanReport)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func scanReport(row pgx.CollectableRow) (*Report, error) {
+	var id string
+	var discrepancies []byte
+	var from, to, created, updated time.Time
+	report := &Report{}
+
+	err := row.Scan(&id, &from, &to, &report.Status, &report.Error,
+		&report.Orders, &report.Payments, &discrepancies, &created, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(discrepancies, &report.Discrepancies); err != nil {
+		return nil, err
+	}
+
+	report.From = from.UTC()
+	report.To = to.UTC()
+	report.Created = created.UTC()
+	report.Updated = updated.UTC()
+	return report, nil
+}
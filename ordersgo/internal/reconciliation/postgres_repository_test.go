@@ -0,0 +1,13 @@
+package reconciliation_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/reconciliation"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+func TestPostgresReport(t *testing.T) {
+	repotest.ReportRepository(t, reconciliation.NewPostgresReportRepository(log.Get("", "test"), repotest.Postgres(t)))
+}
@@ -0,0 +1,92 @@
+package reconciliation
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/db"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReportRepository interface {
+	// Insert crea o reemplaza el reporte por id
+	Insert(report *Report) (*Report, error)
+	FindById(id string) (*Report, error)
+	// FindAll reportes del más reciente al más antiguo, sin el detalle de discrepancias
+	FindAll() ([]*Report, error)
+}
+
+// NewReportRepository raw es la misma colección, se usa para las operaciones que db.Collection no soporta
+func NewReportRepository(log log.LogRusEntry, collection db.Collection, raw *mongo.Collection) ReportRepository {
+	return &reportRepository{
+		log:        log,
+		collection: collection,
+		raw:        raw,
+	}
+}
+
+type reportRepository struct {
+	log        log.LogRusEntry
+	collection db.Collection
+	raw        *mongo.Collection
+}
+
+func (r *reportRepository) Insert(report *Report) (*Report, error) {
+	if err := report.ValidateSchema(); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+
+	filter := bson.M{"_id": report.ID}
+	if _, err := r.raw.ReplaceOne(context.Background(), filter, report, options.Replace().SetUpsert(true)); err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *reportRepository) FindById(id string) (*Report, error) {
+	_id, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.NotFound
+	}
+
+	report := &Report{}
+	if err := r.collection.FindOne(context.Background(), bson.M{"_id": _id}, report); err != nil {
+		if err.Error() == "mongo: no documents in result" {
+			return nil, errs.NotFound
+		}
+		r.log.Error(err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (r *reportRepository) FindAll() ([]*Report, error) {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created", Value: -1}}).
+		SetProjection(bson.M{"discrepancies": 0})
+	cur, err := r.raw.Find(context.Background(), bson.M{}, findOptions)
+	if err != nil {
+		r.log.Error(err)
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	result := []*Report{}
+	for cur.Next(context.Background()) {
+		report := &Report{}
+		if err := cur.Decode(report); err != nil {
+			r.log.Error(err)
+			return nil, err
+		}
+		result = append(result, report)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,16 @@
+package reconciliation_test
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/reconciliation"
+	"github.com/nmarsollier/ordersgo/internal/repotest"
+)
+
+// TestMongoReport corre contra MONGO_URL con las colecciones e índices del injector
+func TestMongoReport(t *testing.T) {
+	deps := &di.Deps{CurrLog: log.Get("", "test"), CurrDatabase: repotest.Mongo(t)}
+	repotest.ReportRepository(t, reconciliation.NewReportRepository(deps.Logger(), deps.ReportsCollection(), deps.Database().Collection("reconciliation_reports")))
+}
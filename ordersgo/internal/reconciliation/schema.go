@@ -0,0 +1,68 @@
+package reconciliation
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReportStatus string
+
+const (
+	Running   ReportStatus = "running"
+	Completed ReportStatus = "completed"
+	Failed    ReportStatus = "failed"
+)
+
+type DiscrepancyType string
+
+const (
+	// Missing el pago está en payments_node y no en ordersgo
+	Missing DiscrepancyType = "missing"
+	// Extra el pago está en ordersgo y no en payments_node
+	Extra DiscrepancyType = "extra"
+	// AmountMismatch el pago está en ambos con distinto monto
+	AmountMismatch DiscrepancyType = "amount_mismatch"
+	// StatusMismatch el pago está en ambos con distinto estado
+	StatusMismatch DiscrepancyType = "status_mismatch"
+)
+
+// Report resultado de conciliar los pagos de las ordenes del rango From (incluido) To (excluido)
+type Report struct {
+	ID            primitive.ObjectID `bson:"_id" json:"id"`
+	From          time.Time          `bson:"from" json:"from"`
+	To            time.Time          `bson:"to" json:"to"`
+	Status        ReportStatus       `bson:"status" json:"status" validate:"required"`
+	Error         string             `bson:"error,omitempty" json:"error,omitempty"`
+	Orders        int                `bson:"orders" json:"orders"`
+	Payments      int                `bson:"payments" json:"payments"`
+	Discrepancies []*Discrepancy     `bson:"discrepancies" json:"discrepancies"`
+	Created       time.Time          `bson:"created" json:"created"`
+	Updated       time.Time          `bson:"updated" json:"updated"`
+}
+
+type Discrepancy struct {
+	Type         DiscrepancyType `bson:"type" json:"type"`
+	OrderId      string          `bson:"orderId" json:"orderId"`
+	PaymentId    string          `bson:"paymentId" json:"paymentId"`
+	LocalAmount  float32         `bson:"localAmount,omitempty" json:"localAmount,omitempty"`
+	RemoteAmount float32         `bson:"remoteAmount,omitempty" json:"remoteAmount,omitempty"`
+	LocalStatus  string          `bson:"localStatus,omitempty" json:"localStatus,omitempty"`
+	RemoteStatus string          `bson:"remoteStatus,omitempty" json:"remoteStatus,omitempty"`
+}
+
+// ValidateSchema valida la estructura para ser insertada en la db
+func (e *Report) ValidateSchema() error {
+	return validator.New().Struct(e)
+}
+
+// RemotePayment pago como lo devuelve payments_node
+type RemotePayment struct {
+	ID        string  `json:"id"`
+	OrderId   string  `json:"orderId"`
+	Amount    float32 `json:"amount"`
+	Method    string  `json:"method"`
+	Status    string  `json:"status"`
+	ErrorCode string  `json:"errorCode"`
+}
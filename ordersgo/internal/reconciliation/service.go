@@ -0,0 +1,149 @@
+package reconciliation
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxRange rango máximo de una conciliación, cada orden es una consulta a payments_node
+const maxRange = 93 * 24 * time.Hour
+
+// ReconciliationService concilia los pagos del event store con los de payments_node
+type ReconciliationService interface {
+	// Start valida el rango y guarda el reporte en estado running
+	Start(from time.Time, to time.Time) (*Report, error)
+	// Run concilia el reporte iniciado con Start, token se reenvía a payments_node
+	Run(report *Report, token string) (*Report, error)
+	FindById(id string) (*Report, error)
+	FindAll() ([]*Report, error)
+}
+
+func NewReconciliationService(
+	log log.LogRusEntry,
+	client PaymentsClient,
+	repository ReportRepository,
+	status status.StatusService,
+	events events.EventService,
+) ReconciliationService {
+	return &reconciliationService{
+		log:        log,
+		client:     client,
+		repository: repository,
+		status:     status,
+		events:     events,
+	}
+}
+
+type reconciliationService struct {
+	log        log.LogRusEntry
+	client     PaymentsClient
+	repository ReportRepository
+	status     status.StatusService
+	events     events.EventService
+}
+
+func (s *reconciliationService) Start(from time.Time, to time.Time) (*Report, error) {
+	if !from.Before(to) {
+		return nil, errs.NewValidation().Add("from", "must be before to")
+	}
+	if to.Sub(from) > maxRange {
+		return nil, errs.NewValidation().Add("to", "range too large")
+	}
+
+	now := time.Now().UTC()
+	return s.repository.Insert(&Report{
+		ID:            primitive.NewObjectID(),
+		From:          from.UTC(),
+		To:            to.UTC(),
+		Status:        Running,
+		Discrepancies: []*Discrepancy{},
+		Created:       now,
+		Updated:       now,
+	})
+}
+
+func (s *reconciliationService) Run(report *Report, token string) (*Report, error) {
+	if err := s.reconcile(report, token); err != nil {
+		report.Status = Failed
+		report.Error = err.Error()
+	} else {
+		report.Status = Completed
+	}
+	report.Updated = time.Now().UTC()
+
+	return s.repository.Insert(report)
+}
+
+// reconcile las ordenes son las colocadas en el rango más las que recibieron pagos en el rango
+func (s *reconciliationService) reconcile(report *Report, token string) error {
+	orderIds, err := s.orderIds(report.From, report.To)
+	if err != nil {
+		return err
+	}
+
+	report.Orders = 0
+	report.Payments = 0
+	report.Discrepancies = []*Discrepancy{}
+	for _, orderId := range orderIds {
+		if lifecycle.IsStopping() {
+			return errors.New("reconciliation interrupted by shutdown")
+		}
+
+		orderEvents, err := s.events.FindByOrderId(orderId)
+		if err != nil {
+			return err
+		}
+		remote, err := s.client.FindByOrderId(token, orderId)
+		if err != nil {
+			return err
+		}
+
+		report.Orders++
+		report.Payments += len(remote)
+		report.Discrepancies = append(report.Discrepancies, compareOrder(orderId, orderEvents, remote)...)
+	}
+
+	return nil
+}
+
+func (s *reconciliationService) orderIds(from time.Time, to time.Time) ([]string, error) {
+	placed, err := s.status.FindByCreated(from, to)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.events.FindPayments(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	for _, order := range placed {
+		ids[order.OrderId] = true
+	}
+	for _, e := range payments {
+		ids[e.OrderId] = true
+	}
+
+	result := make([]string, 0, len(ids))
+	for id := range ids {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func (s *reconciliationService) FindById(id string) (*Report, error) {
+	return s.repository.FindById(id)
+}
+
+func (s *reconciliationService) FindAll() ([]*Report, error) {
+	return s.repository.FindAll()
+}
@@ -0,0 +1,135 @@
+package reconciliation_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/memdb"
+	"github.com/nmarsollier/ordersgo/internal/metrics"
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+	"github.com/nmarsollier/ordersgo/internal/reconciliation"
+	uuid "github.com/satori/go.uuid"
+)
+
+// payments_node simulado, responde los pagos de cada orden y 404 si la orden no tiene pagos
+func newPaymentsServer(t *testing.T, payments map[string][]*reconciliation.RemotePayment) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("authorization = %q", r.Header.Get("Authorization"))
+		}
+
+		orderId := strings.TrimPrefix(r.URL.Path, "/api/payments/order/")
+		remote, ok := payments[orderId]
+		if !ok || r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"payments": remote})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestReconciliationRun(t *testing.T) {
+	logger := log.Get("", "test")
+	eventService := events.NewEventService(logger, metrics.New(), events.NewMemoryEventsRepository(logger, memdb.NewTable[events.Event]()))
+	statusService := status.NewStatusService(logger, status.NewMemoryStatusRepository(logger, memdb.NewTable[status.OrderStatus]()), 0)
+	reportRepository := reconciliation.NewMemoryReportRepository(logger, memdb.NewTable[reconciliation.Report]())
+
+	// placeOrder coloca una orden con los pagos capturados indicados y actualiza su línea de tiempo
+	placeOrder := func(payments map[string]float32) string {
+		placed, err := eventService.SavePlaceOrder(&events.PlacedOrderData{
+			CartId:   uuid.NewV4().String(),
+			UserId:   "user",
+			Articles: []events.PlacePrderArticleData{{Id: "article", Quantity: 1}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for paymentId, amount := range payments {
+			_, err := eventService.SavePayment(&events.PaymentEvent{
+				OrderId:   placed.OrderId,
+				Method:    "CREDIT",
+				Amount:    amount,
+				PaymentId: paymentId,
+				Status:    events.PaymentCaptured,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		orderEvents, err := eventService.FindByOrderId(placed.OrderId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := statusService.Update(placed.OrderId, orderEvents); err != nil {
+			t.Fatal(err)
+		}
+		return placed.OrderId
+	}
+
+	missing := placeOrder(nil)
+	extra := placeOrder(map[string]float32{"pay-extra": 10})
+	amount := placeOrder(map[string]float32{"pay-amount": 100})
+	state := placeOrder(map[string]float32{"pay-status": 50})
+	matching := placeOrder(map[string]float32{"pay-ok": 20})
+
+	server := newPaymentsServer(t, map[string][]*reconciliation.RemotePayment{
+		missing:  {{ID: "pay-missing", OrderId: missing, Amount: 30, Status: "approved"}},
+		amount:   {{ID: "pay-amount", OrderId: amount, Amount: 90, Status: "approved"}},
+		state:    {{ID: "pay-status", OrderId: state, Amount: 50, Status: "rejected"}},
+		matching: {{ID: "pay-ok", OrderId: matching, Amount: 20, Status: "approved"}},
+	})
+
+	service := reconciliation.NewReconciliationService(
+		logger,
+		reconciliation.NewPaymentsClient(logger, server.Client(), server.URL),
+		reportRepository,
+		statusService,
+		eventService,
+	)
+
+	report, err := service.Start(time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Run(report, "token"); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := reportRepository.FindById(report.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Status != reconciliation.Completed || saved.Error != "" {
+		t.Fatalf("status = %s, error = %q", saved.Status, saved.Error)
+	}
+	if saved.Orders != 5 || saved.Payments != 4 {
+		t.Errorf("orders = %d, payments = %d, want 5 and 4", saved.Orders, saved.Payments)
+	}
+
+	want := map[string]reconciliation.Discrepancy{
+		missing: {Type: reconciliation.Missing, OrderId: missing, PaymentId: "pay-missing", RemoteAmount: 30, RemoteStatus: "approved"},
+		extra:   {Type: reconciliation.Extra, OrderId: extra, PaymentId: "pay-extra", LocalAmount: 10, LocalStatus: "captured"},
+		amount:  {Type: reconciliation.AmountMismatch, OrderId: amount, PaymentId: "pay-amount", LocalAmount: 100, RemoteAmount: 90},
+		state:   {Type: reconciliation.StatusMismatch, OrderId: state, PaymentId: "pay-status", LocalStatus: "captured", RemoteStatus: "rejected"},
+	}
+	if len(saved.Discrepancies) != len(want) {
+		t.Fatalf("discrepancies = %d, want %d", len(saved.Discrepancies), len(want))
+	}
+	for _, got := range saved.Discrepancies {
+		if expected, ok := want[got.OrderId]; !ok || *got != expected {
+			t.Errorf("discrepancy = %+v, want %+v", got, expected)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package repotest
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/ordersgo/internal/projections/article"
+)
+
+// ArticleRepository verifica el contrato de article.ArticleRepository
+func ArticleRepository(t *testing.T, repository article.ArticleRepository) {
+	t.Run("groups by article", func(t *testing.T) {
+		articleId := newId()
+		paid := newOrderArticles(&article.ArticleLine{
+			ArticleId: articleId, Quantity: 2, QuantityPaid: 2, Validated: 1, Revenue: 20,
+		})
+		invalid := newOrderArticles(&article.ArticleLine{
+			ArticleId: articleId, Quantity: 1, QuantityCanceled: 1, Validated: 1, Invalid: 1,
+		}, &article.ArticleLine{
+			ArticleId: newId(), Quantity: 5,
+		})
+		for _, current := range []*article.OrderArticles{paid, invalid} {
+			_, err := repository.Insert(current)
+			assertNoError(t, err)
+		}
+
+		demand, err := repository.FindByArticleId(articleId)
+		assertNoError(t, err)
+		assertEqual(t, "articleId", demand.ArticleId, articleId)
+		assertEqual(t, "orders", demand.Orders, 2)
+		assertEqual(t, "quantityOrdered", demand.QuantityOrdered, 3)
+		assertEqual(t, "quantityPaid", demand.QuantityPaid, 2)
+		assertEqual(t, "quantityCanceled", demand.QuantityCanceled, 1)
+		assertEqual(t, "validations", demand.Validations, 2)
+		assertEqual(t, "invalid", demand.Invalid, 1)
+		assertEqual(t, "revenue", demand.Revenue, float32(20))
+
+		all, err := repository.FindAll()
+		assertNoError(t, err)
+		found := 0
+		for _, current := range all {
+			if current.ArticleId == articleId || current.ArticleId == invalid.Articles[1].ArticleId {
+				found++
+			}
+		}
+		assertEqual(t, "articles", found, 2)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repository.FindByArticleId(newId())
+		assertNotFound(t, err)
+	})
+
+	t.Run("insert upserts by order id", func(t *testing.T) {
+		articleId := newId()
+		current := newOrderArticles(&article.ArticleLine{ArticleId: articleId, Quantity: 1})
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+
+		current.Articles[0].QuantityCanceled = 1
+		_, err = repository.Insert(current)
+		assertNoError(t, err)
+
+		demand, err := repository.FindByArticleId(articleId)
+		assertNoError(t, err)
+		assertEqual(t, "orders", demand.Orders, 1)
+		assertEqual(t, "quantityCanceled", demand.QuantityCanceled, 1)
+	})
+}
+
+func newOrderArticles(lines ...*article.ArticleLine) *article.OrderArticles {
+	return &article.OrderArticles{
+		OrderId:  newId(),
+		Articles: lines,
+		Updated:  now(),
+	}
+}
@@ -0,0 +1,51 @@
+package repotest
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/ordersgo/internal/projections/checkpoint"
+)
+
+// CheckpointRepository verifica el contrato de checkpoint.CheckpointRepository
+func CheckpointRepository(t *testing.T, repository checkpoint.CheckpointRepository) {
+	t.Run("save and find", func(t *testing.T) {
+		current := newCheckpoint(newId())
+		_, err := repository.Save(current)
+		assertNoError(t, err)
+
+		found, err := repository.FindByName(current.Name)
+		assertNoError(t, err)
+		assertEqual(t, "position", found.Position, current.Position)
+		assertTime(t, "updated", found.Updated, current.Updated)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repository.FindByName(newId())
+		assertNotFound(t, err)
+	})
+
+	t.Run("save replaces by name", func(t *testing.T) {
+		current := newCheckpoint(newId())
+		_, err := repository.Save(current)
+		assertNoError(t, err)
+		first, err := repository.FindByName(current.Name)
+		assertNoError(t, err)
+
+		current.Position = "next"
+		_, err = repository.Save(current)
+		assertNoError(t, err)
+
+		found, err := repository.FindByName(current.Name)
+		assertNoError(t, err)
+		assertEqual(t, "position", found.Position, "next")
+		assertEqual(t, "id", found.ID, first.ID)
+	})
+}
+
+func newCheckpoint(name string) *checkpoint.Checkpoint {
+	return &checkpoint.Checkpoint{
+		Name:     name,
+		Position: newId(),
+		Updated:  now(),
+	}
+}
@@ -0,0 +1,72 @@
+package repotest
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/ordersgo/internal/projections/customer"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+)
+
+// CustomerRepository verifica el contrato de customer.CustomerRepository
+func CustomerRepository(t *testing.T, repository customer.CustomerRepository) {
+	t.Run("insert and find by user", func(t *testing.T) {
+		userId := newId()
+		first := newOrderStats(userId)
+		second := newOrderStats(userId)
+		_, err := repository.Insert(first)
+		assertNoError(t, err)
+		_, err = repository.Insert(second)
+		assertNoError(t, err)
+		_, err = repository.Insert(newOrderStats(newId()))
+		assertNoError(t, err)
+
+		found, err := repository.FindByUserId(userId)
+		assertNoError(t, err)
+		assertEqual(t, "orders", len(found), 2)
+		for _, stats := range found {
+			assertEqual(t, "userId", stats.UserId, userId)
+			assertEqual(t, "spent", stats.Spent, first.Spent)
+			assertEqual(t, "articles", len(stats.Articles), 1)
+			assertEqual(t, "articleId", stats.Articles[0].ArticleId, first.Articles[0].ArticleId)
+			assertTime(t, "created", stats.Created, first.Created)
+		}
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		found, err := repository.FindByUserId(newId())
+		assertNoError(t, err)
+		assertEqual(t, "orders", len(found), 0)
+	})
+
+	t.Run("insert upserts by order id", func(t *testing.T) {
+		current := newOrderStats(newId())
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+
+		current.Status = order.Canceled
+		current.Refunded = current.Spent
+		current.Spent = 0
+		_, err = repository.Insert(current)
+		assertNoError(t, err)
+
+		found, err := repository.FindByUserId(current.UserId)
+		assertNoError(t, err)
+		assertEqual(t, "orders", len(found), 1)
+		assertEqual(t, "status", found[0].Status, order.Canceled)
+		assertEqual(t, "refunded", found[0].Refunded, current.Refunded)
+		assertEqual(t, "spent", found[0].Spent, float32(0))
+	})
+}
+
+func newOrderStats(userId string) *customer.OrderStats {
+	return &customer.OrderStats{
+		OrderId:  newId(),
+		UserId:   userId,
+		Status:   order.Paid,
+		Total:    30,
+		Spent:    30,
+		Articles: []*customer.ArticleCount{{ArticleId: "article", Quantity: 3}},
+		Created:  now(),
+		Updated:  now(),
+	}
+}
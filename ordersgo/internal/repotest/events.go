@@ -0,0 +1,287 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/events"
+)
+
+// EventsRepository verifica el contrato de events.EventsRepository
+func EventsRepository(t *testing.T, repository events.EventsRepository) {
+	t.Run("insert validates schema", func(t *testing.T) {
+		_, err := repository.Insert(&events.Event{Type: events.Place})
+		if err == nil {
+			t.Fatal("expected validation error")
+		}
+	})
+
+	t.Run("find place by cart id", func(t *testing.T) {
+		event := newPlace(newId(), newId())
+		_, err := repository.Insert(event)
+		assertNoError(t, err)
+
+		found, err := repository.FindPlaceByCartId(event.PlaceEvent.CartId)
+		assertNoError(t, err)
+		assertEqual(t, "orderId", found.OrderId, event.OrderId)
+		assertEqual(t, "type", found.Type, events.Place)
+		assertEqual(t, "userId", found.PlaceEvent.UserId, event.PlaceEvent.UserId)
+		assertEqual(t, "articles", len(found.PlaceEvent.Articles), 1)
+		assertTime(t, "created", found.Created, event.Created)
+		if found.ID.IsZero() {
+			t.Fatal("expected generated id")
+		}
+	})
+
+	t.Run("place not found", func(t *testing.T) {
+		_, err := repository.FindPlaceByCartId(newId())
+		assertNotFound(t, err)
+	})
+
+	t.Run("one place per cart", func(t *testing.T) {
+		cartId := newId()
+		_, err := repository.Insert(newPlace(newId(), cartId))
+		assertNoError(t, err)
+
+		_, err = repository.Insert(newPlace(newId(), cartId))
+		assertError(t, err, errs.AlreadyExist)
+	})
+
+	t.Run("one manual transition per payment", func(t *testing.T) {
+		orderId := newId()
+		paymentId := newId()
+		manual := func() *events.Event {
+			event := newPayment(orderId, paymentId)
+			event.Payment.Status = ""
+			return event
+		}
+
+		_, err := repository.Insert(manual())
+		assertNoError(t, err)
+
+		_, err = repository.Insert(manual())
+		assertError(t, err, errs.AlreadyExist)
+
+		// Las transiciones con estado del mismo pago se siguen guardando
+		_, err = repository.Insert(newPayment(orderId, paymentId))
+		assertNoError(t, err)
+	})
+
+	t.Run("one validation per article", func(t *testing.T) {
+		orderId := newId()
+		articleId := newId()
+
+		_, err := repository.Insert(newValidation(orderId, articleId))
+		assertNoError(t, err)
+
+		_, err = repository.Insert(newValidation(orderId, articleId))
+		assertError(t, err, errs.AlreadyExist)
+
+		_, err = repository.Insert(newValidation(orderId, newId()))
+		assertNoError(t, err)
+	})
+
+	t.Run("one refund per sequence", func(t *testing.T) {
+		orderId := newId()
+		paymentId := newId()
+		refund := func(sequence int) *events.Event {
+			event := newRefund(orderId, paymentId)
+			event.Refund.Sequence = sequence
+			return event
+		}
+
+		_, err := repository.Insert(refund(1))
+		assertNoError(t, err)
+
+		_, err = repository.Insert(refund(1))
+		assertError(t, err, errs.AlreadyExist)
+
+		_, err = repository.Insert(refund(2))
+		assertNoError(t, err)
+
+		// Los reembolsos sin número son anteriores al índice
+		_, err = repository.Insert(refund(0))
+		assertNoError(t, err)
+		_, err = repository.Insert(refund(0))
+		assertNoError(t, err)
+	})
+
+	t.Run("find by order id keeps insertion order", func(t *testing.T) {
+		orderId := newId()
+		_, err := repository.Insert(newPlace(orderId, newId()))
+		assertNoError(t, err)
+		_, err = repository.Insert(newPayment(orderId, newId()))
+		assertNoError(t, err)
+		_, err = repository.Insert(newPayment(newId(), newId()))
+		assertNoError(t, err)
+
+		found, err := repository.FindByOrderId(orderId)
+		assertNoError(t, err)
+		assertEqual(t, "len", len(found), 2)
+		assertEqual(t, "first", found[0].Type, events.Place)
+		assertEqual(t, "second", found[1].Type, events.Payment)
+	})
+
+	t.Run("find by unknown order id is empty", func(t *testing.T) {
+		found, err := repository.FindByOrderId(newId())
+		assertNoError(t, err)
+		assertEqual(t, "len", len(found), 0)
+	})
+
+	t.Run("find payment by payment id", func(t *testing.T) {
+		event := newPayment(newId(), newId())
+		_, err := repository.Insert(event)
+		assertNoError(t, err)
+
+		found, err := repository.FindPaymentByPaymentId(event.Payment.PaymentId)
+		assertNoError(t, err)
+		assertEqual(t, "orderId", found.OrderId, event.OrderId)
+		assertEqual(t, "amount", found.Payment.Amount, event.Payment.Amount)
+
+		_, err = repository.FindPaymentByPaymentId(newId())
+		assertNotFound(t, err)
+	})
+
+	t.Run("refund keeps payment reference", func(t *testing.T) {
+		payment := newPayment(newId(), newId())
+		refund := newRefund(payment.OrderId, payment.Payment.PaymentId)
+		for _, event := range []*events.Event{payment, refund} {
+			_, err := repository.Insert(event)
+			assertNoError(t, err)
+		}
+
+		found, err := repository.FindByOrderId(payment.OrderId)
+		assertNoError(t, err)
+		assertEqual(t, "len", len(found), 2)
+		assertEqual(t, "type", found[1].Type, events.Refund)
+		assertEqual(t, "paymentId", found[1].Refund.PaymentId, payment.Payment.PaymentId)
+		assertEqual(t, "refundId", found[1].Refund.RefundId, refund.Refund.RefundId)
+		assertEqual(t, "amount", found[1].Refund.Amount, refund.Refund.Amount)
+
+		// Los reembolsos no son eventos de pago
+		byPayment, err := repository.FindPaymentByPaymentId(payment.Payment.PaymentId)
+		assertNoError(t, err)
+		assertEqual(t, "payment type", byPayment.Type, events.Payment)
+	})
+
+	t.Run("find order ids includes placed orders", func(t *testing.T) {
+		orderId := newId()
+		_, err := repository.Insert(newPlace(orderId, newId()))
+		assertNoError(t, err)
+		_, err = repository.Insert(newPayment(orderId, newId()))
+		assertNoError(t, err)
+
+		orderIds, err := repository.FindOrderIds()
+		assertNoError(t, err)
+		found := 0
+		for _, current := range orderIds {
+			if current == orderId {
+				found++
+			}
+		}
+		assertEqual(t, "orderId", found, 1)
+	})
+
+	t.Run("find payments in range", func(t *testing.T) {
+		base := now().AddDate(-50, 0, 0)
+		inside := newPayment(newId(), newId())
+		inside.Created = base
+		outside := newPayment(newId(), newId())
+		outside.Created = base.Add(time.Hour)
+		place := newPlace(newId(), newId())
+		place.Created = base
+		for _, event := range []*events.Event{inside, outside, place} {
+			_, err := repository.Insert(event)
+			assertNoError(t, err)
+		}
+
+		found, err := repository.FindPayments(base, base.Add(time.Hour))
+		assertNoError(t, err)
+		orderIds := map[string]bool{}
+		for _, event := range found {
+			assertEqual(t, "type", event.Type, events.Payment)
+			orderIds[event.OrderId] = true
+		}
+		assertEqual(t, "inside", orderIds[inside.OrderId], true)
+		assertEqual(t, "outside", orderIds[outside.OrderId], false)
+	})
+
+	t.Run("stored events are not aliased", func(t *testing.T) {
+		event := newPlace(newId(), newId())
+		_, err := repository.Insert(event)
+		assertNoError(t, err)
+		event.PlaceEvent.UserId = "changed"
+
+		found, err := repository.FindPlaceByCartId(event.PlaceEvent.CartId)
+		assertNoError(t, err)
+		if found.PlaceEvent.UserId == "changed" {
+			t.Fatal("stored event changed after insert")
+		}
+	})
+}
+
+func newPlace(orderId string, cartId string) *events.Event {
+	return &events.Event{
+		OrderId: orderId,
+		Type:    events.Place,
+		PlaceEvent: &events.PlaceEvent{
+			CartId: cartId,
+			UserId: newId(),
+			Articles: []events.Article{{
+				ArticleId: newId(),
+				Quantity:  1,
+			}},
+		},
+		Created: now(),
+		Updated: now(),
+	}
+}
+
+func newPayment(orderId string, paymentId string) *events.Event {
+	return &events.Event{
+		OrderId: orderId,
+		Type:    events.Payment,
+		Payment: &events.PaymentEvent{
+			OrderId:   orderId,
+			Method:    string(events.Cash),
+			Amount:    100,
+			PaymentId: paymentId,
+			Status:    events.PaymentCaptured,
+		},
+		Created: now(),
+		Updated: now(),
+	}
+}
+
+func newRefund(orderId string, paymentId string) *events.Event {
+	return &events.Event{
+		OrderId: orderId,
+		Type:    events.Refund,
+		Refund: &events.RefundEvent{
+			OrderId:   orderId,
+			PaymentId: paymentId,
+			RefundId:  newId(),
+			Amount:    25,
+			Reason:    "test",
+		},
+		Created: now(),
+		Updated: now(),
+	}
+}
+
+func newValidation(orderId string, articleId string) *events.Event {
+	return &events.Event{
+		OrderId: orderId,
+		Type:    events.Validation,
+		Validation: &events.ValidationEvent{
+			ArticleId:   articleId,
+			ReferenceId: orderId,
+			IsValid:     true,
+			Stock:       10,
+			Price:       100,
+		},
+		Created: now(),
+		Updated: now(),
+	}
+}
@@ -0,0 +1,126 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/idempotency"
+)
+
+// IdempotencyRepository verifica el contrato de idempotency.IdempotencyRepository
+func IdempotencyRepository(t *testing.T, repository idempotency.IdempotencyRepository) {
+	t.Run("insert validates schema", func(t *testing.T) {
+		_, err := repository.Insert(&idempotency.Record{Key: newId()})
+		if err == nil {
+			t.Fatal("expected validation error")
+		}
+	})
+
+	t.Run("insert and find", func(t *testing.T) {
+		current := newIdempotencyRecord()
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+
+		found, err := repository.FindByKey(current.Key)
+		assertNoError(t, err)
+		assertEqual(t, "requestHash", found.RequestHash, current.RequestHash)
+		assertEqual(t, "completed", found.IsCompleted(), false)
+		assertTime(t, "created", found.Created, current.Created)
+	})
+
+	t.Run("duplicated key", func(t *testing.T) {
+		current := newIdempotencyRecord()
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+
+		_, err = repository.Insert(current)
+		assertError(t, err, errs.AlreadyExist)
+	})
+
+	t.Run("update keeps created", func(t *testing.T) {
+		current := newIdempotencyRecord()
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+
+		current.Status = 200
+		current.ContentType = "application/json"
+		current.Body = `{"ok":true}`
+		assertNoError(t, repository.Update(current))
+
+		found, err := repository.FindByKey(current.Key)
+		assertNoError(t, err)
+		assertEqual(t, "status", found.Status, 200)
+		assertEqual(t, "contentType", found.ContentType, current.ContentType)
+		assertEqual(t, "body", found.Body, current.Body)
+		assertTime(t, "created", found.Created, current.Created)
+	})
+
+	t.Run("update unknown key", func(t *testing.T) {
+		assertNotFound(t, repository.Update(newIdempotencyRecord()))
+	})
+
+	t.Run("take over", func(t *testing.T) {
+		current := newIdempotencyRecord()
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+		stale, err := repository.FindByKey(current.Key)
+		assertNoError(t, err)
+
+		first := newIdempotencyRecord()
+		first.Key = current.Key
+		first.Created = now().Add(time.Second)
+		assertNoError(t, repository.TakeOver(stale, first))
+
+		found, err := repository.FindByKey(current.Key)
+		assertNoError(t, err)
+		assertEqual(t, "requestHash", found.RequestHash, first.RequestHash)
+		assertTime(t, "created", found.Created, first.Created)
+
+		// Otro pedido que leyó el mismo registro abandonado ya no lo puede tomar
+		second := newIdempotencyRecord()
+		second.Key = current.Key
+		assertNotFound(t, repository.TakeOver(stale, second))
+	})
+
+	t.Run("take over completed", func(t *testing.T) {
+		current := newIdempotencyRecord()
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+		stale, err := repository.FindByKey(current.Key)
+		assertNoError(t, err)
+
+		current.Status = 200
+		assertNoError(t, repository.Update(current))
+
+		next := newIdempotencyRecord()
+		next.Key = current.Key
+		assertNotFound(t, repository.TakeOver(stale, next))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		current := newIdempotencyRecord()
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+
+		assertNoError(t, repository.Delete(current.Key))
+		_, err = repository.FindByKey(current.Key)
+		assertNotFound(t, err)
+
+		_, err = repository.Insert(current)
+		assertNoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repository.FindByKey(newId())
+		assertNotFound(t, err)
+	})
+}
+
+func newIdempotencyRecord() *idempotency.Record {
+	return &idempotency.Record{
+		Key:         idempotency.ScopedKey(newId(), newId()),
+		RequestHash: idempotency.RequestHash("repotest", newId()),
+		Created:     now(),
+	}
+}
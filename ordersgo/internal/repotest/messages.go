@@ -0,0 +1,51 @@
+package repotest
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/messages"
+)
+
+// MessagesRepository verifica el contrato de messages.MessagesRepository
+func MessagesRepository(t *testing.T, repository messages.MessagesRepository) {
+	t.Run("insert validates schema", func(t *testing.T) {
+		_, err := repository.Insert(&messages.ProcessedMessage{Queue: "queue"})
+		if err == nil {
+			t.Fatal("expected validation error")
+		}
+	})
+
+	t.Run("insert and find", func(t *testing.T) {
+		current := newMessage()
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+
+		found, err := repository.FindByKey(current.Key)
+		assertNoError(t, err)
+		assertEqual(t, "queue", found.Queue, current.Queue)
+		assertTime(t, "created", found.Created, current.Created)
+	})
+
+	t.Run("duplicated key", func(t *testing.T) {
+		current := newMessage()
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+
+		_, err = repository.Insert(current)
+		assertError(t, err, errs.AlreadyExist)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repository.FindByKey(newId())
+		assertNotFound(t, err)
+	})
+}
+
+func newMessage() *messages.ProcessedMessage {
+	return &messages.ProcessedMessage{
+		Key:     messages.MessageKey("repotest", newId()),
+		Queue:   "repotest",
+		Created: now(),
+	}
+}
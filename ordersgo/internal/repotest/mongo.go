@@ -0,0 +1,40 @@
+package repotest
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nmarsollier/commongo/db"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Mongo base de datos temporal en MONGO_URL, se elimina al terminar el test.
+// Si MONGO_URL no está definida el test se saltea.
+func Mongo(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	url := os.Getenv("MONGO_URL")
+	if url == "" {
+		t.Skip("MONGO_URL not set")
+	}
+
+	name := "orders_test_" + strings.ReplaceAll(newId(), "-", "")[:12]
+	database, err := db.NewDatabase(url, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		ctx := context.Background()
+		if err := database.Drop(ctx); err != nil {
+			t.Error(err)
+		}
+		if err := database.Client().Disconnect(ctx); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return database
+}
@@ -0,0 +1,158 @@
+package repotest
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+)
+
+// OrderRepository verifica el contrato de order.OrderRepository
+func OrderRepository(t *testing.T, repository order.OrderRepository) {
+	t.Run("insert validates schema", func(t *testing.T) {
+		_, err := repository.Insert(&order.Order{Status: order.Placed})
+		if err == nil {
+			t.Fatal("expected validation error")
+		}
+	})
+
+	t.Run("insert and find", func(t *testing.T) {
+		current := newOrder(newId(), newId())
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+
+		found, err := repository.FindByOrderId(current.OrderId)
+		assertNoError(t, err)
+		assertEqual(t, "userId", found.UserId, current.UserId)
+		assertEqual(t, "status", found.Status, order.Placed)
+		assertEqual(t, "articles", len(found.Articles), 1)
+		assertEqual(t, "refunded", found.Refunded, current.Refunded)
+		assertEqual(t, "delivery", found.Delivery.ShippingAddress.City, current.Delivery.ShippingAddress.City)
+		assertEqual(t, "instructions", found.Delivery.Instructions, current.Delivery.Instructions)
+		assertEqual(t, "fulfillment", found.Fulfillment.TrackingNumber, current.Fulfillment.TrackingNumber)
+		assertEqual(t, "transitions", len(found.Fulfillment.Transitions), 1)
+		assertEqual(t, "returns", len(found.Returns), 1)
+		assertEqual(t, "return line", found.Returns[0].Lines[0].Status, events.ReturnRequested)
+		assertTime(t, "created", found.Created, current.Created)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repository.FindByOrderId(newId())
+		assertNotFound(t, err)
+	})
+
+	t.Run("insert upserts by order id", func(t *testing.T) {
+		current := newOrder(newId(), newId())
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+		first, err := repository.FindByOrderId(current.OrderId)
+		assertNoError(t, err)
+
+		current.Status = order.Validated
+		current.Articles = nil
+		_, err = repository.Insert(current)
+		assertNoError(t, err)
+
+		found, err := repository.FindByOrderId(current.OrderId)
+		assertNoError(t, err)
+		assertEqual(t, "status", found.Status, order.Validated)
+		assertEqual(t, "articles", len(found.Articles), 0)
+		assertEqual(t, "id", found.ID, first.ID)
+
+		byUser, err := repository.FindByUserId(current.UserId)
+		assertNoError(t, err)
+		assertEqual(t, "len", len(byUser), 1)
+	})
+
+	t.Run("find by user id keeps insertion order", func(t *testing.T) {
+		userId := newId()
+		first := newOrder(newId(), userId)
+		second := newOrder(newId(), userId)
+		_, err := repository.Insert(first)
+		assertNoError(t, err)
+		_, err = repository.Insert(second)
+		assertNoError(t, err)
+
+		// Actualizar no cambia la posición
+		first.Status = order.Paid
+		_, err = repository.Insert(first)
+		assertNoError(t, err)
+
+		found, err := repository.FindByUserId(userId)
+		assertNoError(t, err)
+		assertEqual(t, "len", len(found), 2)
+		assertEqual(t, "first", found[0].OrderId, first.OrderId)
+		assertEqual(t, "second", found[1].OrderId, second.OrderId)
+
+		found, err = repository.FindByUserId(newId())
+		assertNoError(t, err)
+		assertEqual(t, "len", len(found), 0)
+	})
+
+	t.Run("count by status", func(t *testing.T) {
+		before, err := repository.CountByStatus()
+		assertNoError(t, err)
+
+		current := newOrder(newId(), newId())
+		current.Status = order.PartiallyPaid
+		_, err = repository.Insert(current)
+		assertNoError(t, err)
+
+		after, err := repository.CountByStatus()
+		assertNoError(t, err)
+		assertEqual(t, "partially_paid", after[order.PartiallyPaid], before[order.PartiallyPaid]+1)
+	})
+}
+
+func newOrder(orderId string, userId string) *order.Order {
+	return &order.Order{
+		OrderId: orderId,
+		Status:  order.Placed,
+		UserId:  userId,
+		CartId:  newId(),
+		Articles: []*order.Article{{
+			ArticleId: newId(),
+			Quantity:  2,
+		}},
+		Payments: []*order.PaymentEvent{},
+		Refunded: 10,
+		Delivery: &events.Delivery{
+			ShippingAddress: &events.Address{
+				Line1:      "Av. Siempre Viva 742",
+				City:       "Springfield",
+				PostalCode: "1000",
+				Country:    "AR",
+			},
+			Instructions: "Tocar timbre",
+		},
+		Fulfillment: &events.FulfillmentState{
+			Status:         events.FulfillmentShipped,
+			Carrier:        "OCA",
+			TrackingNumber: "TN-1234",
+			Transitions: []*events.FulfillmentTransition{{
+				Status:         events.FulfillmentShipped,
+				Carrier:        "OCA",
+				TrackingNumber: "TN-1234",
+				Time:           now(),
+			}},
+		},
+		Returns: []*events.ReturnState{{
+			ReturnId: newId(),
+			UserId:   userId,
+			Status:   events.ReturnRequested,
+			Reason:   "Llegó dañado",
+			Lines: []*events.ReturnLineState{{
+				ArticleId: newId(),
+				Quantity:  1,
+				Status:    events.ReturnRequested,
+			}},
+			Transitions: []*events.ReturnTransition{{
+				Status: events.ReturnRequested,
+				Reason: "Llegó dañado",
+				Time:   now(),
+			}},
+		}},
+		Created: now(),
+		Updated: now(),
+	}
+}
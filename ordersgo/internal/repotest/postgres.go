@@ -0,0 +1,30 @@
+package repotest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/pgdb"
+)
+
+// Postgres pool contra POSTGRES_URL con las migraciones aplicadas, se cierra al terminar el test.
+// Si POSTGRES_URL no está definida el test se saltea.
+func Postgres(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv("POSTGRES_URL")
+	if url == "" {
+		t.Skip("POSTGRES_URL not set")
+	}
+
+	pool, err := pgdb.Connect(context.Background(), log.Get("", "test"), url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
@@ -0,0 +1,98 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nmarsollier/ordersgo/internal/reconciliation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReportRepository verifica el contrato de reconciliation.ReportRepository
+func ReportRepository(t *testing.T, repository reconciliation.ReportRepository) {
+	t.Run("insert and find by id", func(t *testing.T) {
+		report := newReport()
+		_, err := repository.Insert(report)
+		assertNoError(t, err)
+
+		found, err := repository.FindById(report.ID.Hex())
+		assertNoError(t, err)
+		assertEqual(t, "status", found.Status, reconciliation.Running)
+		assertTime(t, "from", found.From, report.From)
+		assertTime(t, "to", found.To, report.To)
+		assertEqual(t, "discrepancies", len(found.Discrepancies), 0)
+	})
+
+	t.Run("insert replaces by id", func(t *testing.T) {
+		report := newReport()
+		_, err := repository.Insert(report)
+		assertNoError(t, err)
+
+		orderId := newId()
+		report.Status = reconciliation.Completed
+		report.Orders = 1
+		report.Payments = 2
+		report.Discrepancies = []*reconciliation.Discrepancy{{
+			Type:         reconciliation.AmountMismatch,
+			OrderId:      orderId,
+			PaymentId:    newId(),
+			LocalAmount:  10,
+			RemoteAmount: 12.5,
+		}}
+		_, err = repository.Insert(report)
+		assertNoError(t, err)
+
+		found, err := repository.FindById(report.ID.Hex())
+		assertNoError(t, err)
+		assertEqual(t, "status", found.Status, reconciliation.Completed)
+		assertEqual(t, "orders", found.Orders, 1)
+		assertEqual(t, "payments", found.Payments, 2)
+		assertEqual(t, "discrepancies", len(found.Discrepancies), 1)
+		assertEqual(t, "type", found.Discrepancies[0].Type, reconciliation.AmountMismatch)
+		assertEqual(t, "orderId", found.Discrepancies[0].OrderId, orderId)
+		assertEqual(t, "remoteAmount", found.Discrepancies[0].RemoteAmount, float32(12.5))
+	})
+
+	t.Run("find all latest first", func(t *testing.T) {
+		older := newReport()
+		older.Created = older.Created.Add(-time.Minute)
+		newer := newReport()
+		for _, current := range []*reconciliation.Report{older, newer} {
+			_, err := repository.Insert(current)
+			assertNoError(t, err)
+		}
+
+		all, err := repository.FindAll()
+		assertNoError(t, err)
+		olderAt, newerAt := -1, -1
+		for i, current := range all {
+			switch current.ID {
+			case older.ID:
+				olderAt = i
+			case newer.ID:
+				newerAt = i
+			}
+		}
+		if newerAt < 0 || olderAt < 0 || newerAt > olderAt {
+			t.Fatalf("reports order = %d, %d, want newer first", newerAt, olderAt)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repository.FindById(primitive.NewObjectID().Hex())
+		assertNotFound(t, err)
+	})
+}
+
+func newReport() *reconciliation.Report {
+	created := now()
+	return &reconciliation.Report{
+		ID:            primitive.NewObjectID(),
+		From:          created.Add(-24 * time.Hour),
+		To:            created,
+		Status:        reconciliation.Running,
+		Discrepancies: []*reconciliation.Discrepancy{},
+		Created:       created,
+		Updated:       created,
+	}
+}
@@ -0,0 +1,63 @@
+// Package repotest contiene las pruebas de contrato que deben pasar todas las
+// implementaciones de los repositorios (mongo, memoria, ...).
+//
+// Se usan desde los tests de cada backend:
+//
+//	func TestMemoryEvents(t *testing.T) {
+//		repotest.EventsRepository(t, events.NewMemoryEventsRepository(logger, memdb.NewTable[events.Event]()))
+//	}
+//
+// Las pruebas generan ids únicos, por lo que pueden correr sobre una base con datos.
+// Los repositorios mongo deben usar colecciones con los indices que crea di y los
+// de postgres un pool creado con pgdb.Connect, que aplica las migraciones.
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nmarsollier/commongo/errs"
+	uuid "github.com/satori/go.uuid"
+)
+
+func newId() string {
+	return uuid.NewV4().String()
+}
+
+// now con la precisión que guardan los backends
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+func assertError(t *testing.T, got error, want error) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("error = %v, want %v", got, want)
+	}
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func assertNotFound(t *testing.T, err error) {
+	t.Helper()
+	assertError(t, err, errs.NotFound)
+}
+
+func assertEqual[T comparable](t *testing.T, field string, got T, want T) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("%s = %v, want %v", field, got, want)
+	}
+}
+
+func assertTime(t *testing.T, field string, got time.Time, want time.Time) {
+	t.Helper()
+	if !got.Equal(want) {
+		t.Fatalf("%s = %v, want %v", field, got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nmarsollier/ordersgo/internal/projections/sales"
+)
+
+// SalesRepository verifica el contrato de sales.SalesRepository
+func SalesRepository(t *testing.T, repository sales.SalesRepository) {
+	// Lejos de now, igual puede haber datos de corridas anteriores en el rango
+	base := now().AddDate(-50, 0, 0)
+
+	t.Run("find between", func(t *testing.T) {
+		inside := newOrderSales(base, base.Add(2*time.Hour))
+		before := newOrderSales(base.Add(-3*time.Hour), base.Add(-time.Hour))
+		overlaps := newOrderSales(base.Add(-time.Hour), base.Add(time.Hour))
+		after := newOrderSales(base.Add(5*time.Hour), base.Add(6*time.Hour))
+		for _, current := range []*sales.OrderSales{inside, before, overlaps, after} {
+			_, err := repository.Insert(current)
+			assertNoError(t, err)
+		}
+
+		found, err := repository.FindBetween(base, base.Add(5*time.Hour))
+		assertNoError(t, err)
+		assertEqual(t, "inside", findSales(found, inside.OrderId) != nil, true)
+		assertEqual(t, "overlaps", findSales(found, overlaps.OrderId) != nil, true)
+		assertEqual(t, "before", findSales(found, before.OrderId) != nil, false)
+		assertEqual(t, "after", findSales(found, after.OrderId) != nil, false)
+	})
+
+	t.Run("insert upserts by order id", func(t *testing.T) {
+		start := base.AddDate(0, 1, 0)
+		current := newOrderSales(start, start)
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+
+		current.Facts = append(current.Facts, &sales.Fact{Type: sales.Refund, Time: start.Add(time.Hour), Amount: 10})
+		current.Last = start.Add(time.Hour)
+		_, err = repository.Insert(current)
+		assertNoError(t, err)
+
+		all, err := repository.FindBetween(start, start.Add(2*time.Hour))
+		assertNoError(t, err)
+		found := findSales(all, current.OrderId)
+		if found == nil {
+			t.Fatalf("order %s not found", current.OrderId)
+		}
+		assertEqual(t, "items", found.Items, current.Items)
+		assertEqual(t, "facts", len(found.Facts), 3)
+		assertEqual(t, "type", found.Facts[2].Type, sales.Refund)
+		assertEqual(t, "amount", found.Facts[2].Amount, float32(10))
+		assertTime(t, "fact time", found.Facts[2].Time, start.Add(time.Hour))
+		assertTime(t, "last", found.Last, start.Add(time.Hour))
+	})
+}
+
+func newOrderSales(first time.Time, last time.Time) *sales.OrderSales {
+	return &sales.OrderSales{
+		OrderId: newId(),
+		Items:   3,
+		Facts: []*sales.Fact{
+			{Type: sales.Placed, Time: first},
+			{Type: sales.Payment, Time: last, Amount: 30},
+		},
+		First:   first,
+		Last:    last,
+		Updated: last,
+	}
+}
+
+func findSales(all []*sales.OrderSales, orderId string) *sales.OrderSales {
+	for _, current := range all {
+		if current.OrderId == orderId {
+			return current
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nmarsollier/ordersgo/internal/projections/status"
+)
+
+// StatusRepository verifica el contrato de status.StatusRepository
+func StatusRepository(t *testing.T, repository status.StatusRepository) {
+	t.Run("insert and find", func(t *testing.T) {
+		current := newStatus(newId())
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+
+		found, err := repository.FindByOrderId(current.OrderId)
+		assertNoError(t, err)
+		assertEqual(t, "userId", found.UserId, current.UserId)
+		assertEqual(t, "milestones", len(found.Milestones), 2)
+		assertEqual(t, "placed", found.Milestones[0].Type, status.Placed)
+		assertTime(t, "placed time", found.Milestones[0].Time, current.Milestones[0].Time)
+		assertEqual(t, "articleId", found.Milestones[1].ArticleId, current.Milestones[1].ArticleId)
+		assertEqual(t, "valid", *found.Milestones[1].Valid, false)
+		assertTime(t, "created", found.Created, current.Created)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repository.FindByOrderId(newId())
+		assertNotFound(t, err)
+	})
+
+	t.Run("find by created", func(t *testing.T) {
+		base := now().AddDate(-50, 0, 0)
+		inside := newStatus(newId())
+		inside.Created = base
+		outside := newStatus(newId())
+		outside.Created = base.Add(time.Hour)
+		for _, current := range []*status.OrderStatus{inside, outside} {
+			_, err := repository.Insert(current)
+			assertNoError(t, err)
+		}
+
+		found, err := repository.FindByCreated(base, base.Add(time.Hour))
+		assertNoError(t, err)
+		orderIds := map[string]bool{}
+		for _, current := range found {
+			orderIds[current.OrderId] = true
+		}
+		assertEqual(t, "inside", orderIds[inside.OrderId], true)
+		assertEqual(t, "outside", orderIds[outside.OrderId], false)
+	})
+
+	t.Run("insert upserts by order id", func(t *testing.T) {
+		current := newStatus(newId())
+		_, err := repository.Insert(current)
+		assertNoError(t, err)
+		first, err := repository.FindByOrderId(current.OrderId)
+		assertNoError(t, err)
+
+		current.Milestones = append(current.Milestones, &status.Milestone{
+			Type:   status.Canceled,
+			Time:   now(),
+			Reason: "test",
+		})
+		_, err = repository.Insert(current)
+		assertNoError(t, err)
+
+		found, err := repository.FindByOrderId(current.OrderId)
+		assertNoError(t, err)
+		assertEqual(t, "milestones", len(found.Milestones), 3)
+		assertEqual(t, "reason", found.Milestones[2].Reason, "test")
+		assertEqual(t, "id", found.ID, first.ID)
+	})
+}
+
+func newStatus(orderId string) *status.OrderStatus {
+	valid := false
+	return &status.OrderStatus{
+		OrderId: orderId,
+		UserId:  newId(),
+		Milestones: []*status.Milestone{
+			{Type: status.Placed, Time: now()},
+			{Type: status.ArticleValidated, Time: now(), ArticleId: newId(), Valid: &valid},
+		},
+		Created: now(),
+		Updated: now(),
+	}
+}
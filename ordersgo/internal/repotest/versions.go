@@ -0,0 +1,51 @@
+package repotest
+
+import (
+	"testing"
+
+	"github.com/nmarsollier/ordersgo/internal/projections/version"
+)
+
+// VersionRepository verifica el contrato de version.VersionRepository
+func VersionRepository(t *testing.T, repository version.VersionRepository) {
+	t.Run("save and find", func(t *testing.T) {
+		current := newVersion(newId())
+		_, err := repository.Save(current)
+		assertNoError(t, err)
+
+		found, err := repository.FindByProjection(current.Projection)
+		assertNoError(t, err)
+		assertEqual(t, "version", found.Version, 1)
+		assertTime(t, "updated", found.Updated, current.Updated)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repository.FindByProjection(newId())
+		assertNotFound(t, err)
+	})
+
+	t.Run("save replaces by projection", func(t *testing.T) {
+		current := newVersion(newId())
+		_, err := repository.Save(current)
+		assertNoError(t, err)
+		first, err := repository.FindByProjection(current.Projection)
+		assertNoError(t, err)
+
+		current.Version = 2
+		_, err = repository.Save(current)
+		assertNoError(t, err)
+
+		found, err := repository.FindByProjection(current.Projection)
+		assertNoError(t, err)
+		assertEqual(t, "version", found.Version, 2)
+		assertEqual(t, "id", found.ID, first.ID)
+	})
+}
+
+func newVersion(projection string) *version.Version {
+	return &version.Version{
+		Projection: projection,
+		Version:    1,
+		Updated:    now(),
+	}
+}
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/errs"
+)
+
+// defaultAnalyticsRange rango de los reportes cuando no se indica from
+const defaultAnalyticsRange = 30 * 24 * time.Hour
+
+const defaultArticlesLimit = 10
+const maxArticlesLimit = 100
+
+// parseRange lee from y to de la query, por defecto los últimos 30 días
+func parseRange(c *gin.Context) (time.Time, time.Time, error) {
+	to, err := parseDate("to", c.Query("to"), time.Now())
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	from, err := parseDate("from", c.Query("from"), to.Add(-defaultAnalyticsRange))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+// parseDate acepta RFC3339 o solo la fecha, en UTC
+func parseDate(field string, value string, def time.Time) (time.Time, error) {
+	if value == "" {
+		return def, nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	return time.Time{}, errs.NewValidation().Add(field, "must be RFC3339 or YYYY-MM-DD")
+}
+
+func parseLimit(value string) (int, error) {
+	if value == "" {
+		return defaultArticlesLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxArticlesLimit {
+		return 0, errs.NewValidation().Add("limit", "must be between 1 and 100")
+	}
+	return limit, nil
+}
@@ -14,22 +14,26 @@ import (
 // DELETE /orders/:orderId
 //
 //	@Summary		Cancelar una orden
-//	@Description	Cancela una orden existente si está en un estado cancelable. Publica evento order.canceled que dispara reembolsos automáticos en payments_node.
+//	@Description	Cancela una orden existente si está en un estado cancelable, las ordenes despachadas no se cancelan, se devuelven. Publica evento order.canceled que dispara reembolsos automáticos en payments_node.
 //	@Tags			Ordenes
 //	@Accept			json
 //	@Produce		json
 //	@Param			orderId	path		string	true	"ID de la orden"
 //	@Param			Authorization	header	string	true	"Bearer {token}"
+//	@Param			Idempotency-Key	header	string	false	"Clave para reintentos, repite la respuesta original"
 //	@Success		200	{object}	CancelOrderResponse	"Orden cancelada exitosamente"
 //	@Failure		400	{object}	errs.ValidationErr	"Orden no puede ser cancelada en este estado"
 //	@Failure		401	{object}	rst.ErrorData	"Unauthorized"
 //	@Failure		404	{object}	rst.ErrorData	"Orden no encontrada"
+//	@Failure		409	{object}	rst.ErrorData	"Idempotency-Key en proceso"
+//	@Failure		422	{object}	rst.ErrorData	"Idempotency-Key usada con otro pedido"
 //	@Failure		500	{object}	rst.ErrorData	"Internal server error"
 //	@Router			/orders/{orderId} [delete]
 func initDeleteOrdersId(engine *gin.Engine) {
 	engine.DELETE(
 		"/orders/:orderId",
 		server.ValidateAuthentication,
+		server.Idempotent,
 		cancelOrder,
 	)
 }
@@ -88,8 +92,14 @@ func cancelOrder(c *gin.Context) {
 		return
 	}
 
-	// 4. Validar que la orden puede ser cancelada según su estado
-	if !canCancelOrder(orderData.Status) {
+	// 4. Validar que la orden puede ser cancelada según su estado, una vez despachada solo se devuelve.
+	// ProcessCancelOrder vuelve a validar con los eventos, la proyección puede no tener un despacho reciente.
+	if orderData.IsShipped() {
+		rst.AbortWithError(c, errs.NewValidation().Add("status",
+			"La orden ya fue despachada, no se puede cancelar, solo devolver"))
+		return
+	}
+	if !orderData.CanCancel() {
 		rst.AbortWithError(c, errs.NewValidation().Add("status",
 			"No se puede cancelar una orden en estado "+string(orderData.Status)))
 		return
@@ -104,14 +114,18 @@ func cancelOrder(c *gin.Context) {
 	cancelEvent := deps.EventService().NewCancelEvent(orderId, user.ID, reason)
 
 	// 6. Guardar el evento y actualizar la proyección
-	if _, err := deps.EventService().Save(cancelEvent); err != nil {
+	if _, err := deps.Service().ProcessCancelOrder(cancelEvent); err != nil {
 		deps.Logger().Error("Error saving cancel event: ", err)
+		if _, ok := err.(errs.Validation); ok {
+			rst.AbortWithError(c, err)
+			return
+		}
 		rst.AbortWithError(c, errs.Internal)
 		return
 	}
 
 	// 7. Publicar evento order.canceled para que payments_node procese los reembolsos
-	if err := rabbit.PublishOrderCanceled(deps.Logger(), orderId, user.ID, reason); err != nil {
+	if err := rabbit.PublishOrderCanceled(deps, orderId, user.ID, reason); err != nil {
 		deps.Logger().Error("Error publishing order.canceled: ", err)
 		// No retornamos error porque el evento ya se guardó
 		// Los reembolsos se pueden procesar manualmente si falla RabbitMQ
@@ -124,19 +138,3 @@ func cancelOrder(c *gin.Context) {
 		Status:  "canceled",
 	})
 }
-
-// canCancelOrder verifica si una orden puede ser cancelada según su estado
-func canCancelOrder(status order.OrderStatus) bool {
-	// No se pueden cancelar órdenes inválidas o ya canceladas
-	if status == order.Invalid || status == order.Canceled {
-		return false
-	}
-
-	// Se pueden cancelar órdenes en cualquier otro estado:
-	// - placed: Recién creada
-	// - validated: Validada
-	// - payment_defined: Sin pagos o todos reembolsados
-	// - partially_paid: Con pagos parciales
-	// - paid: Pagada completamente (antes de enviarse)
-	return true
-}
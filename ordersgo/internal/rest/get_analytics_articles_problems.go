@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Artículos con problemas
+//	@Description	Artículos rechazados en la validación o en ordenes canceladas, ordenados por tasa de rechazo y luego por cantidad cancelada. Requiere permiso admin.
+//	@Tags			Reportes
+//	@Produce		json
+//	@Param			limit			query		int						false	"Cantidad de artículos, por defecto 10, máximo 100"
+//	@Param			Authorization	header		string					true	"Bearer {token}"
+//	@Success		200				{array}		article.ArticleDemand	"Artículos"
+//	@Failure		400				{object}	rst.ErrorData			"Bad Request"
+//	@Failure		401				{object}	rst.ErrorData			"Unauthorized"
+//	@Failure		500				{object}	rst.ErrorData			"Internal Server Error"
+//	@Router			/analytics/articles/problems [get]
+//
+// Artículos con problemas
+func initGetAnalyticsArticlesProblems(engine *gin.Engine) {
+	engine.GET(
+		"/analytics/articles/problems",
+		server.ValidateAdmin,
+		getAnalyticsArticlesProblems,
+	)
+}
+
+func getAnalyticsArticlesProblems(c *gin.Context) {
+	limit, err := parseLimit(c.Query("limit"))
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	articles, err := deps.ArticleService().Problems(limit)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, articles)
+}
@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Artículos más pedidos
+//	@Description	Demanda por artículo: cantidad pedida, pagada y cancelada, tasa de rechazo en la validación e ingresos, ordenado por cantidad pedida. Requiere permiso admin.
+//	@Tags			Reportes
+//	@Produce		json
+//	@Param			limit			query		int						false	"Cantidad de artículos, por defecto 10, máximo 100"
+//	@Param			Authorization	header		string					true	"Bearer {token}"
+//	@Success		200				{array}		article.ArticleDemand	"Artículos"
+//	@Failure		400				{object}	rst.ErrorData			"Bad Request"
+//	@Failure		401				{object}	rst.ErrorData			"Unauthorized"
+//	@Failure		500				{object}	rst.ErrorData			"Internal Server Error"
+//	@Router			/analytics/articles/top [get]
+//
+// Artículos más pedidos
+func initGetAnalyticsArticlesTop(engine *gin.Engine) {
+	engine.GET(
+		"/analytics/articles/top",
+		server.ValidateAdmin,
+		getAnalyticsArticlesTop,
+	)
+}
+
+func getAnalyticsArticlesTop(c *gin.Context) {
+	limit, err := parseLimit(c.Query("limit"))
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	articles, err := deps.ArticleService().Top(limit)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, articles)
+}
@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Embudo de ordenes
+//	@Description	Ordenes colocadas en el rango que llegaron a validada, pagada, cancelada o reembolsada, con la conversión y la duración promedio, mediana y p90 de cada paso. Por defecto los últimos 30 días. Requiere permiso admin.
+//	@Tags			Reportes
+//	@Produce		json
+//	@Param			from			query		string				false	"Desde, incluido (RFC3339 o YYYY-MM-DD)"
+//	@Param			to				query		string				false	"Hasta, excluido (RFC3339 o YYYY-MM-DD)"
+//	@Param			Authorization	header		string				true	"Bearer {token}"
+//	@Success		200				{object}	analytics.Funnel	"Embudo"
+//	@Failure		400				{object}	rst.ErrorData		"Bad Request"
+//	@Failure		401				{object}	rst.ErrorData		"Unauthorized"
+//	@Failure		500				{object}	rst.ErrorData		"Internal Server Error"
+//	@Router			/analytics/funnel [get]
+//
+// Embudo de ordenes
+func initGetAnalyticsFunnel(engine *gin.Engine) {
+	engine.GET(
+		"/analytics/funnel",
+		server.ValidateAdmin,
+		getAnalyticsFunnel,
+	)
+}
+
+func getAnalyticsFunnel(c *gin.Context) {
+	from, to, err := parseRange(c)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	funnel, err := deps.AnalyticsService().Funnel(from, to)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, funnel)
+}
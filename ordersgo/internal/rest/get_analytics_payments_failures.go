@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Pagos rechazados
+//	@Description	Intentos de pago y rechazos del rango, agrupados por código de error y por método de pago. Por defecto los últimos 30 días. Requiere permiso admin.
+//	@Tags			Reportes
+//	@Produce		json
+//	@Param			from			query		string						false	"Desde, incluido (RFC3339 o YYYY-MM-DD)"
+//	@Param			to				query		string						false	"Hasta, excluido (RFC3339 o YYYY-MM-DD)"
+//	@Param			Authorization	header		string						true	"Bearer {token}"
+//	@Success		200				{object}	analytics.PaymentFailures	"Rechazos"
+//	@Failure		400				{object}	rst.ErrorData				"Bad Request"
+//	@Failure		401				{object}	rst.ErrorData				"Unauthorized"
+//	@Failure		500				{object}	rst.ErrorData				"Internal Server Error"
+//	@Router			/analytics/payments/failures [get]
+//
+// Pagos rechazados
+func initGetAnalyticsPaymentsFailures(engine *gin.Engine) {
+	engine.GET(
+		"/analytics/payments/failures",
+		server.ValidateAdmin,
+		getAnalyticsPaymentsFailures,
+	)
+}
+
+func getAnalyticsPaymentsFailures(c *gin.Context) {
+	from, to, err := parseRange(c)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	failures, err := deps.AnalyticsService().PaymentFailures(from, to)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, failures)
+}
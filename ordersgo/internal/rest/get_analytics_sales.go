@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/projections/sales"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Reporte de ventas
+//	@Description	Ordenes colocadas, validadas, pagadas y canceladas, ingreso bruto y neto, reembolsos y tamaño promedio de carrito agrupados por hora, día o mes (UTC). Por defecto los últimos 30 días por día. Requiere permiso admin.
+//	@Tags			Reportes
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			from			query		string			false	"Desde, incluido (RFC3339 o YYYY-MM-DD)"
+//	@Param			to				query		string			false	"Hasta, excluido (RFC3339 o YYYY-MM-DD)"
+//	@Param			granularity		query		string			false	"hour, day o month"
+//	@Param			format			query		string			false	"json o csv"
+//	@Param			Authorization	header		string			true	"Bearer {token}"
+//	@Success		200				{object}	sales.Report	"Reporte"
+//	@Failure		400				{object}	rst.ErrorData	"Bad Request"
+//	@Failure		401				{object}	rst.ErrorData	"Unauthorized"
+//	@Failure		500				{object}	rst.ErrorData	"Internal Server Error"
+//	@Router			/analytics/sales [get]
+//
+// Reporte de ventas
+func initGetAnalyticsSales(engine *gin.Engine) {
+	engine.GET(
+		"/analytics/sales",
+		server.ValidateAdmin,
+		getAnalyticsSales,
+	)
+}
+
+func getAnalyticsSales(c *gin.Context) {
+	from, to, err := parseRange(c)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+	granularity, err := sales.ParseGranularity(c.Query("granularity"))
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		rst.AbortWithError(c, errs.NewValidation().Add("format", "must be json or csv"))
+		return
+	}
+
+	deps := server.GinDi(c)
+	report, err := deps.SalesService().Report(from, to, granularity)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(200, report)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=sales-%s.csv", granularity))
+	c.Status(200)
+	if err := report.WriteCSV(c.Writer); err != nil {
+		deps.Logger().Error(err)
+	}
+}
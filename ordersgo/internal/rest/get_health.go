@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/ordersgo/internal/health"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Liveness
+//	@Description	Indica si el proceso está vivo, no chequea dependencias.
+//	@Tags			Health
+//	@Produce		json
+//	@Success		200	{object}	health.Report	"Vivo"
+//	@Failure		503	{object}	health.Report	"Apagándose"
+//	@Router			/health/live [get]
+//
+// Liveness
+func initGetHealthLive(engine *gin.Engine) {
+	engine.GET(
+		"/health/live",
+		getHealthLive,
+	)
+}
+
+//	@Summary		Readiness
+//	@Description	Chequea Mongo, consumers de Rabbit, servicio de Auth y el retraso de las proyecciones.
+//	@Tags			Health
+//	@Produce		json
+//	@Success		200	{object}	health.Report	"Listo"
+//	@Failure		503	{object}	health.Report	"Alguna dependencia no esta disponible"
+//	@Router			/health/ready [get]
+//
+// Readiness
+func initGetHealthReady(engine *gin.Engine) {
+	engine.GET(
+		"/health/ready",
+		getHealthReady,
+	)
+}
+
+func getHealthLive(c *gin.Context) {
+	report := health.Live()
+	c.JSON(report.HttpStatus(), report)
+}
+
+func getHealthReady(c *gin.Context) {
+	report := health.Ready(server.GinDi(c))
+	c.JSON(report.HttpStatus(), report)
+}
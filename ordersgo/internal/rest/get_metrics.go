@@ -0,0 +1,25 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Métricas
+//	@Description	Métricas del servicio en formato Prometheus.
+//	@Tags			Health
+//	@Produce		plain
+//	@Success		200	"Métricas"
+//	@Router			/metrics [get]
+//
+// Métricas Prometheus
+func initGetMetrics(engine *gin.Engine) {
+	engine.GET(
+		"/metrics",
+		getMetrics,
+	)
+}
+
+func getMetrics(c *gin.Context) {
+	server.GinDi(c).Metrics().Handler().ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Estado de la Orden
+//	@Description	Línea de tiempo de la orden: colocada, validación de cada artículo, validada o inválida, primer pago, pago completo, reembolsos y cancelación.
+//	@Tags			Ordenes
+//	@Accept			json
+//	@Produce		json
+//	@Param			orderId			path		string				true	"ID de orden"
+//	@Param			Authorization	header		string				true	"Bearer {token}"
+//	@Success		200				{object}	status.OrderStatus	"Línea de tiempo"
+//	@Failure		401				{object}	rst.ErrorData		"Unauthorized"
+//	@Failure		404				{object}	rst.ErrorData		"Not Found"
+//	@Failure		500				{object}	rst.ErrorData		"Internal Server Error"
+//	@Router			/orders/{orderId}/status [get]
+//
+// Estado de la Orden
+func initGetOrdersIdStatus(engine *gin.Engine) {
+	engine.GET(
+		"/orders/:orderId/status",
+		server.ValidateAuthentication,
+		getOrderStatus,
+	)
+}
+
+func getOrderStatus(c *gin.Context) {
+	orderId := c.Param("orderId")
+
+	tokenString, err := rst.GetHeaderToken(c)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	user, err := deps.SecurityService().Validate(tokenString)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	status, err := deps.StatusService().FindByOrderId(orderId)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	// Solo el dueño de la orden o un admin
+	if status.UserId != user.ID && !user.HasPermission("admin") {
+		rst.AbortWithError(c, errs.Unauthorized)
+		return
+	}
+
+	c.JSON(200, status)
+}
@@ -28,7 +28,7 @@ func updateOrderById(c *gin.Context) {
 	orderId := c.Param("orderId")
 
 	deps := server.GinDi(c)
-	go deps.ProjectionsService().Update(orderId)
+	deps.ProjectionsService().UpdateAsync(orderId)
 
 	c.JSON(200, "")
 }
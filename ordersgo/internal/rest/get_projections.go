@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Proyecciones
+//	@Description	Versión de cada proyección registrada y la versión con la que está construida. Requiere permiso admin.
+//	@Tags			Proyecciones
+//	@Produce		json
+//	@Param			Authorization	header		string							true	"Bearer {token}"
+//	@Success		200				{array}		projections.ProjectionVersion	"Proyecciones"
+//	@Failure		401				{object}	rst.ErrorData					"Unauthorized"
+//	@Failure		500				{object}	rst.ErrorData					"Internal Server Error"
+//	@Router			/projections [get]
+//
+// Proyecciones
+func initGetProjections(engine *gin.Engine) {
+	engine.GET(
+		"/projections",
+		server.ValidateAdmin,
+		getProjections,
+	)
+}
+
+func getProjections(c *gin.Context) {
+	deps := server.GinDi(c)
+
+	versions, err := deps.ProjectionsService().Versions()
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, versions)
+}
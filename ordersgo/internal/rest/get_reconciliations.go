@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Conciliaciones
+//	@Description	Conciliaciones de pagos de la más reciente a la más antigua, sin el detalle de discrepancias. Requiere permiso admin.
+//	@Tags			Conciliación
+//	@Produce		json
+//	@Param			Authorization	header		string					true	"Bearer {token}"
+//	@Success		200				{array}		reconciliation.Report	"Conciliaciones"
+//	@Failure		401				{object}	rst.ErrorData			"Unauthorized"
+//	@Failure		500				{object}	rst.ErrorData			"Internal Server Error"
+//	@Router			/reconciliations [get]
+//
+// Conciliaciones
+func initGetReconciliations(engine *gin.Engine) {
+	engine.GET(
+		"/reconciliations",
+		server.ValidateAdmin,
+		getReconciliations,
+	)
+}
+
+func getReconciliations(c *gin.Context) {
+	deps := server.GinDi(c)
+
+	reports, err := deps.ReconciliationService().FindAll()
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, reports)
+}
@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Conciliación
+//	@Description	Estado y discrepancias de una conciliación de pagos. Requiere permiso admin.
+//	@Tags			Conciliación
+//	@Produce		json
+//	@Param			id				path		string					true	"ID de la conciliación"
+//	@Param			Authorization	header		string					true	"Bearer {token}"
+//	@Success		200				{object}	reconciliation.Report	"Conciliación"
+//	@Failure		401				{object}	rst.ErrorData			"Unauthorized"
+//	@Failure		404				{object}	rst.ErrorData			"Not Found"
+//	@Failure		500				{object}	rst.ErrorData			"Internal Server Error"
+//	@Router			/reconciliations/{id} [get]
+//
+// Conciliación
+func initGetReconciliationsId(engine *gin.Engine) {
+	engine.GET(
+		"/reconciliations/:id",
+		server.ValidateAdmin,
+		getReconciliationsId,
+	)
+}
+
+func getReconciliationsId(c *gin.Context) {
+	deps := server.GinDi(c)
+
+	report, err := deps.ReconciliationService().FindById(c.Param("id"))
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, report)
+}
@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Estadísticas de ordenes de un usuario
+//	@Description	Estadísticas de ordenes de cualquier usuario. Requiere permiso admin.
+//	@Tags			Clientes
+//	@Produce		json
+//	@Param			userId			path		string					true	"ID de usuario"
+//	@Param			Authorization	header		string					true	"Bearer {token}"
+//	@Success		200				{object}	customer.CustomerStats	"Estadísticas"
+//	@Failure		401				{object}	rst.ErrorData			"Unauthorized"
+//	@Failure		500				{object}	rst.ErrorData			"Internal Server Error"
+//	@Router			/users/{userId}/order-stats [get]
+//
+// Estadísticas de ordenes de un usuario
+func initGetUsersIdOrderStats(engine *gin.Engine) {
+	engine.GET(
+		"/users/:userId/order-stats",
+		server.ValidateAdmin,
+		getUsersIdOrderStats,
+	)
+}
+
+func getUsersIdOrderStats(c *gin.Context) {
+	userId := c.Param("userId")
+	deps := server.GinDi(c)
+
+	stats, err := deps.CustomerService().FindByUserId(userId)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, stats)
+}
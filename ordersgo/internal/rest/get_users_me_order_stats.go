@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Estadísticas de mis ordenes
+//	@Description	Cantidad de ordenes, ordenes por estado, total gastado y reembolsado, valor promedio, primera y última orden y artículos favoritos del usuario logueado.
+//	@Tags			Clientes
+//	@Produce		json
+//	@Param			Authorization	header		string					true	"Bearer {token}"
+//	@Success		200				{object}	customer.CustomerStats	"Estadísticas"
+//	@Failure		401				{object}	rst.ErrorData			"Unauthorized"
+//	@Failure		500				{object}	rst.ErrorData			"Internal Server Error"
+//	@Router			/users/me/order-stats [get]
+//
+// Estadísticas de mis ordenes
+func initGetUsersMeOrderStats(engine *gin.Engine) {
+	engine.GET(
+		"/users/me/order-stats",
+		server.ValidateAuthentication,
+		getUsersMeOrderStats,
+	)
+}
+
+func getUsersMeOrderStats(c *gin.Context) {
+	tokenString, err := rst.GetHeaderToken(c)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	user, err := deps.SecurityService().Validate(tokenString)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	stats, err := deps.CustomerService().FindByUserId(user.ID)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, stats)
+}
@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+type PlaceOrderResponse struct {
+	OrderId string `json:"orderId"`
+}
+
+//	@Summary		Crear una orden
+//	@Description	Crea una orden con el mismo proceso que el mensaje place_order. Sólo un admin puede crear ordenes de otro usuario, cada carrito genera una única orden.
+//	@Tags			Ordenes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string					true	"Bearer {token}"
+//	@Param			Idempotency-Key	header		string					false	"Clave para reintentos, repite la respuesta original"
+//	@Param			body			body		events.PlacedOrderData	true	"Carrito a ordenar"
+//	@Success		200				{object}	PlaceOrderResponse		"Orden creada"
+//	@Failure		400				{object}	errs.ValidationErr		"Bad Request"
+//	@Failure		401				{object}	rst.ErrorData			"Unauthorized"
+//	@Failure		500				{object}	rst.ErrorData			"Internal Server Error"
+//	@Router			/orders [post]
+//
+// Crear una orden
+func initPostOrders(engine *gin.Engine) {
+	engine.POST(
+		"/orders",
+		server.ValidateAuthentication,
+		server.Idempotent,
+		placeOrder,
+	)
+}
+
+func placeOrder(c *gin.Context) {
+	body := events.PlacedOrderData{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	token, err := rst.GetHeaderToken(c)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	user, err := deps.SecurityService().Validate(token)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	if body.UserId != user.ID && !user.HasPermission("admin") {
+		rst.AbortWithError(c, errs.Unauthorized)
+		return
+	}
+
+	event, err := deps.Service().PocessPlaceOrder(&body)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, PlaceOrderResponse{
+		OrderId: event.OrderId,
+	})
+}
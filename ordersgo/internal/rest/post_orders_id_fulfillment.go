@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Envío de la orden
+//	@Description	Registra una transición del envío: packing, shipped (con carrier y trackingNumber), delivered o delivery_failed. El envío empieza con la orden pagada. Requiere permiso admin.
+//	@Tags			Ordenes
+//	@Accept			json
+//	@Produce		json
+//	@Param			orderId			path		string					true	"ID de orden"
+//	@Param			Authorization	header		string					true	"Bearer {token}"
+//	@Param			Idempotency-Key	header		string					false	"Clave para reintentos, repite la respuesta original"
+//	@Param			body			body		events.FulfillmentEvent	true	"Transición del envío"
+//	@Success		200				{object}	events.Event			"Evento fulfillment"
+//	@Failure		400				{object}	errs.ValidationErr		"Bad Request"
+//	@Failure		401				{object}	rst.ErrorData			"Unauthorized"
+//	@Failure		404				{object}	rst.ErrorData			"Not Found"
+//	@Failure		500				{object}	rst.ErrorData			"Internal Server Error"
+//	@Router			/orders/{orderId}/fulfillment [post]
+//
+// Envío de la orden
+func initPostOrdersIdFulfillment(engine *gin.Engine) {
+	engine.POST(
+		"/orders/:orderId/fulfillment",
+		server.ValidateAdmin,
+		server.Idempotent,
+		saveFulfillment,
+	)
+}
+
+func saveFulfillment(c *gin.Context) {
+	body := events.FulfillmentEvent{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	event, err := deps.Service().ProcessFulfillment(c.Param("orderId"), &body)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, event)
+}
@@ -5,20 +5,30 @@ import (
 	"github.com/nmarsollier/commongo/rst"
 	"github.com/nmarsollier/ordersgo/internal/events"
 	"github.com/nmarsollier/ordersgo/internal/rest/server"
+	"github.com/nmarsollier/ordersgo/internal/services"
 )
 
+// SavePaymentRequest pago informado por el cliente
+type SavePaymentRequest struct {
+	Method events.PaymentMethod `json:"method" example:"CREDIT"`
+	Amount float32              `json:"amount" example:"100"`
+}
+
 //	@Summary		Agrega un Pago
-//	@Description	Agrega un Pago
+//	@Description	Agrega un Pago a una orden del usuario. Valida que la orden acepte pagos y que el monto no supere el saldo.
 //	@Tags			Ordenes
 //	@Accept			json
 //	@Produce		json
 //	@Param			orderId			path		string				true	"ID de orden"
 //	@Param			Authorization	header		string				true	"Bearer {token}"
-//	@Param			body			body		events.PaymentEvent	true	"Informacion del pago"
-//	@Success		200				{object}	order.Order			"Ordenes"
+//	@Param			Idempotency-Key	header		string				false	"Clave para reintentos, repite la respuesta original"
+//	@Param			body			body		SavePaymentRequest	true	"Informacion del pago"
+//	@Success		200				{object}	events.Event		"Evento de pago"
 //	@Failure		400				{object}	errs.ValidationErr	"Bad Request"
 //	@Failure		401				{object}	rst.ErrorData		"Unauthorized"
 //	@Failure		404				{object}	rst.ErrorData		"Not Found"
+//	@Failure		409				{object}	rst.ErrorData		"Idempotency-Key en proceso"
+//	@Failure		422				{object}	rst.ErrorData		"Idempotency-Key usada con otro pedido"
 //	@Failure		500				{object}	rst.ErrorData		"Internal Server Error"
 //	@Router			/orders/:orderId/payment [post]
 //
@@ -27,19 +37,38 @@ func initPostPayment(engine *gin.Engine) {
 	engine.POST(
 		"/orders/:orderId/payment",
 		server.ValidateAuthentication,
+		server.Idempotent,
 		savePayment,
 	)
 }
 
 func savePayment(c *gin.Context) {
-	body := events.PaymentEvent{}
+	body := SavePaymentRequest{}
 	if err := c.ShouldBindJSON(&body); err != nil {
 		rst.AbortWithError(c, err)
 		return
 	}
 
+	token, err := rst.GetHeaderToken(c)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
 	deps := server.GinDi(c)
-	event, err := deps.Service().ProcessSavePayment(&body)
+	user, err := deps.SecurityService().Validate(token)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	event, err := deps.Service().ProcessManualPayment(&services.ManualPaymentData{
+		OrderId:        c.Param("orderId"),
+		UserId:         user.ID,
+		Method:         body.Method,
+		Amount:         body.Amount,
+		IdempotencyKey: c.GetHeader("Idempotency-Key"),
+	})
 	if err != nil {
 		rst.AbortWithError(c, err)
 		return
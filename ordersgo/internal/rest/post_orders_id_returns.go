@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+	"github.com/nmarsollier/ordersgo/internal/services"
+)
+
+// ReturnRequest devolución pedida por el cliente
+type ReturnRequest struct {
+	Reason string               `json:"reason" example:"Llegó dañado"`
+	Lines  []*events.ReturnLine `json:"lines"`
+}
+
+//	@Summary		Pedir una devolución
+//	@Description	Abre una devolución de una orden entregada del usuario, con los artículos y cantidades a devolver y el motivo. No se puede devolver más de lo comprado.
+//	@Tags			Ordenes
+//	@Accept			json
+//	@Produce		json
+//	@Param			orderId			path		string				true	"ID de orden"
+//	@Param			Authorization	header		string				true	"Bearer {token}"
+//	@Param			Idempotency-Key	header		string				false	"Clave para reintentos, repite la respuesta original"
+//	@Param			body			body		ReturnRequest		true	"Devolución"
+//	@Success		200				{object}	events.Event		"Evento order_return con el returnId"
+//	@Failure		400				{object}	errs.ValidationErr	"Bad Request"
+//	@Failure		401				{object}	rst.ErrorData		"Unauthorized"
+//	@Failure		404				{object}	rst.ErrorData		"Not Found"
+//	@Failure		500				{object}	rst.ErrorData		"Internal Server Error"
+//	@Router			/orders/{orderId}/returns [post]
+//
+// Pedir una devolución
+func initPostOrdersIdReturns(engine *gin.Engine) {
+	engine.POST(
+		"/orders/:orderId/returns",
+		server.ValidateAuthentication,
+		server.Idempotent,
+		requestReturn,
+	)
+}
+
+func requestReturn(c *gin.Context) {
+	body := ReturnRequest{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	token, err := rst.GetHeaderToken(c)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	user, err := deps.SecurityService().Validate(token)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	event, err := deps.Service().ProcessRequestReturn(&services.ReturnRequestData{
+		OrderId: c.Param("orderId"),
+		UserId:  user.ID,
+		Reason:  body.Reason,
+		Lines:   body.Lines,
+	})
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, event)
+}
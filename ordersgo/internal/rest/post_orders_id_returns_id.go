@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+// ReturnTransitionRequest decisión del admin sobre la devolución
+type ReturnTransitionRequest struct {
+	Status events.ReturnStatus `json:"status" example:"approved"`
+	Reason string              `json:"reason"`
+}
+
+//	@Summary		Avanzar una devolución
+//	@Description	Aprueba (approved) o rechaza (rejected) una devolución pedida, o la da por recibida (received). Al recibirla se pide a payments_node el reembolso del valor de las líneas. Requiere permiso admin.
+//	@Tags			Ordenes
+//	@Accept			json
+//	@Produce		json
+//	@Param			orderId			path		string					true	"ID de orden"
+//	@Param			returnId		path		string					true	"ID de la devolución"
+//	@Param			Authorization	header		string					true	"Bearer {token}"
+//	@Param			Idempotency-Key	header		string					false	"Clave para reintentos, repite la respuesta original"
+//	@Param			body			body		ReturnTransitionRequest	true	"Transición de la devolución"
+//	@Success		200				{object}	events.Event			"Evento order_return"
+//	@Failure		400				{object}	errs.ValidationErr		"Bad Request"
+//	@Failure		401				{object}	rst.ErrorData			"Unauthorized"
+//	@Failure		404				{object}	rst.ErrorData			"Not Found"
+//	@Failure		500				{object}	rst.ErrorData			"Internal Server Error"
+//	@Router			/orders/{orderId}/returns/{returnId} [post]
+//
+// Avanzar una devolución
+func initPostOrdersIdReturnsId(engine *gin.Engine) {
+	engine.POST(
+		"/orders/:orderId/returns/:returnId",
+		server.ValidateAdmin,
+		server.Idempotent,
+		saveReturnTransition,
+	)
+}
+
+func saveReturnTransition(c *gin.Context) {
+	body := ReturnTransitionRequest{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	event, err := deps.Service().ProcessReturnTransition(c.Param("orderId"), &events.ReturnEvent{
+		ReturnId: c.Param("returnId"),
+		Status:   body.Status,
+		Reason:   body.Reason,
+	})
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, event)
+}
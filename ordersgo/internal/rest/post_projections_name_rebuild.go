@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Reconstruir proyección
+//	@Description	Reconstruye la proyección en background con todos los eventos, sin vaciarla. Requiere permiso admin.
+//	@Tags			Proyecciones
+//	@Produce		json
+//	@Param			Authorization	header		string					true	"Bearer {token}"
+//	@Param			Idempotency-Key	header		string					false	"Clave para reintentos, repite la respuesta original"
+//	@Param			name			path		string					true	"Nombre de la proyección"
+//	@Success		202				{object}	RebuildProjectionData	"Rebuild iniciado"
+//	@Failure		400				{object}	rst.ErrorData			"Rebuild en curso"
+//	@Failure		401				{object}	rst.ErrorData			"Unauthorized"
+//	@Failure		404				{object}	rst.ErrorData			"Not Found"
+//	@Router			/projections/{name}/rebuild [post]
+//
+// Reconstruir proyección
+func initPostProjectionsRebuild(engine *gin.Engine) {
+	engine.POST(
+		"/projections/:name/rebuild",
+		server.ValidateAdmin,
+		server.Idempotent,
+		rebuildProjection,
+	)
+}
+
+type RebuildProjectionData struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+}
+
+func rebuildProjection(c *gin.Context) {
+	name := c.Param("name")
+
+	deps := server.GinDi(c)
+	projection, err := deps.ProjectionRegistry().Get(name)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	// El rebuild sigue después de responder, no usa el contexto del request
+	if err := di.NewInjector(deps.Logger()).ProjectionsService().Rebuild(name); err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, RebuildProjectionData{
+		Name:    projection.Name(),
+		Version: projection.Version(),
+	})
+}
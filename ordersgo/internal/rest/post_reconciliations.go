@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/di"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Conciliar pagos
+//	@Description	Compara en background los pagos de las ordenes del rango contra payments_node e informa los faltantes, sobrantes y diferencias de monto o estado. Por defecto los últimos 30 días. Requiere permiso admin.
+//	@Tags			Conciliación
+//	@Produce		json
+//	@Param			from			query		string					false	"Desde, incluido (RFC3339 o YYYY-MM-DD)"
+//	@Param			to				query		string					false	"Hasta, excluido (RFC3339 o YYYY-MM-DD)"
+//	@Param			Authorization	header		string					true	"Bearer {token}"
+//	@Param			Idempotency-Key	header		string					false	"Clave para reintentos, repite la respuesta original"
+//	@Success		202				{object}	reconciliation.Report	"Conciliación iniciada"
+//	@Failure		400				{object}	rst.ErrorData			"Bad Request"
+//	@Failure		401				{object}	rst.ErrorData			"Unauthorized"
+//	@Failure		500				{object}	rst.ErrorData			"Internal Server Error"
+//	@Router			/reconciliations [post]
+//
+// Conciliar pagos
+func initPostReconciliations(engine *gin.Engine) {
+	engine.POST(
+		"/reconciliations",
+		server.ValidateAdmin,
+		server.Idempotent,
+		postReconciliations,
+	)
+}
+
+func postReconciliations(c *gin.Context) {
+	from, to, err := parseRange(c)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	tokenString, err := rst.GetHeaderToken(c)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	report, err := deps.ReconciliationService().Start(from, to)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	// La conciliación sigue después de responder, no usa el contexto del request.
+	// El token del admin se reenvía a payments_node.
+	logger := deps.Logger()
+	running := *report
+	lifecycle.Go(func() {
+		if _, err := di.NewInjector(logger).ReconciliationService().Run(&running, tokenString); err != nil {
+			logger.Error("Reconciliation failed: ", err)
+		}
+	})
+
+	c.JSON(http.StatusAccepted, report)
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/rest/server"
+)
+
+//	@Summary		Datos de entrega
+//	@Description	Cambia los datos de entrega de una orden del usuario, sólo se reemplazan los campos informados. No se pueden cambiar en ordenes canceladas o inválidas.
+//	@Tags			Ordenes
+//	@Accept			json
+//	@Produce		json
+//	@Param			orderId			path		string				true	"ID de orden"
+//	@Param			Authorization	header		string				true	"Bearer {token}"
+//	@Param			Idempotency-Key	header		string				false	"Clave para reintentos, repite la respuesta original"
+//	@Param			body			body		events.Delivery		true	"Datos de entrega"
+//	@Success		200				{object}	events.Event		"Evento address_set"
+//	@Failure		400				{object}	errs.ValidationErr	"Bad Request"
+//	@Failure		401				{object}	rst.ErrorData		"Unauthorized"
+//	@Failure		404				{object}	rst.ErrorData		"Not Found"
+//	@Failure		500				{object}	rst.ErrorData		"Internal Server Error"
+//	@Router			/orders/{orderId}/delivery [put]
+//
+// Datos de entrega
+func initPutOrdersIdDelivery(engine *gin.Engine) {
+	engine.PUT(
+		"/orders/:orderId/delivery",
+		server.ValidateAuthentication,
+		server.Idempotent,
+		setDelivery,
+	)
+}
+
+func setDelivery(c *gin.Context) {
+	body := events.Delivery{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	token, err := rst.GetHeaderToken(c)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	deps := server.GinDi(c)
+	user, err := deps.SecurityService().Validate(token)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	event, err := deps.Service().ProcessSetDelivery(c.Param("orderId"), user.ID, &body)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, event)
+}
@@ -2,23 +2,58 @@ package rest
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/ordersgo/internal/env"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
 	"github.com/nmarsollier/ordersgo/internal/rest/server"
 )
 
 // Start this server
 func Start() {
+	logger := log.Get(env.Get().FluentURL, env.Get().ServerName)
 	engine := server.Router()
 	initRoutes(engine)
-	engine.Run(fmt.Sprintf(":%d", env.Get().Port))
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", env.Get().Port),
+		Handler: engine,
+	}
+	lifecycle.OnShutdown(lifecycle.Servers, "rest", srv.Shutdown)
+
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Error(err)
+		lifecycle.Stop()
+	}
 }
 
 func initRoutes(engine *gin.Engine) {
 	initGetPrdersId(engine)
 	initGetOdersIdUpdate(engine)
+	initGetOrdersIdStatus(engine)
+	initGetUsersMeOrderStats(engine)
+	initGetUsersIdOrderStats(engine)
 	initGetOrders(engine)
+	initPostOrders(engine)
 	initPostPayment(engine)
+	initPutOrdersIdDelivery(engine)
+	initPostOrdersIdFulfillment(engine)
+	initPostOrdersIdReturns(engine)
+	initPostOrdersIdReturnsId(engine)
 	initDeleteOrdersId(engine)
+	initGetHealthLive(engine)
+	initGetHealthReady(engine)
+	initGetMetrics(engine)
+	initGetAnalyticsSales(engine)
+	initGetAnalyticsArticlesTop(engine)
+	initGetAnalyticsArticlesProblems(engine)
+	initGetAnalyticsFunnel(engine)
+	initGetAnalyticsPaymentsFailures(engine)
+	initPostReconciliations(engine)
+	initGetReconciliations(engine)
+	initGetReconciliationsId(engine)
+	initGetProjections(engine)
+	initPostProjectionsRebuild(engine)
 }
@@ -22,6 +22,25 @@ func ValidateAuthentication(c *gin.Context) {
 	c.Set("logger", deps.Logger().WithField(log.LOG_FIELD_USER_ID, user.ID))
 }
 
+// ValidateAdmin igual que ValidateAuthentication, además requiere el permiso admin
+func ValidateAdmin(c *gin.Context) {
+	user, err := validateToken(c)
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return
+	}
+
+	if !user.HasPermission("admin") {
+		c.Error(errs.Unauthorized)
+		c.Abort()
+		return
+	}
+
+	deps := GinDi(c)
+	c.Set("logger", deps.Logger().WithField(log.LOG_FIELD_USER_ID, user.ID))
+}
+
 func validateToken(c *gin.Context) (*security.User, error) {
 	tokenString, err := rst.GetHeaderToken(c)
 	if err != nil {
@@ -15,7 +15,7 @@ func DiInjectorMiddleware() gin.HandlerFunc {
 
 		if !exists {
 			logger := rst.GinLogger(c, env.Get().FluentURL, env.Get().ServerName)
-			deps = di.NewInjector(logger)
+			deps = di.NewInjectorWithContext(c.Request.Context(), logger)
 			c.Set("di", deps)
 		} else {
 			deps = dep_param.(di.Injector)
@@ -8,8 +8,10 @@ import (
 	cors "github.com/itsjamie/gin-cors"
 	"github.com/nmarsollier/commongo/rst"
 	_ "github.com/nmarsollier/ordersgo/docs"
+	"github.com/nmarsollier/ordersgo/internal/env"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 var engine *gin.Engine = nil
@@ -21,13 +23,15 @@ func Router() *gin.Engine {
 
 	engine = gin.Default()
 	engine.Use(gzip.Gzip(gzip.DefaultCompression))
+	engine.Use(otelgin.Middleware(env.Get().ServerName))
 	engine.Use(DiInjectorMiddleware())
+	engine.Use(MetricsMiddleware())
 	engine.Use(rst.ErrorHandler)
 
 	engine.Use(cors.Middleware(cors.Config{
 		Origins:         "*",
 		Methods:         "GET, PUT, POST, DELETE",
-		RequestHeaders:  "Origin, Authorization, Content-Type, Size",
+		RequestHeaders:  "Origin, Authorization, Content-Type, Size, Idempotency-Key",
 		ExposedHeaders:  "",
 		MaxAge:          50 * time.Second,
 		Credentials:     false,
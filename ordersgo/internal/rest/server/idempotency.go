@@ -0,0 +1,87 @@
+package server
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nmarsollier/commongo/rst"
+	"github.com/nmarsollier/commongo/security"
+	"github.com/nmarsollier/ordersgo/internal/idempotency"
+)
+
+// Idempotent repite la respuesta original cuando el cliente reintenta con el mismo Idempotency-Key.
+// Va después de ValidateAuthentication o ValidateAdmin, las claves son por usuario. Sólo se guardan
+// las respuestas exitosas, si la operación falla la clave se libera para poder reintentar.
+func Idempotent(c *gin.Context) {
+	key := c.GetHeader(idempotency.Header)
+	if key == "" {
+		return
+	}
+	if err := idempotency.ValidateKey(key); err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	user := c.MustGet("user").(security.User)
+	key = idempotency.ScopedKey(user.ID, key)
+	hash := idempotency.RequestHash(c.Request.Method, c.Request.URL.Path, string(body))
+
+	service := GinDi(c).IdempotencyService()
+	record, err := service.Begin(key, hash)
+	if err != nil {
+		rst.AbortWithError(c, err)
+		return
+	}
+	if record != nil {
+		c.Header("Idempotent-Replayed", "true")
+		c.Data(record.Status, record.ContentType, []byte(record.Body))
+		c.Abort()
+		return
+	}
+
+	writer := &recordingWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+
+	completed := false
+	defer func() {
+		if !completed {
+			service.Release(key)
+		}
+	}()
+
+	c.Next()
+
+	if len(c.Errors) > 0 || writer.Status() >= 400 {
+		return
+	}
+
+	if err := service.Complete(key, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+		GinDi(c).Logger().Error(err)
+		return
+	}
+	completed = true
+}
+
+// recordingWriter copia la respuesta para guardarla
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
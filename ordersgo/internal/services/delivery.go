@@ -0,0 +1,31 @@
+package services
+
+import (
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+)
+
+// ProcessSetDelivery guarda los cambios de los datos de entrega de una orden del usuario.
+// La orden se arma con sus eventos, no depende de que la proyección esté al día.
+func (s *service) ProcessSetDelivery(orderId string, userId string, data *events.Delivery) (*events.Event, error) {
+	orderEvents, err := s.events.FindByOrderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+	current := order.Project(orderId, orderEvents)
+	if current.UserId == "" {
+		return nil, errs.NotFound
+	}
+	if current.UserId != userId {
+		return nil, errs.Unauthorized
+	}
+
+	if !current.CanChangeDelivery() {
+		return nil, errs.NewValidation().Add("status", "delivery can't be changed in status "+string(current.Status))
+	}
+
+	return s.save(func(eventService events.EventService) (*events.Event, error) {
+		return eventService.SaveAddressSet(orderId, userId, data)
+	})
+}
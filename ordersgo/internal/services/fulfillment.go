@@ -0,0 +1,32 @@
+package services
+
+import (
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+)
+
+// ProcessFulfillment guarda una transición del envío de la orden, llega desde los administradores o
+// desde el servicio de envíos. El envío empieza con la orden pagada y no avanza si se cancela.
+func (s *service) ProcessFulfillment(orderId string, data *events.FulfillmentEvent) (*events.Event, error) {
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+
+	orderEvents, err := s.events.FindByOrderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+	current := order.Project(orderId, orderEvents)
+	if current.UserId == "" {
+		return nil, errs.NotFound
+	}
+
+	if !current.CanFulfill() {
+		return nil, errs.NewValidation().Add("status", "fulfillment can't change in status "+string(current.Status))
+	}
+
+	return s.save(func(eventService events.EventService) (*events.Event, error) {
+		return eventService.SaveFulfillment(orderId, data)
+	})
+}
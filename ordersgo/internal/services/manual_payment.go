@@ -0,0 +1,96 @@
+package services
+
+import (
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+	uuid "github.com/satori/go.uuid"
+)
+
+// balanceTolerance diferencia de redondeo aceptada al comparar el monto con el saldo
+const balanceTolerance = 0.01
+
+// ManualPaymentData pago informado por el cliente por REST o GraphQL
+type ManualPaymentData struct {
+	OrderId string
+	UserId  string
+	Method  events.PaymentMethod
+	Amount  float32
+	// IdempotencyKey opcional, los reintentos con la misma clave devuelven el pago ya registrado
+	IdempotencyKey string
+}
+
+// ProcessManualPayment valida el pago contra la orden y lo guarda con un paymentId generado.
+// La orden se arma con sus eventos, no depende de que la proyección esté al día.
+func (s *service) ProcessManualPayment(data *ManualPaymentData) (*events.Event, error) {
+	if !data.Method.IsValid() {
+		return nil, errs.NewValidation().Add("method", "must be CASH, CREDIT or DEBIT")
+	}
+	if data.Amount <= 0 {
+		return nil, errs.NewValidation().Add("amount", "must be greater than 0")
+	}
+
+	orderEvents, err := s.events.FindByOrderId(data.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	current := order.Project(data.OrderId, orderEvents)
+	if current.UserId == "" {
+		return nil, errs.NotFound
+	}
+	if current.UserId != data.UserId {
+		return nil, errs.Unauthorized
+	}
+
+	paymentId := newPaymentId(data)
+	if existing := registeredPayment(orderEvents, paymentId); existing != nil {
+		s.log.Info("Payment already registered for idempotency key: ", paymentId)
+		return existing, nil
+	}
+
+	if !current.IsPayable() {
+		return nil, errs.NewValidation().Add("status", "order can't be paid in status "+string(current.Status))
+	}
+	if data.Amount > current.Balance()+balanceTolerance {
+		return nil, errs.NewValidation().Add("amount", "exceeds the remaining balance")
+	}
+
+	event, err := s.ProcessSavePayment(&events.PaymentEvent{
+		OrderId:   data.OrderId,
+		Method:    string(data.Method),
+		Amount:    data.Amount,
+		PaymentId: paymentId,
+	})
+	if err != errs.AlreadyExist {
+		return event, err
+	}
+
+	// Un reintento concurrente con la misma clave guardó el pago entre la lectura y el insert
+	orderEvents, err = s.events.FindByOrderId(data.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	if existing := registeredPayment(orderEvents, paymentId); existing != nil {
+		s.log.Info("Payment already registered for idempotency key: ", paymentId)
+		return existing, nil
+	}
+	return nil, errs.AlreadyExist
+}
+
+// registeredPayment el evento inicial del pago, si ya se guardó
+func registeredPayment(orderEvents []*events.Event, paymentId string) *events.Event {
+	for _, e := range orderEvents {
+		if e.Type == events.Payment && e.Payment.PaymentId == paymentId {
+			return e
+		}
+	}
+	return nil
+}
+
+// newPaymentId con clave de idempotencia el id se deriva de la clave, el usuario y la orden
+func newPaymentId(data *ManualPaymentData) string {
+	if data.IdempotencyKey == "" {
+		return uuid.NewV4().String()
+	}
+	return uuid.NewV5(uuid.NamespaceOID, data.UserId+"/"+data.OrderId+"/"+data.IdempotencyKey).String()
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/nmarsollier/commongo/errs"
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
+	"github.com/nmarsollier/ordersgo/internal/rabbit/rbschema"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ReturnRequestData devolución pedida por el cliente por REST o GraphQL
+type ReturnRequestData struct {
+	OrderId string
+	UserId  string
+	Reason  string
+	Lines   []*events.ReturnLine
+}
+
+// ProcessRequestReturn abre una devolución de una orden entregada del usuario, con un returnId generado.
+// Cada artículo se informa una vez y no se puede devolver más de lo comprado, contando las devoluciones
+// abiertas o recibidas.
+func (s *service) ProcessRequestReturn(data *ReturnRequestData) (*events.Event, error) {
+	returnEvent := &events.ReturnEvent{
+		ReturnId: uuid.NewV4().String(),
+		Status:   events.ReturnRequested,
+		UserId:   data.UserId,
+		Reason:   data.Reason,
+		Lines:    data.Lines,
+	}
+	if err := returnEvent.Validate(); err != nil {
+		return nil, err
+	}
+
+	orderEvents, err := s.events.FindByOrderId(data.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	current := order.Project(data.OrderId, orderEvents)
+	if current.UserId == "" {
+		return nil, errs.NotFound
+	}
+	if current.UserId != data.UserId {
+		return nil, errs.Unauthorized
+	}
+
+	if current.Status != order.Delivered {
+		return nil, errs.NewValidation().Add("status", "only delivered orders can be returned")
+	}
+
+	for i, line := range data.Lines {
+		for _, previous := range data.Lines[:i] {
+			if previous.ArticleId == line.ArticleId {
+				return nil, errs.NewValidation().Add(fmt.Sprintf("lines[%d].articleId", i), "duplicated article")
+			}
+		}
+		if current.Article(line.ArticleId) == nil {
+			return nil, errs.NewValidation().Add(fmt.Sprintf("lines[%d].articleId", i), "article not in order")
+		}
+		if line.Quantity > current.Returnable(line.ArticleId) {
+			return nil, errs.NewValidation().Add(fmt.Sprintf("lines[%d].quantity", i), "exceeds the returnable quantity")
+		}
+	}
+
+	return s.save(func(eventService events.EventService) (*events.Event, error) {
+		return eventService.SaveReturn(data.OrderId, returnEvent)
+	})
+}
+
+// ProcessReturnTransition aprueba, rechaza o recibe una devolución. Al recibirla se guarda el valor de cada
+// línea con el precio validado del artículo y se pide el reembolso a payments_node.
+func (s *service) ProcessReturnTransition(orderId string, data *events.ReturnEvent) (*events.Event, error) {
+	if data.Status == events.ReturnRequested {
+		return nil, errs.NewValidation().Add("status", "must be approved, rejected or received")
+	}
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+
+	orderEvents, err := s.events.FindByOrderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+	current := order.Project(orderId, orderEvents)
+	if current.UserId == "" {
+		return nil, errs.NotFound
+	}
+	previous := current.Return(data.ReturnId)
+	if previous == nil {
+		return nil, errs.NotFound
+	}
+
+	transition := &events.ReturnEvent{
+		ReturnId: data.ReturnId,
+		Status:   data.Status,
+		Reason:   data.Reason,
+	}
+	if data.Status == events.ReturnReceived {
+		for _, line := range previous.Lines {
+			amount := current.Article(line.ArticleId).UnitaryPrice * float32(line.Quantity)
+			transition.Lines = append(transition.Lines, &events.ReturnLine{
+				ArticleId: line.ArticleId,
+				Quantity:  line.Quantity,
+				Amount:    amount,
+			})
+			transition.Amount += amount
+		}
+	}
+
+	event, err := s.save(func(eventService events.EventService) (*events.Event, error) {
+		return eventService.SaveReturn(orderId, transition)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// El pedido de reembolso se publica antes de responder, si falla se devuelve el error y el reintento
+	// lo vuelve a publicar mientras payments_node no haya informado el reembolso de la devolución
+	if data.Status == events.ReturnReceived && !isRefunded(orderEvents, data.ReturnId) {
+		s.rrPublisher.Logger().WithField(log.LOG_FIELD_CORRELATION_ID, s.log.CorrelationId())
+		if err := s.rrPublisher.Publish(toRefundRequestData(orderId, previous, event.Return)); err != nil {
+			s.log.Error(err)
+			return nil, err
+		}
+	}
+
+	return event, nil
+}
+
+// isRefunded si ya se guardó el reembolso de la devolución, payments_node lo informa con el returnId como refundId
+func isRefunded(orderEvents []*events.Event, returnId string) bool {
+	for _, e := range orderEvents {
+		if e.Type == events.Refund && e.Refund.RefundId == returnId {
+			return true
+		}
+	}
+	return false
+}
+
+func toRefundRequestData(orderId string, requested *events.ReturnState, received *events.ReturnEvent) *rbschema.RefundRequestData {
+	articles := make([]rbschema.RefundArticleData, len(received.Lines))
+	for index, line := range received.Lines {
+		articles[index] = rbschema.RefundArticleData{
+			ArticleId: line.ArticleId,
+			Quantity:  line.Quantity,
+			Amount:    line.Amount,
+		}
+	}
+
+	return &rbschema.RefundRequestData{
+		OrderId:  orderId,
+		UserId:   requested.UserId,
+		RefundId: received.ReturnId,
+		Amount:   received.Amount,
+		Reason:   requested.Reason,
+		Articles: articles,
+	}
+}
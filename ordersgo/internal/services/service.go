@@ -1,9 +1,13 @@
 package services
 
 import (
+	"github.com/nmarsollier/commongo/errs"
 	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/env"
 	"github.com/nmarsollier/ordersgo/internal/events"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
 	"github.com/nmarsollier/ordersgo/internal/projections"
+	"github.com/nmarsollier/ordersgo/internal/projections/order"
 	"github.com/nmarsollier/ordersgo/internal/rabbit/rbschema"
 )
 
@@ -11,15 +15,27 @@ type Service interface {
 	ProcessArticleData(data *events.ValidationEvent) (*events.Event, error)
 	PocessPlaceOrder(data *events.PlacedOrderData) (*events.Event, error)
 	ProcessSavePayment(data *events.PaymentEvent) (*events.Event, error)
+	ProcessSaveRefund(data *events.RefundEvent) (*events.Event, error)
+	ProcessManualPayment(data *ManualPaymentData) (*events.Event, error)
+	ProcessSetDelivery(orderId string, userId string, data *events.Delivery) (*events.Event, error)
+	ProcessFulfillment(orderId string, data *events.FulfillmentEvent) (*events.Event, error)
+	ProcessRequestReturn(data *ReturnRequestData) (*events.Event, error)
+	ProcessReturnTransition(orderId string, data *events.ReturnEvent) (*events.Event, error)
+	ProcessCancelOrder(event *events.Event) (*events.Event, error)
 }
 
-func NewService(log log.LogRusEntry, events events.EventService, projections projections.ProjectionsService, avPublihser rbschema.ArticleValidationPublisher, plPublisher rbschema.PlacedDataPublisher) Service {
+// Transaction ejecuta fn de forma atómica, los servicios que recibe escriben dentro de la transacción
+type Transaction func(fn func(events events.EventService, projections projections.ProjectionsService) error) error
+
+func NewService(log log.LogRusEntry, events events.EventService, projections projections.ProjectionsService, transaction Transaction, avPublihser rbschema.ArticleValidationPublisher, plPublisher rbschema.PlacedDataPublisher, rrPublisher rbschema.RefundRequestPublisher) Service {
 	return &service{
 		log:         log,
 		events:      events,
 		projections: projections,
+		transaction: transaction,
 		avPublihser: avPublihser,
 		plPublisher: plPublisher,
+		rrPublisher: rrPublisher,
 	}
 }
 
@@ -27,57 +43,145 @@ type service struct {
 	log         log.LogRusEntry
 	events      events.EventService
 	projections projections.ProjectionsService
+	transaction Transaction
 	avPublihser rbschema.ArticleValidationPublisher
 	plPublisher rbschema.PlacedDataPublisher
+	rrPublisher rbschema.RefundRequestPublisher
 }
 
-func (s *service) ProcessArticleData(data *events.ValidationEvent) (*events.Event, error) {
-	event, err := s.events.SaveArticleExist(data)
+// save guarda el evento y actualiza las proyecciones.
+// En modo sync evento y proyecciones se escriben en una misma transacción, al volver
+// las lecturas ya reflejan el evento. En modo async las proyecciones se actualizan en background,
+// con el runner de proyecciones activo es el runner quien proyecta el evento.
+func (s *service) save(save func(events.EventService) (*events.Event, error)) (*events.Event, error) {
+	if env.Get().ProjectionsMode != env.SyncProjections {
+		event, err := save(s.events)
+		if err != nil {
+			return nil, err
+		}
+
+		if !env.Get().ProjectionRunner {
+			s.projections.UpdateAsync(event.OrderId)
+		}
+		return event, nil
+	}
+
+	var event *events.Event
+	err := s.transaction(func(eventService events.EventService, projectionsService projections.ProjectionsService) (err error) {
+		if event, err = save(eventService); err != nil {
+			return err
+		}
+
+		return projectionsService.Update(event.OrderId)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	go s.projections.Update(event.OrderId)
+	return event, nil
+}
+
+func (s *service) ProcessArticleData(data *events.ValidationEvent) (*events.Event, error) {
+	event, err := s.save(func(eventService events.EventService) (*events.Event, error) {
+		return eventService.SaveArticleExist(data)
+	})
+	if err == errs.AlreadyExist {
+		// Otra entrega validó el artículo entre la lectura y el insert, se devuelve esa validación
+		return s.events.SaveArticleExist(data)
+	}
 
 	return event, err
 }
 
 func (s *service) PocessPlaceOrder(data *events.PlacedOrderData) (*events.Event, error) {
-	event, err := s.events.SavePlaceOrder(data)
+	event, err := s.save(func(eventService events.EventService) (*events.Event, error) {
+		return eventService.SavePlaceOrder(data)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	go s.projections.Update(event.OrderId)
-
 	s.plPublisher.Logger().WithField(log.LOG_FIELD_CORRELATION_ID, s.log.CorrelationId())
-	go s.plPublisher.Publish(toPlaceData(event))
+	placeData := toPlaceData(event)
+	lifecycle.Go(func() {
+		s.plPublisher.Publish(placeData)
+	})
 
 	s.avPublihser.Logger().WithField(log.LOG_FIELD_CORRELATION_ID, s.log.CorrelationId())
 	for _, article := range event.PlaceEvent.Articles {
-		go s.avPublihser.PublishForResult(
-			&rbschema.ArticleValidationData{
-				ReferenceId: event.OrderId,
-				ArticleId:   article.ArticleId,
-			},
-			"article_exist",
-			"order_article_exist",
-		)
+		validationData := &rbschema.ArticleValidationData{
+			ReferenceId: event.OrderId,
+			ArticleId:   article.ArticleId,
+		}
+		lifecycle.Go(func() {
+			s.avPublihser.PublishForResult(
+				validationData,
+				"article_exist",
+				"order_article_exist",
+			)
+		})
 	}
 
 	return event, err
 }
 
 func (s *service) ProcessSavePayment(data *events.PaymentEvent) (*events.Event, error) {
-	event, err := s.events.SavePayment(data)
+	event, err := s.save(func(eventService events.EventService) (*events.Event, error) {
+		return eventService.SavePayment(data)
+	})
 	if err != nil {
 		s.log.Error(err)
 		return nil, err
 	}
 
-	go s.projections.Update(event.OrderId)
+	return event, nil
+}
 
-	return event, err
+// refundRetries veces que se vuelve a validar un reembolso que compitió con otro del mismo pago
+const refundRetries = 3
+
+func (s *service) ProcessSaveRefund(data *events.RefundEvent) (*events.Event, error) {
+	event, err := s.save(func(eventService events.EventService) (*events.Event, error) {
+		return eventService.SaveRefund(data)
+	})
+	for retry := 0; err == errs.AlreadyExist && retry < refundRetries; retry++ {
+		// Otro reembolso del pago se guardó entre la lectura y el insert, se valida contra lo que queda
+		event, err = s.save(func(eventService events.EventService) (*events.Event, error) {
+			return eventService.SaveRefund(data)
+		})
+	}
+	if err != nil {
+		s.log.Error(err)
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// ProcessCancelOrder guarda la cancelación si la orden todavía se puede cancelar.
+// La orden se arma con sus eventos, la proyección puede no tener todavía un despacho reciente.
+func (s *service) ProcessCancelOrder(cancel *events.Event) (*events.Event, error) {
+	orderEvents, err := s.events.FindByOrderId(cancel.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	current := order.Project(cancel.OrderId, orderEvents)
+	if current.IsShipped() {
+		return nil, errs.NewValidation().Add("status", "order was already shipped, it can only be returned")
+	}
+	if !current.CanCancel() {
+		return nil, errs.NewValidation().Add("status", "order can't be canceled in status "+string(current.Status))
+	}
+
+	event, err := s.save(func(eventService events.EventService) (*events.Event, error) {
+		return eventService.Save(cancel)
+	})
+	if err != nil {
+		s.log.Error(err)
+		return nil, err
+	}
+
+	return event, nil
 }
 
 func toPlaceData(event *events.Event) *rbschema.OrderPlacedData {
@@ -95,5 +199,34 @@ func toPlaceData(event *events.Event) *rbschema.OrderPlacedData {
 		CartId:   event.PlaceEvent.CartId,
 		UserId:   event.PlaceEvent.UserId,
 		Articles: articles,
+		Delivery: toDeliveryData(event.PlaceEvent.Delivery),
+	}
+}
+
+func toDeliveryData(delivery *events.Delivery) *rbschema.DeliveryPlacedData {
+	if delivery == nil {
+		return nil
+	}
+
+	return &rbschema.DeliveryPlacedData{
+		ShippingAddress: toAddressData(delivery.ShippingAddress),
+		BillingAddress:  toAddressData(delivery.BillingAddress),
+		ContactPhone:    delivery.ContactPhone,
+		Instructions:    delivery.Instructions,
+	}
+}
+
+func toAddressData(address *events.Address) *rbschema.AddressPlacedData {
+	if address == nil {
+		return nil
+	}
+
+	return &rbschema.AddressPlacedData{
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		State:      address.State,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
 	}
 }
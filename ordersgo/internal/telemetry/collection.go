@@ -0,0 +1,85 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/db"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceCollection registra un span por cada operación sobre la colección.
+// Los repositorios no reciben contexto, por eso las operaciones se ejecutan con ctx,
+// el contexto de la operación que creó el injector (traza y sesión de transacción).
+// La cancelación de ctx no se propaga, las proyecciones siguen después de la respuesta.
+func TraceCollection(ctx context.Context, name string, collection db.Collection) db.Collection {
+	return &tracedCollection{
+		ctx:        ctx,
+		name:       name,
+		collection: collection,
+	}
+}
+
+type tracedCollection struct {
+	ctx        context.Context
+	name       string
+	collection db.Collection
+}
+
+func (c *tracedCollection) FindOne(_ context.Context, filter interface{}, v interface{}) (err error) {
+	ctx, span := c.start("findOne")
+	defer func() { end(span, err) }()
+
+	return c.collection.FindOne(ctx, filter, v)
+}
+
+func (c *tracedCollection) InsertOne(_ context.Context, document interface{}) (id interface{}, err error) {
+	ctx, span := c.start("insert")
+	defer func() { end(span, err) }()
+
+	return c.collection.InsertOne(ctx, document)
+}
+
+func (c *tracedCollection) UpdateOne(_ context.Context, filter interface{}, update interface{}, optn *options.UpdateOptions) (modified int64, err error) {
+	ctx, span := c.start("update")
+	defer func() { end(span, err) }()
+
+	return c.collection.UpdateOne(ctx, filter, update, optn)
+}
+
+func (c *tracedCollection) Find(_ context.Context, filter interface{}) (cur db.Cursor, err error) {
+	ctx, span := c.start("find")
+	defer func() { end(span, err) }()
+
+	return c.collection.Find(ctx, filter)
+}
+
+func (c *tracedCollection) ReplaceOne(_ context.Context, filter interface{}, replacement interface{}) (modified int64, err error) {
+	ctx, span := c.start("replace")
+	defer func() { end(span, err) }()
+
+	return c.collection.ReplaceOne(ctx, filter, replacement)
+}
+
+func (c *tracedCollection) start(operation string) (context.Context, trace.Span) {
+	return Tracer().Start(context.WithoutCancel(c.ctx), operation+" "+c.name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMongoDB,
+			semconv.DBCollectionName(c.name),
+			semconv.DBOperationName(operation),
+		),
+	)
+}
+
+// end cierra el span, no encontrar un documento no es un error de la operación
+func end(span trace.Span, err error) {
+	if err != nil && err != mongo.ErrNoDocuments {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
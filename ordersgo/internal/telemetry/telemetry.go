@@ -0,0 +1,67 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/nmarsollier/commongo/log"
+	"github.com/nmarsollier/ordersgo/internal/env"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/nmarsollier/ordersgo"
+
+// Init configura el propagador W3C y, si hay un collector configurado, el exporter OTLP.
+// Sin OTEL_URL los spans no se exportan pero el trace context se sigue propagando.
+func Init(logger log.LogRusEntry) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if env.Get().OtelURL == "" {
+		return
+	}
+
+	exporter, err := otlptracehttp.New(
+		context.Background(),
+		otlptracehttp.WithEndpoint(env.Get().OtelURL),
+		otlptracehttp.WithInsecure(),
+	)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(env.Get().ServerName),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	// Se cierra al final para exportar los spans del apagado
+	lifecycle.OnShutdown(lifecycle.Connections, "telemetry", provider.Shutdown)
+}
+
+// Tracer del servicio
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// TraceId devuelve el trace id del contexto, vacío si no hay un span activo
+func TraceId(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
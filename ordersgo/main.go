@@ -1,12 +1,16 @@
 package main
 
 import (
+	"time"
+
 	"github.com/nmarsollier/commongo/log"
 	"github.com/nmarsollier/ordersgo/internal/di"
 	"github.com/nmarsollier/ordersgo/internal/env"
 	server "github.com/nmarsollier/ordersgo/internal/graph"
+	"github.com/nmarsollier/ordersgo/internal/lifecycle"
 	"github.com/nmarsollier/ordersgo/internal/rabbit"
 	"github.com/nmarsollier/ordersgo/internal/rest"
+	"github.com/nmarsollier/ordersgo/internal/telemetry"
 )
 
 //	@title			OrdersGo
@@ -22,7 +26,19 @@ import (
 func main() {
 	dedps := di.NewInjector(log.Get(env.Get().FluentURL, env.Get().ServerName))
 
+	telemetry.Init(dedps.Logger())
+
 	go rabbit.Init(dedps)
+	if env.Get().ProjectionRunner {
+		if env.Get().ProjectionsMode == env.SyncProjections {
+			// En modo sync quien escribe el evento ya lo proyectó en la misma transacción
+			dedps.Logger().Info("PROJECTION_RUNNER is ignored with PROJECTIONS_MODE=sync")
+		} else {
+			go dedps.ProjectionRunner().Start()
+		}
+	}
 	go server.Start()
-	rest.Start()
+	go rest.Start()
+
+	lifecycle.Run(dedps.Logger(), time.Duration(env.Get().ShutdownTimeout)*time.Second)
 }